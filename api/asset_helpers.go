@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"platform-go-challenge/api/model"
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseIDParam reads the :id path parameter and writes a 400 response if it
+// is not a valid ID
+func parseIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		model.ResponseError(c, models.InvalidField("id", "invalid ID %q", c.Param("id")), "Invalid ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// notFoundOrError writes a 404 response with notFound if err is
+// repository.ErrNotFound and the response of err otherwise, using failed as
+// the message of unexpected errors
+func notFoundOrError(c *gin.Context, err error, notFound, failed string) {
+	if errors.Is(err, repository.ErrNotFound) {
+		err = repository.ErrNotFound.Withf("%s", notFound)
+	}
+	model.ResponseError(c, err, failed)
+}
+
+// deleteAsset deletes the asset identified by the :id path parameter with
+// the given repository delete, which applies the delete policy to its stars
+func deleteAsset(c *gin.Context, assetType models.AssetType, del func(ctx context.Context, id uint) error) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := del(c.Request.Context(), id); err != nil {
+		notFoundOrError(c, err, fmt.Sprintf("%s not found", assetType), fmt.Sprintf("Failed to delete %s", assetType))
+		return
+	}
+	model.ResponseJSON(c, http.StatusOK, fmt.Sprintf("%s deleted successfully", assetType), nil)
+}
@@ -1,81 +1,173 @@
 package api
 
 import (
-	"log"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"platform-go-challenge/api/model"
-	"platform-go-challenge/db"
 	"platform-go-challenge/models"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CreateAudience(c *gin.Context) {
+func (h *Handler) CreateAudience(c *gin.Context) {
 	var audience models.Audience
 
 	//bind the request body
 	if err := c.ShouldBindJSON(&audience); err != nil {
-		model.ResponseJSON(c, http.StatusBadRequest, "Invalid input", nil)
+		model.ResponseError(c, bindingError(err), "Invalid input")
 		return
 	}
-	db.GormDB.Create(&audience)
-	model.ResponseJSON(c, http.StatusCreated, "Audience created successfully", audience)
+	if err := audience.Validate(); err != nil {
+		model.ResponseError(c, err, "Invalid Audience")
+		return
+	}
+	if err := h.Audiences.Create(c.Request.Context(), &audience); err != nil {
+		model.ResponseError(c, err, "Failed to create Audience")
+		return
+	}
+	linked, err := h.linkAudience(c.Request.Context(), audience)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to load Audience links")
+		return
+	}
+	model.ResponseJSON(c, http.StatusCreated, "Audience created successfully", linked)
 }
 
-func GetAudiences(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+func (h *Handler) GetAudiences(c *gin.Context) {
+	page, ok := parsePageRequest(c)
+	if !ok {
+		return
 	}
 
-	var audiences []models.Audience
-	db.GormDB.Find(&audiences)
-	model.ResponseJSON(c, http.StatusOK, "Audiences retrieved successfully", audiences)
+	result, err := h.Audiences.List(c.Request.Context(), page)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to retrieve Audiences")
+		return
+	}
+	linked, err := h.linkAudiences(c.Request.Context(), result.Items)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to load Audience links")
+		return
+	}
+	model.ResponseJSON(c, http.StatusOK, "Audiences retrieved successfully", models.Page[models.LinkedAudience]{Items: linked, PageInfo: result.PageInfo})
 }
 
-func GetAudience(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+func (h *Handler) GetAudience(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
 	}
 
-	var audience models.Audience
-	if err := db.GormDB.First(&audience, c.Param("id")).Error; err != nil {
-		model.ResponseJSON(c, http.StatusNotFound, "Audience not found", nil)
+	audience, err := h.Audiences.Get(c.Request.Context(), id)
+	if err != nil {
+		notFoundOrError(c, err, "Audience not found", "Failed to retrieve Audience")
+		return
+	}
+	linked, err := h.linkAudience(c.Request.Context(), audience)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to load Audience links")
 		return
 	}
-	model.ResponseJSON(c, http.StatusOK, "Audience retrieved successfully", audience)
+	model.ResponseJSON(c, http.StatusOK, "Audience retrieved successfully", linked)
 }
 
-func UpdateAudience(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+func (h *Handler) UpdateAudience(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
 	}
 
-	var audience models.Audience
-	if err := db.GormDB.First(&audience, c.Param("id")).Error; err != nil {
-		model.ResponseJSON(c, http.StatusNotFound, "Audience not found", nil)
+	audience, err := h.Audiences.Get(c.Request.Context(), id)
+	if err != nil {
+		notFoundOrError(c, err, "Audience not found", "Failed to retrieve Audience")
 		return
 	}
 
 	// bind the request body
 	if err := c.ShouldBindJSON(&audience); err != nil {
-		model.ResponseJSON(c, http.StatusBadRequest, "Invalid input", nil)
+		model.ResponseError(c, bindingError(err), "Invalid input")
+		return
+	}
+	audience.ID = id
+	if err := audience.Validate(); err != nil {
+		model.ResponseError(c, err, "Invalid Audience")
+		return
+	}
+
+	if err := h.Audiences.Update(c.Request.Context(), &audience); err != nil {
+		notFoundOrError(c, err, "Audience not found", "Failed to update Audience")
 		return
 	}
+	linked, err := h.linkAudience(c.Request.Context(), audience)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to load Audience links")
+		return
+	}
+	model.ResponseJSON(c, http.StatusOK, "Audience updated successfully", linked)
+}
 
-	db.GormDB.Save(&audience)
-	model.ResponseJSON(c, http.StatusOK, "Audience updated successfully", audience)
+func (h *Handler) DeleteAudience(c *gin.Context) {
+	deleteAsset(c, models.AssetTypeAudience, h.Audiences.Delete)
 }
 
-func DeleteAudience(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+// GetAudienceSize returns the number of respondents matching an audience
+func (h *Handler) GetAudienceSize(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
 	}
 
-	var audience models.Audience
-	if err := db.GormDB.Delete(&audience, c.Param("id")).Error; err != nil {
-		model.ResponseJSON(c, http.StatusNotFound, "Audience not found", nil)
+	audience, err := h.Audiences.Get(c.Request.Context(), id)
+	if err != nil {
+		notFoundOrError(c, err, "Audience not found", "Failed to retrieve Audience")
+		return
+	}
+
+	size, err := h.AudienceSizes.Size(c.Request.Context(), audience)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to size Audience")
+		return
+	}
+	model.ResponseJSON(c, http.StatusOK, "Audience sized successfully", size)
+}
+
+// CompareAudiences returns the overlap of every pair of the audiences listed
+// in the ids query parameter, e.g. ids=1,2,3
+func (h *Handler) CompareAudiences(c *gin.Context) {
+	var ids []uint
+	for i, value := range strings.Split(c.Query("ids"), ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			model.ResponseError(c, models.InvalidField(fmt.Sprintf("ids[%d]", i), "invalid ID %q", value), "Invalid ID")
+			return
+		}
+		ids = append(ids, uint(id))
+	}
+	if err := models.ValidateComparedAudiences(ids); err != nil {
+		model.ResponseError(c, err, "Invalid comparison")
+		return
+	}
+
+	audiences := make([]models.Audience, len(ids))
+	for i, id := range ids {
+		audience, err := h.Audiences.Get(c.Request.Context(), id)
+		if err != nil {
+			notFoundOrError(c, err, fmt.Sprintf("Audience %d not found", id), "Failed to retrieve Audience")
+			return
+		}
+		audiences[i] = audience
+	}
+
+	comparison, err := h.AudienceSizes.Compare(c.Request.Context(), audiences)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to compare Audiences")
 		return
 	}
-	model.ResponseJSON(c, http.StatusOK, "Audience deleted successfully", nil)
+	model.ResponseJSON(c, http.StatusOK, "Audiences compared successfully", comparison)
 }
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"platform-go-challenge/api/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Availability reports whether a dependency of the API can serve requests
+type Availability interface {
+	// Available returns nil, or an error such as repository.ErrUnavailable
+	// carrying a retry hint
+	Available() error
+}
+
+// RequireAvailable answers requests with 503 and a Retry-After header while
+// the dependency is unavailable, instead of letting every handler fail on it
+func RequireAvailable(dependency Availability) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := dependency.Available(); err != nil {
+			model.ResponseError(c, err, "Service unavailable, retry later")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
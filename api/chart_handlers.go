@@ -1,81 +1,173 @@
 package api
 
 import (
-	"log"
 	"net/http"
 
 	"platform-go-challenge/api/model"
-	"platform-go-challenge/db"
 	"platform-go-challenge/models"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CreateChart(c *gin.Context) {
+func (h *Handler) CreateChart(c *gin.Context) {
 	var chart models.Chart
 
 	//bind the request body
 	if err := c.ShouldBindJSON(&chart); err != nil {
-		model.ResponseJSON(c, http.StatusBadRequest, "Invalid input", nil)
+		model.ResponseError(c, bindingError(err), "Invalid input")
 		return
 	}
-	db.GormDB.Create(&chart)
-	model.ResponseJSON(c, http.StatusCreated, "Chart created successfully", chart)
+	// only generated charts have a provenance
+	chart.Provenance = nil
+	if err := chart.Validate(); err != nil {
+		model.ResponseError(c, err, "Invalid Chart")
+		return
+	}
+	if err := h.Charts.Create(c.Request.Context(), &chart); err != nil {
+		model.ResponseError(c, err, "Failed to create Chart")
+		return
+	}
+	linked, err := h.linkChart(c.Request.Context(), chart)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to load Chart links")
+		return
+	}
+	model.ResponseJSON(c, http.StatusCreated, "Chart created successfully", linked)
 }
 
-func GetCharts(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+func (h *Handler) GetCharts(c *gin.Context) {
+	page, ok := parsePageRequest(c)
+	if !ok {
+		return
 	}
 
-	var charts []models.Chart
-	db.GormDB.Find(&charts)
-	model.ResponseJSON(c, http.StatusOK, "Charts retrieved successfully", charts)
+	result, err := h.Charts.List(c.Request.Context(), page)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to retrieve Charts")
+		return
+	}
+	linked, err := h.linkCharts(c.Request.Context(), result.Items)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to load Chart links")
+		return
+	}
+	model.ResponseJSON(c, http.StatusOK, "Charts retrieved successfully", models.Page[models.LinkedChart]{Items: linked, PageInfo: result.PageInfo})
 }
 
-func GetChart(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+func (h *Handler) GetChart(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
 	}
 
-	var chart models.Chart
-	if err := db.GormDB.First(&chart, c.Param("id")).Error; err != nil {
-		model.ResponseJSON(c, http.StatusNotFound, "Chart not found", nil)
+	chart, err := h.Charts.Get(c.Request.Context(), id)
+	if err != nil {
+		notFoundOrError(c, err, "Chart not found", "Failed to retrieve Chart")
+		return
+	}
+	linked, err := h.linkChart(c.Request.Context(), chart)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to load Chart links")
 		return
 	}
-	model.ResponseJSON(c, http.StatusOK, "Chart retrieved successfully", chart)
+	model.ResponseJSON(c, http.StatusOK, "Chart retrieved successfully", linked)
 }
 
-func UpdateChart(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+func (h *Handler) UpdateChart(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
 	}
 
-	var chart models.Chart
-	if err := db.GormDB.First(&chart, c.Param("id")).Error; err != nil {
-		model.ResponseJSON(c, http.StatusNotFound, "Chart not found", nil)
+	chart, err := h.Charts.Get(c.Request.Context(), id)
+	if err != nil {
+		notFoundOrError(c, err, "Chart not found", "Failed to retrieve Chart")
 		return
 	}
 
-	// bind the request body
+	// bind the request body, series are replaced only when given
+	existingSeries, provenance := chart.Series, chart.Provenance
+	chart.Series = nil
 	if err := c.ShouldBindJSON(&chart); err != nil {
-		model.ResponseJSON(c, http.StatusBadRequest, "Invalid input", nil)
+		model.ResponseError(c, bindingError(err), "Invalid input")
+		return
+	}
+	chart.ID, chart.Provenance = id, provenance
+	if chart.Series == nil {
+		chart.Series = existingSeries
+	}
+	if err := chart.Validate(); err != nil {
+		model.ResponseError(c, err, "Invalid Chart")
 		return
 	}
 
-	db.GormDB.Save(&chart)
-	model.ResponseJSON(c, http.StatusOK, "Chart updated successfully", chart)
+	if err := h.Charts.Update(c.Request.Context(), &chart); err != nil {
+		notFoundOrError(c, err, "Chart not found", "Failed to update Chart")
+		return
+	}
+	linked, err := h.linkChart(c.Request.Context(), chart)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to load Chart links")
+		return
+	}
+	model.ResponseJSON(c, http.StatusOK, "Chart updated successfully", linked)
+}
+
+func (h *Handler) DeleteChart(c *gin.Context) {
+	deleteAsset(c, models.AssetTypeChart, h.Charts.Delete)
 }
 
-func DeleteChart(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+// GenerateChart stores a chart breaking the audience identified by the :id
+// path parameter down by the dimension of the request body
+func (h *Handler) GenerateChart(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
 	}
 
-	var chart models.Chart
-	if err := db.GormDB.Delete(&chart, c.Param("id")).Error; err != nil {
-		model.ResponseJSON(c, http.StatusNotFound, "Chart not found", nil)
+	var input struct {
+		Dimension string `json:"dimension" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		model.ResponseError(c, bindingError(err), "Invalid input")
+		return
+	}
+	dimension, err := models.ParseDimension(input.Dimension)
+	if err != nil {
+		model.ResponseError(c, err, "Invalid dimension")
+		return
+	}
+
+	chart, err := h.ChartGenerator.Generate(c.Request.Context(), id, dimension)
+	if err != nil {
+		notFoundOrError(c, err, "Audience not found", "Failed to generate Chart")
+		return
+	}
+	linked, err := h.linkChart(c.Request.Context(), chart)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to load Chart links")
+		return
+	}
+	model.ResponseJSON(c, http.StatusCreated, "Chart generated successfully", linked)
+}
+
+// RefreshChart generates the series of the chart identified by the :id path
+// parameter again from its audience and the current respondents
+func (h *Handler) RefreshChart(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	chart, err := h.ChartGenerator.Refresh(c.Request.Context(), id)
+	if err != nil {
+		notFoundOrError(c, err, "Chart not found", "Failed to refresh Chart")
+		return
+	}
+	linked, err := h.linkChart(c.Request.Context(), chart)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to load Chart links")
 		return
 	}
-	model.ResponseJSON(c, http.StatusOK, "Chart deleted successfully", nil)
+	model.ResponseJSON(c, http.StatusOK, "Chart refreshed successfully", linked)
 }
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"platform-go-challenge/models"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report invalid fields by their JSON names
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(models.JSONFieldName)
+	}
+}
+
+// bindingError turns a failed request body binding into a validation error
+// listing the invalid fields
+func bindingError(err error) error {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+
+	switch {
+	case errors.As(err, &validationErrs):
+		return models.ValidationError(models.FieldErrors(validationErrs)...)
+	case errors.As(err, &typeErr):
+		return models.InvalidField(typeErr.Field, "must be a %s", typeErr.Type.Kind())
+	case errors.As(err, &syntaxErr):
+		return models.InvalidField("body", "invalid JSON at offset %d", syntaxErr.Offset)
+	case errors.Is(err, io.EOF):
+		return models.InvalidField("body", "is required")
+	}
+	// other binding failures, such as a field type with its own decoding
+	// rules, carry a message describing the problem
+	return models.InvalidField("body", "%s", err.Error())
+}
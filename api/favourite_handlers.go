@@ -0,0 +1,144 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"platform-go-challenge/api/model"
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetUserFavourites returns a page of the stars of a user with the starred
+// assets embedded, ordered by the time they were starred (oldest first)
+func (h *Handler) GetUserFavourites(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		model.ResponseError(c, models.InvalidField("userId", "invalid ID %q", c.Param("userId")), "Invalid user ID")
+		return
+	}
+
+	page, ok := parsePageRequest(c)
+	if !ok {
+		return
+	}
+
+	favourites, err := h.Favourites.ListByUser(c.Request.Context(), uint(userID), page)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to retrieve favourites")
+		return
+	}
+	// favourites are cached with the templates of insights, render them now
+	for i, favourite := range favourites.Items {
+		if insight, ok := favourite.Asset.(models.Insight); ok {
+			if favourites.Items[i].Asset, err = h.renderInsight(c.Request.Context(), insight); err != nil {
+				model.ResponseError(c, err, "Failed to render Insight")
+				return
+			}
+		}
+	}
+	model.ResponseJSON(c, http.StatusOK, "Favourites retrieved successfully", favourites)
+}
+
+// StarFavourite idempotently stars an asset for a user. It responds with 201
+// when the star is new and 200 when the asset was already starred.
+func (h *Handler) StarFavourite(c *gin.Context) {
+	userID, assetType, assetID, ok := parseFavouriteParams(c)
+	if !ok {
+		return
+	}
+
+	star, created, err := h.Stars.Star(c.Request.Context(), userID, assetType, assetID)
+	if err != nil {
+		// the asset is the resource identified by the path
+		if errors.Is(err, repository.ErrAssetNotFound) {
+			err = repository.ErrNotFound.Withf("%s", err.Error())
+		}
+		model.ResponseError(c, err, "Failed to star asset")
+		return
+	}
+
+	status := models.StarStatus{UserID: userID, Type: assetType, AssetID: assetID, Starred: true, Star: &star}
+	if created {
+		model.ResponseJSON(c, http.StatusCreated, "Asset starred successfully", status)
+		return
+	}
+	model.ResponseJSON(c, http.StatusOK, "Asset already starred", status)
+}
+
+// UnstarFavourite idempotently removes the star of a user on an asset
+func (h *Handler) UnstarFavourite(c *gin.Context) {
+	userID, assetType, assetID, ok := parseFavouriteParams(c)
+	if !ok {
+		return
+	}
+
+	removed, err := h.Stars.Unstar(c.Request.Context(), userID, assetType, assetID)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to unstar asset")
+		return
+	}
+
+	status := models.StarStatus{UserID: userID, Type: assetType, AssetID: assetID, Starred: false}
+	if removed {
+		model.ResponseJSON(c, http.StatusOK, "Asset unstarred successfully", status)
+		return
+	}
+	model.ResponseJSON(c, http.StatusOK, "Asset was not starred", status)
+}
+
+// favouriteDescriptionRequest is the body of UpdateFavouriteDescription
+type favouriteDescriptionRequest struct {
+	Description *string `json:"description" binding:"required"`
+}
+
+// UpdateFavouriteDescription sets the user's description on a starred asset
+func (h *Handler) UpdateFavouriteDescription(c *gin.Context) {
+	userID, assetType, assetID, ok := parseFavouriteParams(c)
+	if !ok {
+		return
+	}
+
+	var request favouriteDescriptionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		model.ResponseError(c, bindingError(err), "Invalid input")
+		return
+	}
+
+	star, err := h.Stars.UpdateDescription(c.Request.Context(), userID, assetType, assetID, *request.Description)
+	if err != nil {
+		if errors.Is(err, repository.ErrStarNotFound) {
+			err = repository.ErrStarNotFound.Withf("Favourite not found")
+		}
+		model.ResponseError(c, err, "Failed to update favourite")
+		return
+	}
+	model.ResponseJSON(c, http.StatusOK, "Favourite updated successfully", star)
+}
+
+// parseFavouriteParams reads the :userId, :type and :assetId path parameters
+// and writes a 400 response if any of them is invalid
+func parseFavouriteParams(c *gin.Context) (userID uint, assetType models.AssetType, assetID uint, ok bool) {
+	parsedUserID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		model.ResponseError(c, models.InvalidField("userId", "invalid ID %q", c.Param("userId")), "Invalid user ID")
+		return 0, "", 0, false
+	}
+
+	assetType, err = models.ParseAssetType(c.Param("type"))
+	if err != nil {
+		model.ResponseError(c, err, "Invalid asset type")
+		return 0, "", 0, false
+	}
+
+	parsedAssetID, err := strconv.ParseUint(c.Param("assetId"), 10, 64)
+	if err != nil {
+		model.ResponseError(c, models.InvalidField("assetId", "invalid ID %q", c.Param("assetId")), "Invalid asset ID")
+		return 0, "", 0, false
+	}
+
+	return uint(parsedUserID), assetType, uint(parsedAssetID), true
+}
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"platform-go-challenge/repository"
+)
+
+// Handler serves the REST API on top of the storage repositories
+type Handler struct {
+	repository.Repositories
+}
+
+// NewHandler returns a Handler using the given repositories
+func NewHandler(repos repository.Repositories) *Handler {
+	return &Handler{Repositories: repos}
+}
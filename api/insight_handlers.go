@@ -1,81 +1,131 @@
 package api
 
 import (
-	"log"
+	"context"
 	"net/http"
 
 	"platform-go-challenge/api/model"
-	"platform-go-challenge/db"
 	"platform-go-challenge/models"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CreateInsight(c *gin.Context) {
+func (h *Handler) CreateInsight(c *gin.Context) {
 	var insight models.Insight
 
 	//bind the request body
 	if err := c.ShouldBindJSON(&insight); err != nil {
-		model.ResponseJSON(c, http.StatusBadRequest, "Invalid input", nil)
+		model.ResponseError(c, bindingError(err), "Invalid input")
 		return
 	}
-	db.GormDB.Create(&insight)
-	model.ResponseJSON(c, http.StatusCreated, "Insight created successfully", insight)
+	if err := insight.Validate(); err != nil {
+		model.ResponseError(c, err, "Invalid Insight")
+		return
+	}
+	if err := h.Insights.Create(c.Request.Context(), &insight); err != nil {
+		model.ResponseError(c, err, "Failed to create Insight")
+		return
+	}
+	rendered, err := h.renderInsight(c.Request.Context(), insight)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to render Insight")
+		return
+	}
+	model.ResponseJSON(c, http.StatusCreated, "Insight created successfully", rendered)
 }
 
-func GetInsights(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+func (h *Handler) GetInsights(c *gin.Context) {
+	page, ok := parsePageRequest(c)
+	if !ok {
+		return
 	}
 
-	var insights []models.Insight
-	db.GormDB.Find(&insights)
-	model.ResponseJSON(c, http.StatusOK, "Insights retrieved successfully", insights)
+	result, err := h.Insights.List(c.Request.Context(), page)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to retrieve Insights")
+		return
+	}
+	rendered := models.Page[models.RenderedInsight]{
+		Items:    make([]models.RenderedInsight, len(result.Items)),
+		PageInfo: result.PageInfo,
+	}
+	for i, insight := range result.Items {
+		if rendered.Items[i], err = h.renderInsight(c.Request.Context(), insight); err != nil {
+			model.ResponseError(c, err, "Failed to render Insight")
+			return
+		}
+	}
+	model.ResponseJSON(c, http.StatusOK, "Insights retrieved successfully", rendered)
 }
 
-func GetInsight(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+func (h *Handler) GetInsight(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
 	}
 
-	var insight models.Insight
-	if err := db.GormDB.First(&insight, c.Param("id")).Error; err != nil {
-		model.ResponseJSON(c, http.StatusNotFound, "Insight not found", nil)
+	insight, err := h.Insights.Get(c.Request.Context(), id)
+	if err != nil {
+		notFoundOrError(c, err, "Insight not found", "Failed to retrieve Insight")
+		return
+	}
+	rendered, err := h.renderInsight(c.Request.Context(), insight)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to render Insight")
 		return
 	}
-	model.ResponseJSON(c, http.StatusOK, "Insight retrieved successfully", insight)
+	model.ResponseJSON(c, http.StatusOK, "Insight retrieved successfully", rendered)
 }
 
-func UpdateInsight(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+func (h *Handler) UpdateInsight(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
 	}
 
-	var insight models.Insight
-	if err := db.GormDB.First(&insight, c.Param("id")).Error; err != nil {
-		model.ResponseJSON(c, http.StatusNotFound, "Insight not found", nil)
+	insight, err := h.Insights.Get(c.Request.Context(), id)
+	if err != nil {
+		notFoundOrError(c, err, "Insight not found", "Failed to retrieve Insight")
 		return
 	}
 
 	// bind the request body
 	if err := c.ShouldBindJSON(&insight); err != nil {
-		model.ResponseJSON(c, http.StatusBadRequest, "Invalid input", nil)
+		model.ResponseError(c, bindingError(err), "Invalid input")
+		return
+	}
+	insight.ID = id
+	if err := insight.Validate(); err != nil {
+		model.ResponseError(c, err, "Invalid Insight")
+		return
+	}
+
+	if err := h.Insights.Update(c.Request.Context(), &insight); err != nil {
+		notFoundOrError(c, err, "Insight not found", "Failed to update Insight")
+		return
+	}
+	rendered, err := h.renderInsight(c.Request.Context(), insight)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to render Insight")
 		return
 	}
+	model.ResponseJSON(c, http.StatusOK, "Insight updated successfully", rendered)
+}
 
-	db.GormDB.Save(&insight)
-	model.ResponseJSON(c, http.StatusOK, "Insight updated successfully", insight)
+func (h *Handler) DeleteInsight(c *gin.Context) {
+	deleteAsset(c, models.AssetTypeInsight, h.Insights.Delete)
 }
 
-func DeleteInsight(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+// renderInsight returns the insight as read by clients, with the placeholders
+// of its text rendered and references to its sources
+func (h *Handler) renderInsight(ctx context.Context, insight models.Insight) (models.RenderedInsight, error) {
+	text, err := h.InsightRenderer.Render(ctx, insight)
+	if err != nil {
+		return models.RenderedInsight{}, err
 	}
-
-	var insight models.Insight
-	if err := db.GormDB.Delete(&insight, c.Param("id")).Error; err != nil {
-		model.ResponseJSON(c, http.StatusNotFound, "Insight not found", nil)
-		return
+	sources, err := h.sourceRefs(ctx, models.AssetTypeInsight, insight.ID)
+	if err != nil {
+		return models.RenderedInsight{}, err
 	}
-	model.ResponseJSON(c, http.StatusOK, "Insight deleted successfully", nil)
+	return models.RenderedInsight{Insight: insight, Text: text, Template: insight.Text, Sources: sources}, nil
 }
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"platform-go-challenge/api/model"
+	"platform-go-challenge/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LinkChartSource idempotently links the chart to the audience backing it
+func (h *Handler) LinkChartSource(c *gin.Context) {
+	h.linkSource(c, models.AssetTypeChart)
+}
+
+// UnlinkChartSource idempotently removes the link from an audience to the chart
+func (h *Handler) UnlinkChartSource(c *gin.Context) {
+	h.unlinkSource(c, models.AssetTypeChart)
+}
+
+// LinkInsightSource idempotently links the insight to the audience or the
+// chart backing it
+func (h *Handler) LinkInsightSource(c *gin.Context) {
+	h.linkSource(c, models.AssetTypeInsight)
+}
+
+// UnlinkInsightSource idempotently removes the link from an audience or a
+// chart to the insight
+func (h *Handler) UnlinkInsightSource(c *gin.Context) {
+	h.unlinkSource(c, models.AssetTypeInsight)
+}
+
+// linkSource links the asset of targetType identified by the :id path
+// parameter to the source identified by the :type and :sourceId parameters
+func (h *Handler) linkSource(c *gin.Context, targetType models.AssetType) {
+	link, ok := parseLinkParams(c, targetType)
+	if !ok {
+		return
+	}
+
+	created, err := h.Links.Link(c.Request.Context(), link)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to link source")
+		return
+	}
+
+	status := models.LinkStatus{Source: link.Source(), Target: link.Target(), Linked: true}
+	if created {
+		model.ResponseJSON(c, http.StatusCreated, "Source linked successfully", status)
+		return
+	}
+	model.ResponseJSON(c, http.StatusOK, "Source already linked", status)
+}
+
+// unlinkSource removes the link from the source identified by the :type and
+// :sourceId path parameters to the asset of targetType identified by :id
+func (h *Handler) unlinkSource(c *gin.Context, targetType models.AssetType) {
+	link, ok := parseLinkParams(c, targetType)
+	if !ok {
+		return
+	}
+
+	removed, err := h.Links.Unlink(c.Request.Context(), link)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to unlink source")
+		return
+	}
+
+	status := models.LinkStatus{Source: link.Source(), Target: link.Target(), Linked: false}
+	if removed {
+		model.ResponseJSON(c, http.StatusOK, "Source unlinked successfully", status)
+		return
+	}
+	model.ResponseJSON(c, http.StatusOK, "Source was not linked", status)
+}
+
+// parseLinkParams reads the :id, :type and :sourceId path parameters into a
+// link to the asset of targetType and writes a 400 response if any of them is
+// invalid
+func parseLinkParams(c *gin.Context, targetType models.AssetType) (models.AssetLink, bool) {
+	targetID, ok := parseIDParam(c)
+	if !ok {
+		return models.AssetLink{}, false
+	}
+
+	sourceType, err := models.ParseAssetType(c.Param("type"))
+	if err != nil {
+		model.ResponseError(c, err, "Invalid source type")
+		return models.AssetLink{}, false
+	}
+
+	sourceID, err := strconv.ParseUint(c.Param("sourceId"), 10, 64)
+	if err != nil {
+		model.ResponseError(c, models.InvalidField("sourceId", "invalid ID %q", c.Param("sourceId")), "Invalid source ID")
+		return models.AssetLink{}, false
+	}
+
+	link := models.AssetLink{SourceType: sourceType, SourceID: uint(sourceID), TargetType: targetType, TargetID: targetID}
+	if err := link.Validate(); err != nil {
+		model.ResponseError(c, err, fmt.Sprintf("Invalid %s source", targetType))
+		return models.AssetLink{}, false
+	}
+	return link, true
+}
+
+// linkAudiences returns the audiences as read by clients, with references to
+// the charts derived from them
+func (h *Handler) linkAudiences(ctx context.Context, audiences []models.Audience) ([]models.LinkedAudience, error) {
+	ids := make([]uint, len(audiences))
+	for i, audience := range audiences {
+		ids[i] = audience.ID
+	}
+	charts, err := h.targetRefs(ctx, models.AssetTypeAudience, ids, models.AssetTypeChart)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := make([]models.LinkedAudience, len(audiences))
+	for i, audience := range audiences {
+		linked[i] = models.LinkedAudience{Audience: audience, Charts: charts[audience.ID]}
+	}
+	return linked, nil
+}
+
+// linkAudience returns the audience as read by clients, with references to
+// the charts derived from it
+func (h *Handler) linkAudience(ctx context.Context, audience models.Audience) (models.LinkedAudience, error) {
+	linked, err := h.linkAudiences(ctx, []models.Audience{audience})
+	if err != nil {
+		return models.LinkedAudience{}, err
+	}
+	return linked[0], nil
+}
+
+// linkCharts returns the charts as read by clients, with references to the
+// insights drawn from them
+func (h *Handler) linkCharts(ctx context.Context, charts []models.Chart) ([]models.LinkedChart, error) {
+	ids := make([]uint, len(charts))
+	for i, chart := range charts {
+		ids[i] = chart.ID
+	}
+	insights, err := h.targetRefs(ctx, models.AssetTypeChart, ids, models.AssetTypeInsight)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := make([]models.LinkedChart, len(charts))
+	for i, chart := range charts {
+		linked[i] = models.LinkedChart{Chart: chart, Insights: insights[chart.ID]}
+	}
+	return linked, nil
+}
+
+// linkChart returns the chart as read by clients, with references to the
+// insights drawn from it
+func (h *Handler) linkChart(ctx context.Context, chart models.Chart) (models.LinkedChart, error) {
+	linked, err := h.linkCharts(ctx, []models.Chart{chart})
+	if err != nil {
+		return models.LinkedChart{}, err
+	}
+	return linked[0], nil
+}
+
+// targetRefs returns references to the assets of targetType derived from each
+// of the sources, keyed by source ID. Every source has a list, empty if
+// nothing is derived from it.
+func (h *Handler) targetRefs(ctx context.Context, sourceType models.AssetType, sourceIDs []uint, targetType models.AssetType) (map[uint][]models.AssetRef, error) {
+	refs := make(map[uint][]models.AssetRef, len(sourceIDs))
+	for _, id := range sourceIDs {
+		refs[id] = []models.AssetRef{}
+	}
+	if len(sourceIDs) == 0 {
+		return refs, nil
+	}
+
+	links, err := h.Links.Targets(ctx, sourceType, sourceIDs, targetType)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		refs[link.SourceID] = append(refs[link.SourceID], link.Target())
+	}
+	return refs, nil
+}
+
+// sourceRefs returns references to the audiences and charts backing an asset
+func (h *Handler) sourceRefs(ctx context.Context, targetType models.AssetType, targetID uint) ([]models.AssetRef, error) {
+	links, err := h.Links.Sources(ctx, targetType, []uint{targetID})
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]models.AssetRef, len(links))
+	for i, link := range links {
+		refs[i] = link.Source()
+	}
+	return refs, nil
+}
@@ -1,11 +1,24 @@
 package model
 
-import "github.com/gin-gonic/gin"
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"platform-go-challenge/models"
+
+	"github.com/gin-gonic/gin"
+)
 
 type JsonResponse struct {
 	Status  int    `json:"status"`
 	Message string `json:"message"`
-	Data    any    `json:"data"`
+	// Code identifies the error of a failed request
+	Code string `json:"code,omitempty"`
+	// Errors lists the invalid fields of a failed validation
+	Errors []models.FieldError `json:"errors,omitempty"`
+	Data   any                 `json:"data"`
 }
 
 func ResponseJSON(c *gin.Context, status int, message string, data any) {
@@ -17,3 +30,47 @@ func ResponseJSON(c *gin.Context, status int, message string, data any) {
 
 	c.JSON(status, response)
 }
+
+// StatusFor returns the HTTP status of an error kind
+func StatusFor(kind models.ErrorKind) int {
+	switch kind {
+	case models.ErrorNotFound:
+		return http.StatusNotFound
+	case models.ErrorValidation:
+		return http.StatusBadRequest
+	case models.ErrorUnprocessable:
+		return http.StatusUnprocessableEntity
+	case models.ErrorConflict:
+		return http.StatusConflict
+	case models.ErrorForbidden:
+		return http.StatusForbidden
+	case models.ErrorUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ResponseError writes the response of a failed request. Domain errors are
+// mapped to their status and code, with a Retry-After header if they carry a
+// retry hint. Unexpected errors and unavailable dependencies are logged and
+// answered with the failed message, without exposing their details.
+func ResponseError(c *gin.Context, err error, failed string) {
+	domainErr := models.AsError(err)
+	message := domainErr.Message
+	if domainErr.Kind == models.ErrorInternal || domainErr.Kind == models.ErrorUnavailable {
+		log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		message = failed
+	}
+	if domainErr.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(domainErr.RetryAfter.Seconds()))))
+	}
+
+	status := StatusFor(domainErr.Kind)
+	c.JSON(status, JsonResponse{
+		Status:  status,
+		Message: message,
+		Code:    domainErr.Code,
+		Errors:  domainErr.Fields,
+	})
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"strconv"
+
+	"platform-go-challenge/api/model"
+	"platform-go-challenge/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parsePageRequest reads the limit and after query parameters and writes a
+// 400 response if they are invalid
+func parsePageRequest(c *gin.Context) (models.PageRequest, bool) {
+	var limit *int
+	if value := c.Query("limit"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			model.ResponseError(c, models.InvalidField("limit", "invalid number %q", value), "Invalid limit")
+			return models.PageRequest{}, false
+		}
+		limit = &size
+	}
+
+	page, err := models.NewPageRequest(limit, c.Query("after"))
+	if err != nil {
+		model.ResponseError(c, err, "Invalid page")
+		return models.PageRequest{}, false
+	}
+	return page, true
+}
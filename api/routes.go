@@ -0,0 +1,55 @@
+package api
+
+import (
+	"platform-go-challenge/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers all REST routes on the router, served from the
+// given repositories
+func RegisterRoutes(router *gin.Engine, repos repository.Repositories) {
+	h := NewHandler(repos)
+
+	// Audience routes
+	router.POST("/audience", h.CreateAudience)
+	router.GET("/audiences", h.GetAudiences)
+	router.GET("/audiences/compare", h.CompareAudiences)
+	router.GET("/audience/:id", h.GetAudience)
+	router.PUT("/audience/:id", h.UpdateAudience)
+	router.DELETE("/audience/:id", h.DeleteAudience)
+	router.GET("/audience/:id/size", h.GetAudienceSize)
+	router.POST("/audience/:id/chart", h.GenerateChart)
+
+	// Chart routes
+	router.POST("/chart", h.CreateChart)
+	router.GET("/charts", h.GetCharts)
+	router.GET("/chart/:id", h.GetChart)
+	router.PUT("/chart/:id", h.UpdateChart)
+	router.DELETE("/chart/:id", h.DeleteChart)
+	router.POST("/chart/:id/refresh", h.RefreshChart)
+	router.PUT("/chart/:id/sources/:type/:sourceId", h.LinkChartSource)
+	router.DELETE("/chart/:id/sources/:type/:sourceId", h.UnlinkChartSource)
+
+	// Insight routes
+	router.POST("/insight", h.CreateInsight)
+	router.GET("/insights", h.GetInsights)
+	router.GET("/insight/:id", h.GetInsight)
+	router.PUT("/insight/:id", h.UpdateInsight)
+	router.DELETE("/insight/:id", h.DeleteInsight)
+	router.PUT("/insight/:id/sources/:type/:sourceId", h.LinkInsightSource)
+	router.DELETE("/insight/:id/sources/:type/:sourceId", h.UnlinkInsightSource)
+
+	// UserStar routes
+	router.POST("/userstar", h.CreateUserStar)
+	router.GET("/userstars", h.GetUserStars)
+	router.GET("/userstar/:id", h.GetUserStar)
+	router.PUT("/userstar/:id", h.UpdateUserStar)
+	router.DELETE("/userstar/:id", h.DeleteUserStar)
+
+	// Favourites routes
+	router.GET("/users/:userId/favourites", h.GetUserFavourites)
+	router.PUT("/users/:userId/favourites/:type/:assetId", h.StarFavourite)
+	router.PATCH("/users/:userId/favourites/:type/:assetId", h.UpdateFavouriteDescription)
+	router.DELETE("/users/:userId/favourites/:type/:assetId", h.UnstarFavourite)
+}
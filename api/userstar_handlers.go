@@ -1,80 +1,91 @@
 package api
 
 import (
-	"log"
 	"net/http"
 
 	"platform-go-challenge/api/model"
-	"platform-go-challenge/db"
 	"platform-go-challenge/models"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CreateUserStar(c *gin.Context) {
+func (h *Handler) CreateUserStar(c *gin.Context) {
 	var userstar models.UserStar
 
 	//bind the request body
 	if err := c.ShouldBindJSON(&userstar); err != nil {
-		model.ResponseJSON(c, http.StatusBadRequest, "Invalid input", nil)
+		model.ResponseError(c, bindingError(err), "Invalid input")
+		return
+	}
+	if err := h.Stars.Create(c.Request.Context(), &userstar); err != nil {
+		model.ResponseError(c, err, "Failed to save UserStar")
 		return
 	}
-	db.GormDB.Create(&userstar)
 	model.ResponseJSON(c, http.StatusCreated, "UserStar created successfully", userstar)
 }
 
-func GetUserStars(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+func (h *Handler) GetUserStars(c *gin.Context) {
+	page, ok := parsePageRequest(c)
+	if !ok {
+		return
 	}
 
-	var userstars []models.UserStar
-	db.GormDB.Find(&userstars)
-	model.ResponseJSON(c, http.StatusOK, "UserStars retrieved successfully", userstars)
+	result, err := h.Stars.List(c.Request.Context(), page)
+	if err != nil {
+		model.ResponseError(c, err, "Failed to retrieve UserStars")
+		return
+	}
+	model.ResponseJSON(c, http.StatusOK, "UserStars retrieved successfully", result)
 }
 
-func GetUserStar(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+func (h *Handler) GetUserStar(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
 	}
 
-	var userstar models.UserStar
-	if err := db.GormDB.First(&userstar, c.Param("id")).Error; err != nil {
-		model.ResponseJSON(c, http.StatusNotFound, "UserStar not found", nil)
+	userstar, err := h.Stars.Get(c.Request.Context(), id)
+	if err != nil {
+		notFoundOrError(c, err, "UserStar not found", "Failed to retrieve UserStar")
 		return
 	}
 	model.ResponseJSON(c, http.StatusOK, "UserStar retrieved successfully", userstar)
 }
 
-func UpdateUserStar(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+func (h *Handler) UpdateUserStar(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
 	}
 
-	var userstar models.UserStar
-	if err := db.GormDB.First(&userstar, c.Param("id")).Error; err != nil {
-		model.ResponseJSON(c, http.StatusNotFound, "UserStar not found", nil)
+	userstar, err := h.Stars.Get(c.Request.Context(), id)
+	if err != nil {
+		notFoundOrError(c, err, "UserStar not found", "Failed to retrieve UserStar")
 		return
 	}
 
 	// bind the request body
 	if err := c.ShouldBindJSON(&userstar); err != nil {
-		model.ResponseJSON(c, http.StatusBadRequest, "Invalid input", nil)
+		model.ResponseError(c, bindingError(err), "Invalid input")
 		return
 	}
+	userstar.ID = id
 
-	db.GormDB.Save(&userstar)
+	if err := h.Stars.Update(c.Request.Context(), &userstar); err != nil {
+		notFoundOrError(c, err, "UserStar not found", "Failed to save UserStar")
+		return
+	}
 	model.ResponseJSON(c, http.StatusOK, "UserStar updated successfully", userstar)
 }
 
-func DeleteUserStar(c *gin.Context) {
-	if db.GormDB == nil {
-		log.Fatal("DB pointer is nil")
+func (h *Handler) DeleteUserStar(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
 	}
 
-	var userstar models.UserStar
-	if err := db.GormDB.Delete(&userstar, c.Param("id")).Error; err != nil {
-		model.ResponseJSON(c, http.StatusNotFound, "UserStar not found", nil)
+	if err := h.Stars.Delete(c.Request.Context(), id); err != nil {
+		notFoundOrError(c, err, "UserStar not found", "Failed to delete UserStar")
 		return
 	}
 	model.ResponseJSON(c, http.StatusOK, "UserStar deleted successfully", nil)
@@ -0,0 +1,146 @@
+// Package dataset reads the respondents that audiences are sized against
+// from local files
+package dataset
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"platform-go-challenge/models"
+)
+
+// Load reads the respondents of a file, which is CSV if its name ends in
+// .csv and JSON Lines if it ends in .jsonl or .ndjson
+func Load(path string) ([]models.Respondent, error) {
+	read, err := readerFor(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	respondents, err := read(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return respondents, nil
+}
+
+// readerFor returns the reader of the file format given by the extension of path
+func readerFor(path string) (func(io.Reader) ([]models.Respondent, error), error) {
+	switch extension := strings.ToLower(filepath.Ext(path)); extension {
+	case ".csv":
+		return ReadCSV, nil
+	case ".jsonl", ".ndjson":
+		return ReadJSONLines, nil
+	default:
+		return nil, fmt.Errorf("unsupported respondents file %q: must be .csv, .jsonl or .ndjson", path)
+	}
+}
+
+// csvColumns maps the normalized names of CSV columns to the respondent
+// field they hold. Names are normalized by lower-casing them and removing
+// underscores, so that birth_country and BirthCountry are the same column.
+var csvColumns = map[string]string{
+	"gender":        "gender",
+	"birthcountry":  "birthcountry",
+	"agegroup":      "agegroup",
+	"dailyhours":    "dailyhours",
+	"socialhours":   "dailyhours",
+	"noofpurchases": "noofpurchases",
+	"purchases":     "noofpurchases",
+}
+
+// ReadCSV reads respondents from CSV with a header row naming the columns
+// gender, birthcountry, agegroup, dailyhours and noofpurchases. Columns may
+// come in any order; other columns are ignored.
+func ReadCSV(r io.Reader) ([]models.Respondent, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("missing header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index := map[string]int{}
+	for i, name := range header {
+		normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", ""))
+		if field, ok := csvColumns[normalized]; ok {
+			index[field] = i
+		}
+	}
+	for _, field := range []string{"gender", "birthcountry", "agegroup", "dailyhours", "noofpurchases"} {
+		if _, ok := index[field]; !ok {
+			return nil, fmt.Errorf("missing column %s", field)
+		}
+	}
+
+	var respondents []models.Respondent
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return respondents, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		value := func(field string) string { return strings.TrimSpace(record[index[field]]) }
+		respondent := models.Respondent{
+			Gender:       value("gender"),
+			BirthCountry: value("birthcountry"),
+			AgeGroup:     value("agegroup"),
+		}
+		if respondent.DailyHours, err = strconv.ParseFloat(value("dailyhours"), 64); err != nil {
+			return nil, fmt.Errorf("line %d: invalid dailyhours %q", line, value("dailyhours"))
+		}
+		if respondent.NoOfPurchases, err = strconv.Atoi(value("noofpurchases")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid noofpurchases %q", line, value("noofpurchases"))
+		}
+		if err := respondent.Validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		respondents = append(respondents, respondent)
+	}
+}
+
+// ReadJSONLines reads respondents from one JSON object per line, with the
+// fields of the REST representation of a respondent. Blank lines are skipped.
+func ReadJSONLines(r io.Reader) ([]models.Respondent, error) {
+	var respondents []models.Respondent
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var respondent models.Respondent
+		if err := json.Unmarshal([]byte(text), &respondent); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		respondent.ID = 0
+		if err := respondent.Validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		respondents = append(respondents, respondent)
+	}
+	return respondents, scanner.Err()
+}
@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// assetRepository is the Gorm implementation of repository.AssetRepository
+type assetRepository[T models.Asset] struct {
+	db        *gorm.DB
+	assetType models.AssetType
+	policy    repository.AssetDeletePolicy
+	// preload adds the associations loaded with every asset, if any
+	preload func(*gorm.DB) *gorm.DB
+}
+
+// query starts a query for the asset bound to ctx
+func (r *assetRepository[T]) query(ctx context.Context) *gorm.DB {
+	tx := r.db.WithContext(ctx)
+	if r.preload != nil {
+		tx = r.preload(tx)
+	}
+	return tx
+}
+
+func (r *assetRepository[T]) List(ctx context.Context, page models.PageRequest) (models.Page[T], error) {
+	var rows []T
+	if err := r.query(ctx).Scopes(paginate(page)).Find(&rows).Error; err != nil {
+		return models.Page[T]{}, err
+	}
+	return models.NewPage(rows, page, func(asset T) uint { return asset.AssetID() }), nil
+}
+
+func (r *assetRepository[T]) Get(ctx context.Context, id uint) (T, error) {
+	var asset T
+	err := r.query(ctx).First(&asset, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return asset, fmt.Errorf("%w: %s %d", repository.ErrNotFound, r.assetType, id)
+	}
+	return asset, err
+}
+
+func (r *assetRepository[T]) GetMany(ctx context.Context, ids []uint) ([]T, error) {
+	var rows []T
+	if err := r.query(ctx).Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *assetRepository[T]) Create(ctx context.Context, asset *T) error {
+	return r.db.WithContext(ctx).Create(asset).Error
+}
+
+func (r *assetRepository[T]) Update(ctx context.Context, asset *T) error {
+	return updateRow(r.db.WithContext(ctx), asset, string(r.assetType), (*asset).AssetID())
+}
+
+// Delete deletes an asset and its links and applies the delete policy to its
+// stars inside a single transaction. Under the restrict policy it returns
+// repository.ErrAssetStarred and leaves the asset untouched if anyone has starred it.
+//
+// The asset row is locked first, so that stars and links being created for
+// it, which lock it for share, either commit before its stars and links are
+// read or find it deleted.
+func (r *assetRepository[T]) Delete(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		exists, err := assetExists(tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}), r.assetType, id)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: %s %d", repository.ErrNotFound, r.assetType, id)
+		}
+
+		switch r.policy {
+		case repository.AssetDeleteRestrict:
+			var count int64
+			if err := tx.Model(&models.UserStar{}).
+				Where("type = ? AND asset_id = ?", r.assetType, id).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return fmt.Errorf("%w: %s %d has %d star(s)", repository.ErrAssetStarred, r.assetType, id, count)
+			}
+		default:
+			if err := tx.Where("type = ? AND asset_id = ?", r.assetType, id).
+				Delete(&models.UserStar{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := deleteAssetLinks(tx, r.assetType, id); err != nil {
+			return err
+		}
+
+		if r.assetType == models.AssetTypeChart {
+			if err := tx.Where("chart_id = ?", id).Delete(&models.ChartSeries{}).Error; err != nil {
+				return err
+			}
+		}
+
+		var asset T
+		result := tx.Delete(&asset, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: %s %d", repository.ErrNotFound, r.assetType, id)
+		}
+		return nil
+	})
+	// starting or committing the transaction may fail on the connection
+	return unavailableError(err)
+}
+
+// updateRow stores all fields of the existing row of value, but not its
+// associations. Unlike Save it never inserts: it returns
+// repository.ErrNotFound if no row has the given ID.
+func updateRow(tx *gorm.DB, value any, kind string, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("%w: %s %d", repository.ErrNotFound, kind, id)
+	}
+	result := tx.Select("*").Omit(clause.Associations).Updates(value)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %s %d", repository.ErrNotFound, kind, id)
+	}
+	return nil
+}
+
+// newAsset returns an empty model of the given asset type
+func newAsset(assetType models.AssetType) (any, error) {
+	switch assetType {
+	case models.AssetTypeAudience:
+		return &models.Audience{}, nil
+	case models.AssetTypeChart:
+		return &models.Chart{}, nil
+	case models.AssetTypeInsight:
+		return &models.Insight{}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", repository.ErrInvalidAssetType, assetType)
+}
+
+// assetExists reports whether the asset of the given type and ID is present
+func assetExists(tx *gorm.DB, assetType models.AssetType, assetID uint) (bool, error) {
+	asset, err := newAsset(assetType)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Select("id").First(asset, assetID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// checkStarTarget verifies that a star, or either end of a link, points to a
+// valid asset type and an existing asset. It returns
+// repository.ErrInvalidAssetType or repository.ErrAssetNotFound otherwise.
+// The asset row is locked for share, so that within the transaction tx the
+// asset cannot be deleted until the star or link is committed.
+func checkStarTarget(tx *gorm.DB, assetType models.AssetType, assetID uint) error {
+	if err := repository.CheckAssetType(assetType); err != nil {
+		return err
+	}
+
+	exists, err := assetExists(tx.Clauses(clause.Locking{Strength: clause.LockingStrengthShare}), assetType, assetID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s %d", repository.ErrAssetNotFound, assetType, assetID)
+	}
+	return nil
+}
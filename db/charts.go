@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+
+	"platform-go-challenge/models"
+
+	"gorm.io/gorm"
+)
+
+// chartRepository stores charts and replaces their series on update
+type chartRepository struct {
+	assetRepository[models.Chart]
+}
+
+// Update updates a chart and replaces all of its series in a single
+// transaction, so that removed series do not linger
+func (r *chartRepository) Update(ctx context.Context, chart *models.Chart) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updateRow(tx, chart, string(models.AssetTypeChart), chart.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Where("chart_id = ?", chart.ID).Delete(&models.ChartSeries{}).Error; err != nil {
+			return err
+		}
+
+		if len(chart.Series) == 0 {
+			return nil
+		}
+		for i := range chart.Series {
+			chart.Series[i].ID = 0
+			chart.Series[i].ChartID = chart.ID
+		}
+		return tx.Create(&chart.Series).Error
+	})
+	// starting or committing the transaction may fail on the connection
+	return unavailableError(err)
+}
+
+// withSeries preloads the series of the charts loaded by the query, in the
+// order they were defined
+func withSeries(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Series", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("id")
+	})
+}
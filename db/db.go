@@ -1,19 +1,21 @@
 package db
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
-
-	"platform-go-challenge/models"
+	"time"
 
 	"github.com/joho/godotenv"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 var GormDB *gorm.DB
 
-func InitDB() {
+// Connect opens the database at DB_URL and stores it in GormDB. While the
+// database cannot be reached it retries for up to DB_CONNECT_TIMEOUT.
+func Connect() {
 
 	db_url, isSet := os.LookupEnv("DB_URL")
 	if !isSet {
@@ -27,23 +29,60 @@ func InitDB() {
 	}
 	log.Printf("DB_URL value: %s", db_url)
 
-	var err error
-	GormDB, err = gorm.Open(postgres.Open(db_url), &gorm.Config{})
+	timeout, err := durationFromEnv("DB_CONNECT_TIMEOUT", DefaultConnectTimeout)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	GormDB, err = OpenWithRetry(ctx, db_url)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
+}
 
-	// migrate the schema
-	if err := GormDB.AutoMigrate(
-		&models.Audience{},
-		&models.Chart{},
-		&models.Insight{},
-		&models.UserStar{},
-	); err != nil {
-		log.Fatal("Failed to migrate schema:", err)
+// InitDB connects to the database and refuses to continue unless all
+// migrations are applied, run the migrate subcommand to apply them
+func InitDB() {
+	Connect()
+
+	migrator, err := NewMigrator(GormDB)
+	if err != nil {
+		log.Fatal("Failed to load migrations:", err)
+	}
+	if err := migrator.Check(context.Background()); err != nil {
+		log.Fatal("Database schema check failed, run `migrate up` first: ", err)
 	}
 
 	if GormDB != nil {
 		log.Println("DB connection established")
 	}
 }
+
+// StartMonitor starts a Monitor of GormDB pinging it every
+// DB_HEALTH_INTERVAL until ctx is done
+func StartMonitor(ctx context.Context) *Monitor {
+	interval, err := durationFromEnv("DB_HEALTH_INTERVAL", DefaultHealthInterval)
+	if err != nil {
+		log.Fatal("Failed to monitor database:", err)
+	}
+
+	monitor := NewMonitor(GormDB, interval)
+	go monitor.Run(ctx)
+	return monitor
+}
+
+// durationFromEnv parses the positive Go duration in the environment
+// variable name, or returns fallback if it is not set
+func durationFromEnv(name string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive duration", name, value)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,180 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"platform-go-challenge/repository"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// Defaults of the startup connection and of the Monitor
+const (
+	DefaultConnectTimeout = 30 * time.Second
+	DefaultHealthInterval = 5 * time.Second
+)
+
+// Delays between the connection attempts of OpenWithRetry
+const (
+	minRetryDelay = 500 * time.Millisecond
+	maxRetryDelay = 5 * time.Second
+)
+
+// isConnectionError reports whether err means the database could not be
+// reached, as opposed to a failure of the statement itself
+func isConnectionError(err error) bool {
+	// Postgres connection exceptions and shutdowns, e.g. during a restart.
+	// Other server errors, such as a failed authentication, are not
+	// resolved by retrying.
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "57P")
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+	// A statement that ran out of its own deadline, or whose request was
+	// canceled, says nothing about the database. context.DeadlineExceeded
+	// implements net.Error, so it is ruled out first.
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// unavailableError wraps repository.ErrUnavailable around connection errors
+// and returns any other error unchanged
+func unavailableError(err error) error {
+	if err == nil || errors.Is(err, repository.ErrUnavailable) || !isConnectionError(err) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", repository.ErrUnavailable, err)
+}
+
+// translateUnavailable registers callbacks reporting the connection errors of
+// every statement as repository.ErrUnavailable
+func translateUnavailable(database *gorm.DB) error {
+	translate := func(tx *gorm.DB) {
+		tx.Error = unavailableError(tx.Error)
+	}
+
+	callbacks := database.Callback()
+	for _, err := range []error{
+		callbacks.Create().Register("db:unavailable", translate),
+		callbacks.Query().Register("db:unavailable", translate),
+		callbacks.Update().Register("db:unavailable", translate),
+		callbacks.Delete().Register("db:unavailable", translate),
+		callbacks.Row().Register("db:unavailable", translate),
+		callbacks.Raw().Register("db:unavailable", translate),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OpenWithRetry connects to the database at url like Open, retrying with
+// increasing delays while it cannot be reached until ctx is done. Invalid
+// URLs fail immediately.
+func OpenWithRetry(ctx context.Context, url string) (*gorm.DB, error) {
+	delay := minRetryDelay
+	for {
+		database, err := Open(url)
+		if err == nil || !isConnectionError(err) {
+			return database, err
+		}
+
+		log.Printf("Database unavailable, retrying in %s: %v", delay, err)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", repository.ErrUnavailable, err)
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxRetryDelay)
+	}
+}
+
+// Monitor pings the database periodically and reports whether it can be
+// reached. database/sql replaces broken connections as needed, so once the
+// database is back, for example after a restart, the next ping reconnects
+// and the monitor reports it available again.
+type Monitor struct {
+	db       *gorm.DB
+	interval time.Duration
+
+	mu  sync.RWMutex
+	err error
+}
+
+// NewMonitor returns a Monitor pinging database every interval. It assumes
+// the database is available until the first ping.
+func NewMonitor(database *gorm.DB, interval time.Duration) *Monitor {
+	return &Monitor{db: database, interval: interval}
+}
+
+// Available returns nil if the last ping succeeded and otherwise
+// repository.ErrUnavailable, asking to retry after the ping interval
+func (m *Monitor) Available() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.err != nil {
+		return fmt.Errorf("%w: %w", repository.ErrUnavailable.WithRetryAfter(m.interval), m.err)
+	}
+	return nil
+}
+
+// Ping checks the database now and records the result, logging changes
+func (m *Monitor) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, m.interval)
+	defer cancel()
+
+	sqlDB, err := m.db.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch {
+	case err != nil && m.err == nil:
+		log.Printf("Database unavailable: %v", err)
+	case err == nil && m.err != nil:
+		log.Println("Database available again")
+	}
+	m.err = err
+	return err
+}
+
+// Run pings the database every interval until ctx is done
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Ping(ctx)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// linkRepository is the Gorm implementation of repository.LinkRepository
+type linkRepository struct {
+	db *gorm.DB
+}
+
+func (r *linkRepository) Link(ctx context.Context, link models.AssetLink) (bool, error) {
+	if err := repository.CheckLink(link); err != nil {
+		return false, err
+	}
+
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkStarTarget(tx, link.SourceType, link.SourceID); err != nil {
+			return err
+		}
+		if err := checkStarTarget(tx, link.TargetType, link.TargetID); err != nil {
+			return err
+		}
+
+		link.ID = 0
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&link)
+		created = result.RowsAffected == 1
+		return result.Error
+	})
+	return created, unavailableError(err)
+}
+
+func (r *linkRepository) Unlink(ctx context.Context, link models.AssetLink) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("source_type = ? AND source_id = ? AND target_type = ? AND target_id = ?",
+			link.SourceType, link.SourceID, link.TargetType, link.TargetID).
+		Delete(&models.AssetLink{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *linkRepository) Sources(ctx context.Context, targetType models.AssetType, targetIDs []uint) ([]models.AssetLink, error) {
+	return r.find(r.db.WithContext(ctx).Where("target_type = ? AND target_id IN ?", targetType, targetIDs))
+}
+
+func (r *linkRepository) Targets(ctx context.Context, sourceType models.AssetType, sourceIDs []uint, targetType models.AssetType) ([]models.AssetLink, error) {
+	return r.find(r.db.WithContext(ctx).
+		Where("source_type = ? AND source_id IN ? AND target_type = ?", sourceType, sourceIDs, targetType))
+}
+
+// find returns the links selected by tx in ID order
+func (r *linkRepository) find(tx *gorm.DB) ([]models.AssetLink, error) {
+	var links []models.AssetLink
+	if err := tx.Order("id").Find(&links).Error; err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// deleteAssetLinks removes the links from and to an asset
+func deleteAssetLinks(tx *gorm.DB, assetType models.AssetType, assetID uint) error {
+	return tx.Where("(source_type = ? AND source_id = ?) OR (target_type = ? AND target_id = ?)",
+		assetType, assetID, assetType, assetID).
+		Delete(&models.AssetLink{}).Error
+}
@@ -0,0 +1,336 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationFiles holds the SQL migrations of every dialect, one directory per
+// dialect named after the Gorm dialector
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// migrationFileName matches <version>_<name>.<up|down>.sql
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// createMigrationsTable creates the table recording the applied migrations
+const createMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version bigint PRIMARY KEY,
+	name text NOT NULL,
+	checksum text NOT NULL,
+	applied_at timestamp NOT NULL
+)`
+
+var (
+	// ErrSchemaOutdated is returned by Check when migrations are pending
+	ErrSchemaOutdated = errors.New("database schema is not up to date")
+	// ErrChecksumMismatch means an applied migration was edited afterwards
+	ErrChecksumMismatch = errors.New("migration checksum mismatch")
+	// ErrUnknownMigration means the database has a migration applied that
+	// this binary does not know, typically because it was built from an
+	// older version
+	ErrUnknownMigration = errors.New("unknown migration applied")
+)
+
+// Migration is a versioned schema change with the SQL to apply and revert it
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+	// Checksum is the SHA-256 of the up and down SQL, recorded when the
+	// migration is applied so that later edits are detected
+	Checksum string
+}
+
+// MigrationStatus is a known migration together with its state in the database
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Modified reports that the migration changed since it was applied
+	Modified bool
+}
+
+// appliedMigration is a row of schema_migrations
+type appliedMigration struct {
+	Version   uint
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrator applies and reverts the migrations of the database dialect
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator returns a Migrator for the migrations matching the dialect of database
+func NewMigrator(database *gorm.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles, path.Join("migrations", database.Dialector.Name()))
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: database, migrations: migrations}, nil
+}
+
+// Migrate applies all pending migrations to database
+func Migrate(database *gorm.DB) error {
+	migrator, err := NewMigrator(database)
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up(context.Background())
+	return err
+}
+
+// loadMigrations reads the migrations in dir ordered by version. Every
+// version needs both an up and a down file.
+func loadMigrations(files fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for %s: %w", path.Base(dir), err)
+	}
+
+	byVersion := map[uint]*Migration{}
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", entry.Name())
+		}
+
+		content, err := fs.ReadFile(files, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has files with different names", version)
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		sum := sha256.Sum256([]byte(migration.Up + "\x00" + migration.Down))
+		migration.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *migration)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return int(a.Version) - int(b.Version) })
+	return migrations, nil
+}
+
+// Latest returns the version of the newest known migration
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status returns every known migration with its state in the database
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = MigrationStatus{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = row.AppliedAt
+			statuses[i].Modified = row.Checksum != migration.Checksum
+		}
+	}
+	return statuses, nil
+}
+
+// Check verifies that every migration is applied unmodified. It returns
+// ErrSchemaOutdated if any is pending.
+func (m *Migrator) Check(ctx context.Context) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	if err := m.verify(applied); err != nil {
+		return err
+	}
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			return fmt.Errorf("%w: migration %04d_%s is pending", ErrSchemaOutdated, migration.Version, migration.Name)
+		}
+	}
+	return nil
+}
+
+// Up applies all pending migrations and returns them in the order applied
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.To(ctx, m.Latest())
+}
+
+// Down reverts the most recently applied migration. It returns nothing if no
+// migration is applied.
+func (m *Migrator) Down(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var current uint
+	for version := range applied {
+		current = max(current, version)
+	}
+	if current == 0 {
+		return nil, nil
+	}
+
+	// migrate to the newest applied version below the current one
+	var target uint
+	for version := range applied {
+		if version < current {
+			target = max(target, version)
+		}
+	}
+	return m.To(ctx, target)
+}
+
+// To migrates the database to version: migrations up to it that are pending
+// are applied in ascending order, applied migrations above it are reverted in
+// descending order. Version 0 reverts every migration. It returns the
+// migrations applied or reverted, in order.
+func (m *Migrator) To(ctx context.Context, version uint) ([]Migration, error) {
+	if version != 0 && !slices.ContainsFunc(m.migrations, func(migration Migration) bool { return migration.Version == version }) {
+		return nil, fmt.Errorf("unknown migration version %d", version)
+	}
+
+	if err := m.db.WithContext(ctx).Exec(createMigrationsTable).Error; err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.verify(applied); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok || migration.Version <= version {
+			continue
+		}
+		if err := m.revert(ctx, migration); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok || migration.Version > version {
+			continue
+		}
+		if err := m.apply(ctx, migration); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// apply runs the up SQL of a migration and records it in one transaction
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(migration.Up).Error; err != nil {
+			return err
+		}
+		return tx.Exec("INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)",
+			migration.Version, migration.Name, migration.Checksum, time.Now().UTC()).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply migration %04d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// revert runs the down SQL of a migration and forgets it in one transaction
+func (m *Migrator) revert(ctx context.Context, migration Migration) error {
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(migration.Down).Error; err != nil {
+			return err
+		}
+		return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revert migration %04d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// applied returns the rows of schema_migrations by version. A database
+// without the table has no migration applied.
+func (m *Migrator) applied(ctx context.Context) (map[uint]appliedMigration, error) {
+	tx := m.db.WithContext(ctx)
+	if !tx.Migrator().HasTable("schema_migrations") {
+		return map[uint]appliedMigration{}, nil
+	}
+
+	var rows []appliedMigration
+	if err := tx.Table("schema_migrations").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	applied := make(map[uint]appliedMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// verify checks that the applied migrations are known and unmodified
+func (m *Migrator) verify(applied map[uint]appliedMigration) error {
+	for _, row := range applied {
+		i := slices.IndexFunc(m.migrations, func(migration Migration) bool { return migration.Version == row.Version })
+		if i < 0 {
+			return fmt.Errorf("%w: %04d_%s", ErrUnknownMigration, row.Version, row.Name)
+		}
+		if m.migrations[i].Checksum != row.Checksum {
+			return fmt.Errorf("%w: %04d_%s was changed after it was applied", ErrChecksumMismatch, row.Version, row.Name)
+		}
+	}
+	return nil
+}
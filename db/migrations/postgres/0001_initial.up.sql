@@ -0,0 +1,52 @@
+-- Initial schema. Tables and columns are created only if missing, so that
+-- databases created by the former AutoMigrate-based setup are adopted as is.
+
+CREATE TABLE IF NOT EXISTS audiences (
+    id bigserial PRIMARY KEY,
+    gender text,
+    birth_country text,
+    age_group text,
+    daily_hours bigint,
+    no_of_purchases bigint
+);
+
+CREATE TABLE IF NOT EXISTS charts (
+    id bigserial PRIMARY KEY,
+    title text,
+    x_axis_title text,
+    y_axis_title text,
+    labels text
+);
+ALTER TABLE charts ADD COLUMN IF NOT EXISTS labels text;
+
+CREATE TABLE IF NOT EXISTS chart_series (
+    id bigserial PRIMARY KEY,
+    chart_id bigint,
+    name text,
+    points text,
+    CONSTRAINT fk_charts_series FOREIGN KEY (chart_id) REFERENCES charts (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_chart_series_chart_id ON chart_series (chart_id);
+
+CREATE TABLE IF NOT EXISTS insights (
+    id bigserial PRIMARY KEY,
+    text text
+);
+
+CREATE TABLE IF NOT EXISTS user_stars (
+    id bigserial PRIMARY KEY,
+    user_id bigint,
+    type text,
+    asset_id bigint,
+    description text,
+    created_at timestamptz
+);
+ALTER TABLE user_stars ADD COLUMN IF NOT EXISTS description text;
+ALTER TABLE user_stars ADD COLUMN IF NOT EXISTS created_at timestamptz;
+
+-- remove duplicate stars left from before the unique index existed, keeping
+-- the oldest one
+DELETE FROM user_stars WHERE id NOT IN (
+    SELECT MIN(id) FROM user_stars GROUP BY user_id, type, asset_id
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_star_asset ON user_stars (user_id, type, asset_id);
@@ -0,0 +1,18 @@
+-- Restores the flat characteristics from the first value of every criterion.
+-- Audiences with several values or with range criteria lose them.
+
+ALTER TABLE audiences
+    ADD COLUMN gender text,
+    ADD COLUMN birth_country text,
+    ADD COLUMN age_group text,
+    ADD COLUMN daily_hours bigint,
+    ADD COLUMN no_of_purchases bigint;
+
+UPDATE audiences SET
+    gender = criteria -> 'genders' ->> 0,
+    birth_country = criteria -> 'birthcountries' ->> 0,
+    age_group = criteria -> 'agegroups' ->> 0,
+    daily_hours = (criteria -> 'dailyhours' ->> 'value')::numeric,
+    no_of_purchases = (criteria -> 'noofpurchases' ->> 'value')::numeric;
+
+ALTER TABLE audiences DROP COLUMN criteria;
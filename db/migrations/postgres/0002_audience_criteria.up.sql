@@ -0,0 +1,20 @@
+-- Audiences are described by criteria, stored as one JSON document. Every
+-- characteristic of an existing audience becomes a criterion matching
+-- exactly its value.
+
+ALTER TABLE audiences ADD COLUMN criteria jsonb NOT NULL DEFAULT '{}';
+
+UPDATE audiences SET criteria = jsonb_strip_nulls(jsonb_build_object(
+    'genders', CASE WHEN gender <> '' THEN jsonb_build_array(gender) END,
+    'birthcountries', CASE WHEN birth_country <> '' THEN jsonb_build_array(birth_country) END,
+    'agegroups', CASE WHEN age_group <> '' THEN jsonb_build_array(age_group) END,
+    'dailyhours', CASE WHEN daily_hours IS NOT NULL THEN jsonb_build_object('operator', 'EQ', 'value', daily_hours) END,
+    'noofpurchases', CASE WHEN no_of_purchases IS NOT NULL THEN jsonb_build_object('operator', 'EQ', 'value', no_of_purchases) END
+));
+
+ALTER TABLE audiences
+    DROP COLUMN gender,
+    DROP COLUMN birth_country,
+    DROP COLUMN age_group,
+    DROP COLUMN daily_hours,
+    DROP COLUMN no_of_purchases;
@@ -0,0 +1 @@
+ALTER TABLE audiences DROP COLUMN expression;
@@ -0,0 +1,4 @@
+-- Charts generated from an audience record the audience, the dimension and
+-- the versions they were computed from as JSON. NULL for other charts.
+
+ALTER TABLE charts ADD COLUMN provenance text;
@@ -0,0 +1 @@
+DROP TABLE asset_links;
@@ -0,0 +1,2 @@
+ALTER TABLE charts DROP COLUMN config;
+ALTER TABLE charts DROP COLUMN chart_type;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS user_stars;
+DROP TABLE IF EXISTS insights;
+DROP TABLE IF EXISTS chart_series;
+DROP TABLE IF EXISTS charts;
+DROP TABLE IF EXISTS audiences;
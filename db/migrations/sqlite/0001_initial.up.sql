@@ -0,0 +1,43 @@
+-- Initial schema. Tables are created only if missing, so that databases
+-- created by the former AutoMigrate-based setup are adopted as is.
+
+CREATE TABLE IF NOT EXISTS audiences (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    gender text,
+    birth_country text,
+    age_group text,
+    daily_hours integer,
+    no_of_purchases integer
+);
+
+CREATE TABLE IF NOT EXISTS charts (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    title text,
+    x_axis_title text,
+    y_axis_title text,
+    labels text
+);
+
+CREATE TABLE IF NOT EXISTS chart_series (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    chart_id integer,
+    name text,
+    points text,
+    CONSTRAINT fk_charts_series FOREIGN KEY (chart_id) REFERENCES charts (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_chart_series_chart_id ON chart_series (chart_id);
+
+CREATE TABLE IF NOT EXISTS insights (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    text text
+);
+
+CREATE TABLE IF NOT EXISTS user_stars (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    user_id integer,
+    type text,
+    asset_id integer,
+    description text,
+    created_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_star_asset ON user_stars (user_id, type, asset_id);
@@ -0,0 +1,17 @@
+-- Restores the flat characteristics from the first value of every criterion.
+-- Audiences with several values or with range criteria lose them.
+
+ALTER TABLE audiences ADD COLUMN gender text;
+ALTER TABLE audiences ADD COLUMN birth_country text;
+ALTER TABLE audiences ADD COLUMN age_group text;
+ALTER TABLE audiences ADD COLUMN daily_hours integer;
+ALTER TABLE audiences ADD COLUMN no_of_purchases integer;
+
+UPDATE audiences SET
+    gender = json_extract(criteria, '$.genders[0]'),
+    birth_country = json_extract(criteria, '$.birthcountries[0]'),
+    age_group = json_extract(criteria, '$.agegroups[0]'),
+    daily_hours = CAST(json_extract(criteria, '$.dailyhours.value') AS integer),
+    no_of_purchases = CAST(json_extract(criteria, '$.noofpurchases.value') AS integer);
+
+ALTER TABLE audiences DROP COLUMN criteria;
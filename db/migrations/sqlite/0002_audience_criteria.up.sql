@@ -0,0 +1,19 @@
+-- Audiences are described by criteria, stored as one JSON document. Every
+-- characteristic of an existing audience becomes a criterion matching
+-- exactly its value. Patching an empty object drops the null members.
+
+ALTER TABLE audiences ADD COLUMN criteria text NOT NULL DEFAULT '{}';
+
+UPDATE audiences SET criteria = json_patch('{}', json_object(
+    'genders', CASE WHEN gender <> '' THEN json_array(gender) END,
+    'birthcountries', CASE WHEN birth_country <> '' THEN json_array(birth_country) END,
+    'agegroups', CASE WHEN age_group <> '' THEN json_array(age_group) END,
+    'dailyhours', CASE WHEN daily_hours IS NOT NULL THEN json_object('operator', 'EQ', 'value', daily_hours) END,
+    'noofpurchases', CASE WHEN no_of_purchases IS NOT NULL THEN json_object('operator', 'EQ', 'value', no_of_purchases) END
+));
+
+ALTER TABLE audiences DROP COLUMN gender;
+ALTER TABLE audiences DROP COLUMN birth_country;
+ALTER TABLE audiences DROP COLUMN age_group;
+ALTER TABLE audiences DROP COLUMN daily_hours;
+ALTER TABLE audiences DROP COLUMN no_of_purchases;
@@ -0,0 +1,4 @@
+-- Audiences can narrow their criteria with an audience expression, stored in
+-- canonical form. Empty means no further condition.
+
+ALTER TABLE audiences ADD COLUMN expression text NOT NULL DEFAULT '';
@@ -0,0 +1,10 @@
+-- Respondents are the surveyed people that audiences are sized against
+
+CREATE TABLE respondents (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    gender text NOT NULL,
+    birth_country text NOT NULL,
+    age_group text NOT NULL,
+    daily_hours real NOT NULL,
+    no_of_purchases integer NOT NULL
+);
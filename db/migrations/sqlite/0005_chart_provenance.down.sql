@@ -0,0 +1 @@
+ALTER TABLE charts DROP COLUMN provenance;
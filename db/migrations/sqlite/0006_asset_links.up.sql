@@ -0,0 +1,12 @@
+-- Links from audiences and charts to the charts and insights derived from
+-- them. Deleting an asset deletes its links in the same transaction.
+
+CREATE TABLE asset_links (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    source_type text NOT NULL,
+    source_id integer NOT NULL,
+    target_type text NOT NULL,
+    target_id integer NOT NULL
+);
+CREATE UNIQUE INDEX idx_asset_link ON asset_links (source_type, source_id, target_type, target_id);
+CREATE INDEX idx_asset_link_target ON asset_links (target_type, target_id);
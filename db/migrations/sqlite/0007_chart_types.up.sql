@@ -0,0 +1,5 @@
+-- Charts have a type, which decides the configuration they accept. Charts
+-- created before are bar charts without configuration.
+
+ALTER TABLE charts ADD COLUMN chart_type text NOT NULL DEFAULT 'BAR';
+ALTER TABLE charts ADD COLUMN config text;
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// sqlitePragmas are applied to every SQLite connection: foreign keys are off
+// by default in SQLite and concurrent writers should wait instead of failing
+var sqlitePragmas = []string{"foreign_keys(1)", "busy_timeout(5000)"}
+
+// Open connects to the database at url, choosing the driver from its scheme.
+// Statements failing because the database cannot be reached return
+// repository.ErrUnavailable.
+// postgres:// and postgresql:// URLs, as well as key=value DSNs, use Postgres.
+// sqlite: URLs use the embedded SQLite driver, e.g. sqlite://data/app.db for
+// a file relative to the working directory, sqlite:///var/lib/app.db for an
+// absolute path or sqlite::memory: for a private in-memory database.
+func Open(url string) (*gorm.DB, error) {
+	dialector, isSQLite, err := dialectorFor(url)
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := gorm.Open(dialector, &gorm.Config{TranslateError: true})
+	if err != nil {
+		return nil, err
+	}
+	if err := translateUnavailable(database); err != nil {
+		return nil, err
+	}
+
+	if isSQLite {
+		// SQLite allows a single writer, and every connection to :memory:
+		// would open a separate empty database
+		sqlDB, err := database.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+	return database, nil
+}
+
+// dialectorFor returns the Gorm dialector for url and whether it is SQLite
+func dialectorFor(url string) (gorm.Dialector, bool, error) {
+	if path, ok := strings.CutPrefix(url, "sqlite:"); ok {
+		path = strings.TrimPrefix(path, "//")
+		if path == "" {
+			return nil, false, fmt.Errorf("sqlite URL %q has no database path", url)
+		}
+		return sqlite.Open(sqliteDSN(path)), true, nil
+	}
+
+	scheme, _, hasScheme := strings.Cut(url, "://")
+	switch {
+	case !hasScheme, scheme == "postgres", scheme == "postgresql":
+		return postgres.Open(url), false, nil
+	}
+	return nil, false, fmt.Errorf("unsupported database URL scheme %q: must be postgres, postgresql or sqlite", scheme)
+}
+
+// sqliteDSN appends sqlitePragmas to the query string of a SQLite path
+func sqliteDSN(path string) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	for _, pragma := range sqlitePragmas {
+		path += separator + "_pragma=" + pragma
+		separator = "&"
+	}
+	return path
+}
@@ -0,0 +1,19 @@
+package db
+
+import (
+	"platform-go-challenge/models"
+
+	"gorm.io/gorm"
+)
+
+// paginate is a scope applying keyset pagination on the id column. It fetches
+// one row more than the limit so that models.NewPage can tell whether there
+// is a next page.
+func paginate(page models.PageRequest) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if page.After > 0 {
+			tx = tx.Where("id > ?", page.After)
+		}
+		return tx.Order("id").Limit(page.Limit + 1)
+	}
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+
+	"gorm.io/gorm"
+)
+
+// NewRepositories returns the Gorm backed repositories. Deleted assets are
+// handled according to policy.
+func NewRepositories(tx *gorm.DB, policy repository.AssetDeletePolicy) repository.Repositories {
+	repos := repository.Repositories{
+		Charts: &chartRepository{assetRepository[models.Chart]{
+			db: tx, assetType: models.AssetTypeChart, policy: policy, preload: withSeries,
+		}},
+		Insights: &assetRepository[models.Insight]{
+			db: tx, assetType: models.AssetTypeInsight, policy: policy,
+		},
+		Audiences: &assetRepository[models.Audience]{
+			db: tx, assetType: models.AssetTypeAudience, policy: policy,
+		},
+		Stars:       &starRepository{db: tx},
+		Links:       &linkRepository{db: tx},
+		Respondents: &respondentRepository{db: tx},
+	}
+	repos.Favourites = repository.NewFavouriteRepository(repos)
+	repos.AudienceSizes = repository.NewAudienceSizeRepository(repos.Respondents)
+	repos.ChartGenerator = repository.NewChartGenerator(repos)
+	repos.InsightRenderer = repository.NewInsightRenderer(repos)
+	return repos
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"platform-go-challenge/models"
+
+	"gorm.io/gorm"
+)
+
+// respondentBatchSize is the number of respondents written or evaluated at once
+const respondentBatchSize = 1000
+
+// respondentRepository is the Gorm implementation of repository.RespondentRepository
+type respondentRepository struct {
+	db *gorm.DB
+}
+
+func (r *respondentRepository) Replace(ctx context.Context, respondents []models.Respondent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM respondents").Error; err != nil {
+			return err
+		}
+		if len(respondents) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(respondents, respondentBatchSize).Error
+	})
+}
+
+// Count evaluates the expression in batches of respondents, as expressions
+// are not translated to SQL
+func (r *respondentRepository) Count(ctx context.Context, expression models.Expression) (int64, int64, error) {
+	if expression == nil {
+		var total int64
+		err := r.db.WithContext(ctx).Model(&models.Respondent{}).Count(&total).Error
+		return total, total, err
+	}
+
+	var matched, total int64
+	var batch []models.Respondent
+	err := r.db.WithContext(ctx).FindInBatches(&batch, respondentBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, respondent := range batch {
+			if expression.Matches(respondent.Profile()) {
+				matched++
+			}
+		}
+		total += int64(len(batch))
+		return nil
+	}).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return matched, total, nil
+}
+
+// CountOverlaps evaluates the expressions in batches of respondents, reading
+// the dataset once
+func (r *respondentRepository) CountOverlaps(ctx context.Context, expressions []models.Expression) ([][]int64, int64, error) {
+	counter := models.NewOverlapCounter(expressions)
+	var batch []models.Respondent
+	err := r.db.WithContext(ctx).FindInBatches(&batch, respondentBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, respondent := range batch {
+			counter.Add(respondent.Profile())
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	counts, total := counter.Counts()
+	return counts, total, nil
+}
+
+// Breakdown evaluates the expression in batches of respondents
+func (r *respondentRepository) Breakdown(ctx context.Context, expression models.Expression, dimension models.Dimension) (map[string]int64, error) {
+	counter := models.NewBreakdownCounter(expression, dimension)
+	var batch []models.Respondent
+	err := r.db.WithContext(ctx).FindInBatches(&batch, respondentBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, respondent := range batch {
+			counter.Add(respondent.Profile())
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return counter.Counts(), nil
+}
+
+// Version combines the number of respondents and the highest ID. Replacing
+// the dataset inserts rows with new IDs, so the version changes even if the
+// number of respondents does not.
+func (r *respondentRepository) Version(ctx context.Context) (string, error) {
+	var stats struct {
+		Total  int64
+		LastID int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.Respondent{}).
+		Select("COUNT(*) AS total, COALESCE(MAX(id), 0) AS last_id").Scan(&stats).Error
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%d", stats.Total, stats.LastID), nil
+}
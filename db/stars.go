@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// starRepository is the Gorm implementation of repository.StarRepository
+type starRepository struct {
+	db *gorm.DB
+}
+
+func (r *starRepository) List(ctx context.Context, page models.PageRequest) (models.Page[models.UserStar], error) {
+	return r.page(r.db.WithContext(ctx), page)
+}
+
+func (r *starRepository) ListByUser(ctx context.Context, userID uint, page models.PageRequest) (models.Page[models.UserStar], error) {
+	return r.page(r.db.WithContext(ctx).Where("user_id = ?", userID), page)
+}
+
+// page fetches one page of the stars selected by tx
+func (r *starRepository) page(tx *gorm.DB, page models.PageRequest) (models.Page[models.UserStar], error) {
+	var rows []models.UserStar
+	if err := tx.Scopes(paginate(page)).Find(&rows).Error; err != nil {
+		return models.Page[models.UserStar]{}, err
+	}
+	return models.NewPage(rows, page, func(star models.UserStar) uint { return star.ID }), nil
+}
+
+func (r *starRepository) AllByUser(ctx context.Context, userID uint) ([]models.UserStar, error) {
+	var stars []models.UserStar
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&stars).Error; err != nil {
+		return nil, err
+	}
+	return stars, nil
+}
+
+func (r *starRepository) AllByAsset(ctx context.Context, assetType models.AssetType, assetID uint) ([]models.UserStar, error) {
+	var stars []models.UserStar
+	if err := r.db.WithContext(ctx).Where("type = ? AND asset_id = ?", assetType, assetID).Order("id").Find(&stars).Error; err != nil {
+		return nil, err
+	}
+	return stars, nil
+}
+
+func (r *starRepository) Get(ctx context.Context, id uint) (models.UserStar, error) {
+	var star models.UserStar
+	err := r.db.WithContext(ctx).First(&star, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return star, fmt.Errorf("%w: userstar %d", repository.ErrNotFound, id)
+	}
+	return star, err
+}
+
+func (r *starRepository) Create(ctx context.Context, star *models.UserStar) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkStarTarget(tx, star.Type, star.AssetID); err != nil {
+			return err
+		}
+		return starWriteError(tx.Create(star).Error)
+	})
+	return unavailableError(err)
+}
+
+func (r *starRepository) Update(ctx context.Context, star *models.UserStar) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkStarTarget(tx, star.Type, star.AssetID); err != nil {
+			return err
+		}
+		return starWriteError(updateRow(tx, star, "userstar", star.ID))
+	})
+	return unavailableError(err)
+}
+
+func (r *starRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.UserStar{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: userstar %d", repository.ErrNotFound, id)
+	}
+	return nil
+}
+
+func (r *starRepository) Star(ctx context.Context, userID uint, assetType models.AssetType, assetID uint) (star models.UserStar, created bool, err error) {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkStarTarget(tx, assetType, assetID); err != nil {
+			return err
+		}
+
+		star = models.UserStar{UserID: userID, Type: assetType, AssetID: assetID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&star)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			created = true
+			return nil
+		}
+
+		// Already starred, load the existing row
+		star = models.UserStar{}
+		return tx.Where("user_id = ? AND type = ? AND asset_id = ?", userID, assetType, assetID).First(&star).Error
+	})
+	return star, created, unavailableError(err)
+}
+
+func (r *starRepository) Unstar(ctx context.Context, userID uint, assetType models.AssetType, assetID uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND type = ? AND asset_id = ?", userID, assetType, assetID).
+		Delete(&models.UserStar{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *starRepository) UpdateDescription(ctx context.Context, userID uint, assetType models.AssetType, assetID uint, description string) (models.UserStar, error) {
+	tx := r.db.WithContext(ctx)
+
+	var star models.UserStar
+	err := tx.Where("user_id = ? AND type = ? AND asset_id = ?", userID, assetType, assetID).First(&star).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return star, repository.ErrStarNotFound
+	}
+	if err != nil {
+		return star, err
+	}
+
+	result := tx.Model(&star).Update("description", description)
+	if result.Error != nil {
+		return star, result.Error
+	}
+	if result.RowsAffected == 0 {
+		// unstarred concurrently
+		return star, repository.ErrStarNotFound
+	}
+	return star, nil
+}
+
+// starWriteError translates the unique index violation of a star write
+func starWriteError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return repository.ErrDuplicateStar
+	}
+	return err
+}
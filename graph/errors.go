@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+
+	"platform-go-challenge/models"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// NewHandler returns the GraphQL server of the schema with the given
+// resolvers, reporting domain errors with ErrorPresenter
+func NewHandler(resolvers ResolverRoot) *handler.Server {
+	server := handler.NewDefaultServer(NewExecutableSchema(Config{Resolvers: resolvers}))
+	server.SetErrorPresenter(ErrorPresenter)
+	return server
+}
+
+// ErrorPresenter reports resolver errors with the code of their domain error
+// in extensions.code, the invalid fields of validation errors in
+// extensions.fields and the retry hint of unavailable dependencies, in
+// seconds, in extensions.retryAfter. Unexpected errors and unavailable
+// dependencies are logged and reported without their details. Errors raised
+// by gqlgen itself, such as query parsing and validation errors, keep their
+// own codes.
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) && gqlErr.Err == nil {
+		return presented
+	}
+	if _, ok := presented.Extensions["code"]; ok {
+		return presented
+	}
+
+	domainErr := models.AsError(err)
+	switch domainErr.Kind {
+	case models.ErrorInternal:
+		log.Printf("graphql %s: %s", presented.Path, presented.Message)
+		presented.Message = domainErr.Message
+	case models.ErrorUnavailable:
+		log.Printf("graphql %s: %s", presented.Path, presented.Message)
+		presented.Message = "service unavailable, retry later"
+	}
+	if presented.Extensions == nil {
+		presented.Extensions = map[string]any{}
+	}
+	presented.Extensions["code"] = domainErr.Code
+	if len(domainErr.Fields) > 0 {
+		presented.Extensions["fields"] = domainErr.Fields
+	}
+	if domainErr.RetryAfter > 0 {
+		presented.Extensions["retryAfter"] = int(math.Ceil(domainErr.RetryAfter.Seconds()))
+	}
+	return presented
+}
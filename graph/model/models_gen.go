@@ -6,58 +6,126 @@ import (
 	"platform-go-challenge/models"
 )
 
+type AudienceConnection struct {
+	Edges    []*AudienceEdge  `json:"edges"`
+	PageInfo *models.PageInfo `json:"pageInfo"`
+}
+
+type AudienceEdge struct {
+	Cursor string           `json:"cursor"`
+	Node   *models.Audience `json:"node"`
+}
+
+type ChartConnection struct {
+	Edges    []*ChartEdge     `json:"edges"`
+	PageInfo *models.PageInfo `json:"pageInfo"`
+}
+
+type ChartEdge struct {
+	Cursor string        `json:"cursor"`
+	Node   *models.Chart `json:"node"`
+}
+
+type ChartSeriesInput struct {
+	Name   string    `json:"name"`
+	Points []float64 `json:"points"`
+}
+
+type FavouriteConnection struct {
+	Edges    []*FavouriteEdge `json:"edges"`
+	PageInfo *models.PageInfo `json:"pageInfo"`
+}
+
+// A starred asset with the cursor of the page following it
+type FavouriteEdge struct {
+	Cursor string       `json:"cursor"`
+	Node   models.Asset `json:"node"`
+}
+
+type InsightConnection struct {
+	Edges    []*InsightEdge   `json:"edges"`
+	PageInfo *models.PageInfo `json:"pageInfo"`
+}
+
+type InsightEdge struct {
+	Cursor string          `json:"cursor"`
+	Node   *models.Insight `json:"node"`
+}
+
 type Mutation struct {
 }
 
 type NewAudience struct {
-	Gender        string `json:"gender"`
-	Birthcountry  string `json:"birthcountry"`
-	Agegroup      string `json:"agegroup"`
-	Dailyhours    int    `json:"dailyhours"`
-	Noofpurchases int    `json:"noofpurchases"`
+	Criteria   *models.AudienceCriteria `json:"criteria,omitempty"`
+	Expression *string                  `json:"expression,omitempty"`
 }
 
 type NewChart struct {
-	Title      string `json:"title"`
-	Xaxistitle string `json:"xaxistitle"`
-	Yaxistitle string `json:"yaxistitle"`
+	Title string `json:"title"`
+	// Defaults to BAR
+	Charttype *models.ChartType `json:"charttype,omitempty"`
+	// Must be empty for PIE charts
+	Xaxistitle *string `json:"xaxistitle,omitempty"`
+	// Must be empty for PIE charts
+	Yaxistitle *string             `json:"yaxistitle,omitempty"`
+	Labels     []string            `json:"labels,omitempty"`
+	Series     []*ChartSeriesInput `json:"series,omitempty"`
+	Config     *models.ChartConfig `json:"config,omitempty"`
 }
 
 type NewInsight struct {
+	// Insight template, which may contain placeholders
 	Text string `json:"text"`
 }
 
 type NewUserStar struct {
-	Userid  int    `json:"userid"`
-	Type    string `json:"type"`
-	Assetid int    `json:"assetid"`
+	Userid      int     `json:"userid"`
+	Type        string  `json:"type"`
+	Assetid     int     `json:"assetid"`
+	Description *string `json:"description,omitempty"`
 }
 
 type Query struct {
 }
 
 type UpdateAudience struct {
-	Gender        *string `json:"gender,omitempty"`
-	Birthcountry  *string `json:"birthcountry,omitempty"`
-	Agegroup      *string `json:"agegroup,omitempty"`
-	Dailyhours    *int    `json:"dailyhours,omitempty"`
-	Noofpurchases *int    `json:"noofpurchases,omitempty"`
+	// Replaces all criteria of the audience
+	Criteria *models.AudienceCriteria `json:"criteria,omitempty"`
+	// Replaces the expression of the audience, an empty string removes it
+	Expression *string `json:"expression,omitempty"`
 }
 
 type UpdateChart struct {
-	Title      *string `json:"title,omitempty"`
-	Xaxistitle *string `json:"xaxistitle,omitempty"`
-	Yaxistitle *string `json:"yaxistitle,omitempty"`
+	Title      *string             `json:"title,omitempty"`
+	Charttype  *models.ChartType   `json:"charttype,omitempty"`
+	Xaxistitle *string             `json:"xaxistitle,omitempty"`
+	Yaxistitle *string             `json:"yaxistitle,omitempty"`
+	Labels     []string            `json:"labels,omitempty"`
+	Series     []*ChartSeriesInput `json:"series,omitempty"`
+	// Replaces the configuration of the chart
+	Config *models.ChartConfig `json:"config,omitempty"`
 }
 
 type UpdateInsight struct {
+	// Insight template, which may contain placeholders
 	Text *string `json:"text,omitempty"`
 }
 
 type UpdateUserStar struct {
-	Userid  *int    `json:"userid,omitempty"`
-	Type    *string `json:"type,omitempty"`
-	Assetid *int    `json:"assetid,omitempty"`
+	Userid      *int    `json:"userid,omitempty"`
+	Type        *string `json:"type,omitempty"`
+	Assetid     *int    `json:"assetid,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+type UserStarConnection struct {
+	Edges    []*UserStarEdge  `json:"edges"`
+	PageInfo *models.PageInfo `json:"pageInfo"`
+}
+
+type UserStarEdge struct {
+	Cursor string           `json:"cursor"`
+	Node   *models.UserStar `json:"node"`
 }
 
 type UserStared struct {
@@ -65,4 +133,8 @@ type UserStared struct {
 	Audience []*models.Audience `json:"audience"`
 	Chart    []*models.Chart    `json:"chart"`
 	Insight  []*models.Insight  `json:"insight"`
+	Stars    []*models.UserStar `json:"stars"`
+	// The stars of the page in order, each with the cursor of the page following it
+	Edges    []*UserStarEdge  `json:"edges"`
+	PageInfo *models.PageInfo `json:"pageInfo"`
 }
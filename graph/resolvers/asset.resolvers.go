@@ -0,0 +1,66 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.83
+
+import (
+	"context"
+	"fmt"
+	"platform-go-challenge/graph/model"
+	"platform-go-challenge/models"
+)
+
+// LinkSource is the resolver for the linkSource field.
+func (r *mutationResolver) LinkSource(ctx context.Context, typeArg string, id string, sourceType string, sourceID string) (models.Asset, error) {
+	link, err := parseLinkArgs(typeArg, id, sourceType, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.Repos.Links.Link(ctx, link); err != nil {
+		return nil, err
+	}
+	return r.loadAsset(ctx, link.TargetType, link.TargetID)
+}
+
+// UnlinkSource is the resolver for the unlinkSource field.
+func (r *mutationResolver) UnlinkSource(ctx context.Context, typeArg string, id string, sourceType string, sourceID string) (models.Asset, error) {
+	link, err := parseLinkArgs(typeArg, id, sourceType, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.Repos.Links.Unlink(ctx, link); err != nil {
+		return nil, err
+	}
+	return r.loadAsset(ctx, link.TargetType, link.TargetID)
+}
+
+// Favourites is the resolver for the favourites field.
+func (r *queryResolver) Favourites(ctx context.Context, userID string, first *int, after *string) (*model.FavouriteConnection, error) {
+	id, err := parseID("userID", userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	page, err := pageRequest(first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	// Favourites are returned in the order the user starred the assets
+	result, err := r.Repos.Favourites.ListByUser(ctx, id, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch favourites: %w", err)
+	}
+
+	connection := &model.FavouriteConnection{
+		Edges:    make([]*model.FavouriteEdge, 0, len(result.Items)),
+		PageInfo: &result.PageInfo,
+	}
+	for _, favourite := range result.Items {
+		connection.Edges = append(connection.Edges, &model.FavouriteEdge{Cursor: models.EncodeCursor(favourite.ID), Node: favourite.Asset})
+	}
+	return connection, nil
+}
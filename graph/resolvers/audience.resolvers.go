@@ -10,6 +10,7 @@ import (
 	"platform-go-challenge/graph"
 	"platform-go-challenge/graph/model"
 	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
 )
 
 // ID is the resolver for the id field.
@@ -17,17 +18,34 @@ func (r *audienceResolver) ID(ctx context.Context, obj *models.Audience) (string
 	return fmt.Sprintf("%d", obj.ID), nil
 }
 
+// Type is the resolver for the type field.
+func (r *audienceResolver) Type(ctx context.Context, obj *models.Audience) (string, error) {
+	return obj.AssetType().String(), nil
+}
+
+// Charts is the resolver for the charts field.
+func (r *audienceResolver) Charts(ctx context.Context, obj *models.Audience) ([]*models.Chart, error) {
+	charts, err := repository.LoadTargets(ctx, r.Repos.Links, r.Repos.Charts, models.AssetTypeAudience, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return pointers(charts), nil
+}
+
 // CreateAudience is the resolver for the createAudience field.
 func (r *mutationResolver) CreateAudience(ctx context.Context, input model.NewAudience) (*models.Audience, error) {
-	audience := &models.Audience{
-		Gender:        input.Gender,
-		BirthCountry:  input.Birthcountry,
-		AgeGroup:      input.Agegroup,
-		DailyHours:    input.Dailyhours,
-		NoOfPurchases: input.Noofpurchases,
+	audience := &models.Audience{}
+	if input.Criteria != nil {
+		audience.Criteria = *input.Criteria
+	}
+	if input.Expression != nil {
+		audience.Expression = *input.Expression
 	}
 
-	if err := r.DB.Create(audience).Error; err != nil {
+	if err := audience.Validate(); err != nil {
+		return nil, err
+	}
+	if err := r.Repos.Audiences.Create(ctx, audience); err != nil {
 		return nil, err
 	}
 
@@ -36,61 +54,132 @@ func (r *mutationResolver) CreateAudience(ctx context.Context, input model.NewAu
 
 // UpdateAudience is the resolver for the updateAudience field.
 func (r *mutationResolver) UpdateAudience(ctx context.Context, id string, input model.UpdateAudience) (*models.Audience, error) {
-	var audience models.Audience
-	if err := r.DB.First(&audience, id).Error; err != nil {
-		return nil, fmt.Errorf("audience not found")
+	audienceID, err := parseID("id", id)
+	if err != nil {
+		return nil, err
 	}
 
-	if input.Gender != nil {
-		audience.Gender = *input.Gender
-	}
-	if input.Birthcountry != nil {
-		audience.BirthCountry = *input.Birthcountry
+	audience, err := r.Repos.Audiences.Get(ctx, audienceID)
+	if err != nil {
+		return nil, notFoundError(err, "audience not found")
 	}
-	if input.Agegroup != nil {
-		audience.AgeGroup = *input.Agegroup
-	}
-	if input.Dailyhours != nil {
-		audience.DailyHours = *input.Dailyhours
+
+	if input.Criteria != nil {
+		audience.Criteria = *input.Criteria
 	}
-	if input.Noofpurchases != nil {
-		audience.NoOfPurchases = *input.Noofpurchases
+	if input.Expression != nil {
+		audience.Expression = *input.Expression
 	}
 
-	if err := r.DB.Save(&audience).Error; err != nil {
+	if err := audience.Validate(); err != nil {
 		return nil, err
 	}
+	if err := r.Repos.Audiences.Update(ctx, &audience); err != nil {
+		return nil, notFoundError(err, "audience not found")
+	}
 
 	return &audience, nil
 }
 
 // DeleteAudience is the resolver for the deleteAudience field.
 func (r *mutationResolver) DeleteAudience(ctx context.Context, id string) (bool, error) {
-	var audience models.Audience
-	if err := r.DB.Delete(&audience, id).Error; err != nil {
+	audienceID, err := parseID("id", id)
+	if err != nil {
 		return false, err
 	}
+
+	if err := r.Repos.Audiences.Delete(ctx, audienceID); err != nil {
+		return false, notFoundError(err, "audience not found")
+	}
 	return true, nil
 }
 
 // Audiences is the resolver for the audiences field.
-func (r *queryResolver) Audiences(ctx context.Context) ([]*models.Audience, error) {
-	var audiences []*models.Audience
-	if err := r.DB.Find(&audiences).Error; err != nil {
+func (r *queryResolver) Audiences(ctx context.Context, first *int, after *string) (*model.AudienceConnection, error) {
+	page, err := pageRequest(first, after)
+	if err != nil {
 		return nil, err
 	}
-	return audiences, nil
+
+	result, err := r.Repos.Audiences.List(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &model.AudienceConnection{
+		Edges:    make([]*model.AudienceEdge, 0, len(result.Items)),
+		PageInfo: &result.PageInfo,
+	}
+	for i := range result.Items {
+		audience := &result.Items[i]
+		connection.Edges = append(connection.Edges, &model.AudienceEdge{Cursor: models.EncodeCursor(audience.ID), Node: audience})
+	}
+	return connection, nil
 }
 
 // Audience is the resolver for the audience field.
 func (r *queryResolver) Audience(ctx context.Context, id string) (*models.Audience, error) {
-	var audience models.Audience
-	if err := r.DB.First(&audience, id).Error; err != nil {
-		return nil, fmt.Errorf("audience not found")
+	audienceID, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	audience, err := r.Repos.Audiences.Get(ctx, audienceID)
+	if err != nil {
+		return nil, notFoundError(err, "audience not found")
 	}
 	return &audience, nil
 }
 
+// AudienceSize is the resolver for the audienceSize field.
+func (r *queryResolver) AudienceSize(ctx context.Context, id string) (*models.AudienceSize, error) {
+	audienceID, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	audience, err := r.Repos.Audiences.Get(ctx, audienceID)
+	if err != nil {
+		return nil, notFoundError(err, "audience not found")
+	}
+
+	size, err := r.Repos.AudienceSizes.Size(ctx, audience)
+	if err != nil {
+		return nil, err
+	}
+	return &size, nil
+}
+
+// CompareAudiences is the resolver for the compareAudiences field.
+func (r *queryResolver) CompareAudiences(ctx context.Context, ids []string) (*models.AudienceComparison, error) {
+	audienceIDs := make([]uint, len(ids))
+	for i, id := range ids {
+		audienceID, err := parseID(fmt.Sprintf("ids[%d]", i), id)
+		if err != nil {
+			return nil, err
+		}
+		audienceIDs[i] = audienceID
+	}
+	if err := models.ValidateComparedAudiences(audienceIDs); err != nil {
+		return nil, err
+	}
+
+	audiences := make([]models.Audience, len(audienceIDs))
+	for i, audienceID := range audienceIDs {
+		audience, err := r.Repos.Audiences.Get(ctx, audienceID)
+		if err != nil {
+			return nil, notFoundError(err, fmt.Sprintf("audience %d not found", audienceID))
+		}
+		audiences[i] = audience
+	}
+
+	comparison, err := r.Repos.AudienceSizes.Compare(ctx, audiences)
+	if err != nil {
+		return nil, err
+	}
+	return &comparison, nil
+}
+
 // Audience returns graph.AudienceResolver implementation.
 func (r *Resolver) Audience() graph.AudienceResolver { return &audienceResolver{r} }
 
@@ -10,6 +10,7 @@ import (
 	"platform-go-challenge/graph"
 	"platform-go-challenge/graph/model"
 	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
 )
 
 // ID is the resolver for the id field.
@@ -17,15 +18,50 @@ func (r *chartResolver) ID(ctx context.Context, obj *models.Chart) (string, erro
 	return fmt.Sprintf("%d", obj.ID), nil
 }
 
+// Type is the resolver for the type field.
+func (r *chartResolver) Type(ctx context.Context, obj *models.Chart) (string, error) {
+	return obj.AssetType().String(), nil
+}
+
+// Insights is the resolver for the insights field.
+func (r *chartResolver) Insights(ctx context.Context, obj *models.Chart) ([]*models.Insight, error) {
+	insights, err := repository.LoadTargets(ctx, r.Repos.Links, r.Repos.Insights, models.AssetTypeChart, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return pointers(insights), nil
+}
+
+// Audienceid is the resolver for the audienceid field.
+func (r *chartProvenanceResolver) Audienceid(ctx context.Context, obj *models.ChartProvenance) (string, error) {
+	return fmt.Sprintf("%d", obj.AudienceID), nil
+}
+
 // CreateChart is the resolver for the createChart field.
 func (r *mutationResolver) CreateChart(ctx context.Context, input model.NewChart) (*models.Chart, error) {
 	chart := &models.Chart{
-		Title:      input.Title,
-		XAxisTitle: input.Xaxistitle,
-		YAxisTitle: input.Yaxistitle,
+		Title:  input.Title,
+		Labels: input.Labels,
+		Series: toChartSeries(input.Series),
+	}
+	if input.Charttype != nil {
+		chart.ChartType = *input.Charttype
+	}
+	if input.Xaxistitle != nil {
+		chart.XAxisTitle = *input.Xaxistitle
+	}
+	if input.Yaxistitle != nil {
+		chart.YAxisTitle = *input.Yaxistitle
+	}
+	if input.Config != nil {
+		chart.Config = *input.Config
 	}
 
-	if err := r.DB.Create(chart).Error; err != nil {
+	if err := chart.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Repos.Charts.Create(ctx, chart); err != nil {
 		return nil, err
 	}
 
@@ -34,51 +70,123 @@ func (r *mutationResolver) CreateChart(ctx context.Context, input model.NewChart
 
 // UpdateChart is the resolver for the updateChart field.
 func (r *mutationResolver) UpdateChart(ctx context.Context, id string, input model.UpdateChart) (*models.Chart, error) {
-	var chart models.Chart
-	if err := r.DB.First(&chart, id).Error; err != nil {
-		return nil, fmt.Errorf("chart not found")
+	chartID, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	chart, err := r.Repos.Charts.Get(ctx, chartID)
+	if err != nil {
+		return nil, notFoundError(err, "chart not found")
 	}
 
 	if input.Title != nil {
 		chart.Title = *input.Title
 	}
+	if input.Charttype != nil {
+		chart.ChartType = *input.Charttype
+	}
 	if input.Xaxistitle != nil {
 		chart.XAxisTitle = *input.Xaxistitle
 	}
 	if input.Yaxistitle != nil {
 		chart.YAxisTitle = *input.Yaxistitle
 	}
+	if input.Labels != nil {
+		chart.Labels = input.Labels
+	}
+	if input.Series != nil {
+		chart.Series = toChartSeries(input.Series)
+	}
+	if input.Config != nil {
+		chart.Config = *input.Config
+	}
 
-	if err := r.DB.Save(&chart).Error; err != nil {
+	if err := chart.Validate(); err != nil {
 		return nil, err
 	}
 
+	if err := r.Repos.Charts.Update(ctx, &chart); err != nil {
+		return nil, notFoundError(err, "chart not found")
+	}
+
 	return &chart, nil
 }
 
 // DeleteChart is the resolver for the deleteChart field.
 func (r *mutationResolver) DeleteChart(ctx context.Context, id string) (bool, error) {
-	var chart models.Chart
-	if err := r.DB.Delete(&chart, id).Error; err != nil {
+	chartID, err := parseID("id", id)
+	if err != nil {
 		return false, err
 	}
+
+	if err := r.Repos.Charts.Delete(ctx, chartID); err != nil {
+		return false, notFoundError(err, "chart not found")
+	}
 	return true, nil
 }
 
+// GenerateChart is the resolver for the generateChart field.
+func (r *mutationResolver) GenerateChart(ctx context.Context, audienceID string, dimension models.Dimension) (*models.Chart, error) {
+	id, err := parseID("audienceID", audienceID)
+	if err != nil {
+		return nil, err
+	}
+
+	chart, err := r.Repos.ChartGenerator.Generate(ctx, id, dimension)
+	if err != nil {
+		return nil, notFoundError(err, "audience not found")
+	}
+	return &chart, nil
+}
+
+// RefreshChart is the resolver for the refreshChart field.
+func (r *mutationResolver) RefreshChart(ctx context.Context, id string) (*models.Chart, error) {
+	chartID, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	chart, err := r.Repos.ChartGenerator.Refresh(ctx, chartID)
+	if err != nil {
+		return nil, notFoundError(err, "chart not found")
+	}
+	return &chart, nil
+}
+
 // Charts is the resolver for the charts field.
-func (r *queryResolver) Charts(ctx context.Context) ([]*models.Chart, error) {
-	var charts []*models.Chart
-	if err := r.DB.Find(&charts).Error; err != nil {
+func (r *queryResolver) Charts(ctx context.Context, first *int, after *string) (*model.ChartConnection, error) {
+	page, err := pageRequest(first, after)
+	if err != nil {
 		return nil, err
 	}
-	return charts, nil
+
+	result, err := r.Repos.Charts.List(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &model.ChartConnection{
+		Edges:    make([]*model.ChartEdge, 0, len(result.Items)),
+		PageInfo: &result.PageInfo,
+	}
+	for i := range result.Items {
+		chart := &result.Items[i]
+		connection.Edges = append(connection.Edges, &model.ChartEdge{Cursor: models.EncodeCursor(chart.ID), Node: chart})
+	}
+	return connection, nil
 }
 
 // Chart is the resolver for the chart field.
 func (r *queryResolver) Chart(ctx context.Context, id string) (*models.Chart, error) {
-	var chart models.Chart
-	if err := r.DB.First(&chart, id).Error; err != nil {
-		return nil, fmt.Errorf("chart not found")
+	chartID, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	chart, err := r.Repos.Charts.Get(ctx, chartID)
+	if err != nil {
+		return nil, notFoundError(err, "chart not found")
 	}
 	return &chart, nil
 }
@@ -86,4 +194,10 @@ func (r *queryResolver) Chart(ctx context.Context, id string) (*models.Chart, er
 // Chart returns graph.ChartResolver implementation.
 func (r *Resolver) Chart() graph.ChartResolver { return &chartResolver{r} }
 
+// ChartProvenance returns graph.ChartProvenanceResolver implementation.
+func (r *Resolver) ChartProvenance() graph.ChartProvenanceResolver {
+	return &chartProvenanceResolver{r}
+}
+
 type chartResolver struct{ *Resolver }
+type chartProvenanceResolver struct{ *Resolver }
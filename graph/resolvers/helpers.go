@@ -0,0 +1,130 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"platform-go-challenge/graph/model"
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+)
+
+// notFoundError replaces the message of repository.ErrNotFound
+func notFoundError(err error, message string) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return repository.ErrNotFound.Withf("%s", message)
+	}
+	return err
+}
+
+// toUint converts a GraphQL Int argument into an ID, rejecting negative values
+func toUint(field string, value int) (uint, error) {
+	if value < 0 {
+		return 0, models.InvalidField(field, "must not be negative")
+	}
+	return uint(value), nil
+}
+
+// parseID converts the GraphQL ID argument named field into a numeric ID
+func parseID(field, id string) (uint, error) {
+	value, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, models.InvalidField(field, "invalid ID %q", id)
+	}
+	return uint(value), nil
+}
+
+// parseStarArgs converts the arguments of the star and unstar mutations.
+// The asset type is matched case-insensitively.
+func parseStarArgs(userID, assetType, assetID string) (uint, models.AssetType, uint, error) {
+	uid, err := parseID("userID", userID)
+	if err != nil {
+		return 0, "", 0, err
+	}
+
+	at, err := models.ParseAssetType(assetType)
+	if err != nil {
+		return 0, "", 0, err
+	}
+
+	aid, err := parseID("assetID", assetID)
+	if err != nil {
+		return 0, "", 0, err
+	}
+
+	return uid, at, aid, nil
+}
+
+// parseLinkArgs converts the arguments of the linkSource and unlinkSource
+// mutations into the link from the source to the asset. Asset types are
+// matched case-insensitively.
+func parseLinkArgs(assetType, id, sourceType, sourceID string) (models.AssetLink, error) {
+	var link models.AssetLink
+	var err error
+	if link.TargetType, err = models.ParseAssetType(assetType); err != nil {
+		return link, err
+	}
+	if link.TargetID, err = parseID("id", id); err != nil {
+		return link, err
+	}
+	if link.SourceType, err = models.ParseAssetType(sourceType); err != nil {
+		return link, models.InvalidField("sourceType", "invalid asset type %q: must be one of %s, %s or %s",
+			sourceType, models.AssetTypeAudience, models.AssetTypeChart, models.AssetTypeInsight)
+	}
+	if link.SourceID, err = parseID("sourceID", sourceID); err != nil {
+		return link, err
+	}
+	return link, link.Validate()
+}
+
+// loadAsset returns the asset of the given type and ID
+func (r *Resolver) loadAsset(ctx context.Context, assetType models.AssetType, id uint) (models.Asset, error) {
+	var asset models.Asset
+	var err error
+	switch assetType {
+	case models.AssetTypeAudience:
+		asset, err = r.Repos.Audiences.Get(ctx, id)
+	case models.AssetTypeChart:
+		asset, err = r.Repos.Charts.Get(ctx, id)
+	default:
+		asset, err = r.Repos.Insights.Get(ctx, id)
+	}
+	if err != nil {
+		return nil, notFoundError(err, fmt.Sprintf("%s not found", strings.ToLower(string(assetType))))
+	}
+	return asset, nil
+}
+
+// pointers returns pointers to the elements of values, as list fields of
+// objects resolve to
+func pointers[T any](values []T) []*T {
+	result := make([]*T, len(values))
+	for i := range values {
+		result[i] = &values[i]
+	}
+	return result
+}
+
+// toChartSeries converts GraphQL series inputs into chart series models
+func toChartSeries(inputs []*model.ChartSeriesInput) []models.ChartSeries {
+	if inputs == nil {
+		return nil
+	}
+	series := make([]models.ChartSeries, len(inputs))
+	for i, input := range inputs {
+		series[i] = models.ChartSeries{Name: input.Name, Points: input.Points}
+	}
+	return series
+}
+
+// pageRequest converts the first and after arguments of a connection field
+func pageRequest(first *int, after *string) (models.PageRequest, error) {
+	cursor := ""
+	if after != nil {
+		cursor = *after
+	}
+	return models.NewPageRequest(first, cursor)
+}
@@ -17,13 +17,36 @@ func (r *insightResolver) ID(ctx context.Context, obj *models.Insight) (string,
 	return fmt.Sprintf("%d", obj.ID), nil
 }
 
+// Type is the resolver for the type field.
+func (r *insightResolver) Type(ctx context.Context, obj *models.Insight) (string, error) {
+	return obj.AssetType().String(), nil
+}
+
+// Text is the resolver for the text field.
+func (r *insightResolver) Text(ctx context.Context, obj *models.Insight) (string, error) {
+	return r.Repos.InsightRenderer.Render(ctx, *obj)
+}
+
+// Template is the resolver for the template field.
+func (r *insightResolver) Template(ctx context.Context, obj *models.Insight) (string, error) {
+	return obj.Text, nil
+}
+
+// Sources is the resolver for the sources field.
+func (r *insightResolver) Sources(ctx context.Context, obj *models.Insight) ([]models.Asset, error) {
+	return r.Repos.LoadSources(ctx, models.AssetTypeInsight, obj.ID)
+}
+
 // CreateInsight is the resolver for the createInsight field.
 func (r *mutationResolver) CreateInsight(ctx context.Context, input model.NewInsight) (*models.Insight, error) {
 	insight := &models.Insight{
 		Text: input.Text,
 	}
 
-	if err := r.DB.Create(insight).Error; err != nil {
+	if err := insight.Validate(); err != nil {
+		return nil, err
+	}
+	if err := r.Repos.Insights.Create(ctx, insight); err != nil {
 		return nil, err
 	}
 
@@ -32,45 +55,76 @@ func (r *mutationResolver) CreateInsight(ctx context.Context, input model.NewIns
 
 // UpdateInsight is the resolver for the updateInsight field.
 func (r *mutationResolver) UpdateInsight(ctx context.Context, id string, input model.UpdateInsight) (*models.Insight, error) {
-	var insight models.Insight
-	if err := r.DB.First(&insight, id).Error; err != nil {
-		return nil, fmt.Errorf("insight not found")
+	insightID, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	insight, err := r.Repos.Insights.Get(ctx, insightID)
+	if err != nil {
+		return nil, notFoundError(err, "insight not found")
 	}
 
 	if input.Text != nil {
 		insight.Text = *input.Text
 	}
 
-	if err := r.DB.Save(&insight).Error; err != nil {
+	if err := insight.Validate(); err != nil {
 		return nil, err
 	}
+	if err := r.Repos.Insights.Update(ctx, &insight); err != nil {
+		return nil, notFoundError(err, "insight not found")
+	}
 
 	return &insight, nil
 }
 
 // DeleteInsight is the resolver for the deleteInsight field.
 func (r *mutationResolver) DeleteInsight(ctx context.Context, id string) (bool, error) {
-	var insight models.Insight
-	if err := r.DB.Delete(&insight, id).Error; err != nil {
+	insightID, err := parseID("id", id)
+	if err != nil {
 		return false, err
 	}
+
+	if err := r.Repos.Insights.Delete(ctx, insightID); err != nil {
+		return false, notFoundError(err, "insight not found")
+	}
 	return true, nil
 }
 
 // Insights is the resolver for the insights field.
-func (r *queryResolver) Insights(ctx context.Context) ([]*models.Insight, error) {
-	var insights []*models.Insight
-	if err := r.DB.Find(&insights).Error; err != nil {
+func (r *queryResolver) Insights(ctx context.Context, first *int, after *string) (*model.InsightConnection, error) {
+	page, err := pageRequest(first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.Repos.Insights.List(ctx, page)
+	if err != nil {
 		return nil, err
 	}
-	return insights, nil
+
+	connection := &model.InsightConnection{
+		Edges:    make([]*model.InsightEdge, 0, len(result.Items)),
+		PageInfo: &result.PageInfo,
+	}
+	for i := range result.Items {
+		insight := &result.Items[i]
+		connection.Edges = append(connection.Edges, &model.InsightEdge{Cursor: models.EncodeCursor(insight.ID), Node: insight})
+	}
+	return connection, nil
 }
 
 // Insight is the resolver for the insight field.
 func (r *queryResolver) Insight(ctx context.Context, id string) (*models.Insight, error) {
-	var insight models.Insight
-	if err := r.DB.First(&insight, id).Error; err != nil {
-		return nil, fmt.Errorf("insight not found")
+	insightID, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	insight, err := r.Repos.Insights.Get(ctx, insightID)
+	if err != nil {
+		return nil, notFoundError(err, "insight not found")
 	}
 	return &insight, nil
 }
@@ -2,12 +2,12 @@ package resolvers
 
 import (
 	"platform-go-challenge/graph"
-
-	"gorm.io/gorm"
+	"platform-go-challenge/repository"
 )
 
+// Resolver serves the GraphQL API on top of the storage repositories
 type Resolver struct {
-	DB *gorm.DB
+	Repos repository.Repositories
 }
 
 // Ensure Resolver implements the graph.ResolverRoot interface
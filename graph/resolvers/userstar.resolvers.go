@@ -14,45 +14,206 @@ import (
 
 // CreateUserStar is the resolver for the createUserStar field.
 func (r *mutationResolver) CreateUserStar(ctx context.Context, input model.NewUserStar) (*models.UserStar, error) {
-	panic(fmt.Errorf("not implemented: CreateUserStar - createUserStar"))
+	userstar := &models.UserStar{
+		Type: models.AssetType(input.Type),
+	}
+	if input.Description != nil {
+		userstar.Description = *input.Description
+	}
+
+	userID, err := toUint("userid", input.Userid)
+	if err != nil {
+		return nil, err
+	}
+	userstar.UserID = userID
+
+	assetID, err := toUint("assetid", input.Assetid)
+	if err != nil {
+		return nil, err
+	}
+	userstar.AssetID = assetID
+
+	if err := r.Repos.Stars.Create(ctx, userstar); err != nil {
+		return nil, err
+	}
+
+	return userstar, nil
 }
 
 // UpdateUserStar is the resolver for the updateUserStar field.
 func (r *mutationResolver) UpdateUserStar(ctx context.Context, id string, input model.UpdateUserStar) (*models.UserStar, error) {
-	panic(fmt.Errorf("not implemented: UpdateUserStar - updateUserStar"))
+	starID, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	userstar, err := r.Repos.Stars.Get(ctx, starID)
+	if err != nil {
+		return nil, notFoundError(err, "userstar not found")
+	}
+
+	if input.Userid != nil {
+		userID, err := toUint("userid", *input.Userid)
+		if err != nil {
+			return nil, err
+		}
+		userstar.UserID = userID
+	}
+	if input.Type != nil {
+		userstar.Type = models.AssetType(*input.Type)
+	}
+	if input.Description != nil {
+		userstar.Description = *input.Description
+	}
+	if input.Assetid != nil {
+		assetID, err := toUint("assetid", *input.Assetid)
+		if err != nil {
+			return nil, err
+		}
+		userstar.AssetID = assetID
+	}
+
+	if err := r.Repos.Stars.Update(ctx, &userstar); err != nil {
+		return nil, notFoundError(err, "userstar not found")
+	}
+
+	return &userstar, nil
 }
 
 // DeleteUserStar is the resolver for the deleteUserStar field.
 func (r *mutationResolver) DeleteUserStar(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteUserStar - deleteUserStar"))
+	starID, err := parseID("id", id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.Repos.Stars.Delete(ctx, starID); err != nil {
+		return false, notFoundError(err, "userstar not found")
+	}
+	return true, nil
+}
+
+// Star is the resolver for the star field.
+func (r *mutationResolver) Star(ctx context.Context, userID string, typeArg string, assetID string) (*models.StarStatus, error) {
+	uid, assetType, aid, err := parseStarArgs(userID, typeArg, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	star, _, err := r.Repos.Stars.Star(ctx, uid, assetType, aid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.StarStatus{UserID: uid, Type: assetType, AssetID: aid, Starred: true, Star: &star}, nil
+}
+
+// Unstar is the resolver for the unstar field.
+func (r *mutationResolver) Unstar(ctx context.Context, userID string, typeArg string, assetID string) (*models.StarStatus, error) {
+	uid, assetType, aid, err := parseStarArgs(userID, typeArg, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.Repos.Stars.Unstar(ctx, uid, assetType, aid); err != nil {
+		return nil, err
+	}
+
+	return &models.StarStatus{UserID: uid, Type: assetType, AssetID: aid, Starred: false}, nil
+}
+
+// UpdateFavouriteDescription is the resolver for the updateFavouriteDescription field.
+func (r *mutationResolver) UpdateFavouriteDescription(ctx context.Context, userID string, typeArg string, assetID string, description string) (*models.UserStar, error) {
+	uid, assetType, aid, err := parseStarArgs(userID, typeArg, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	star, err := r.Repos.Stars.UpdateDescription(ctx, uid, assetType, aid, description)
+	if err != nil {
+		return nil, err
+	}
+
+	return &star, nil
 }
 
 // Userstars is the resolver for the userstars field.
-func (r *queryResolver) Userstars(ctx context.Context) ([]*models.UserStar, error) {
-	panic(fmt.Errorf("not implemented: Userstars - userstars"))
+func (r *queryResolver) Userstars(ctx context.Context, first *int, after *string) (*model.UserStarConnection, error) {
+	page, err := pageRequest(first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.Repos.Stars.List(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &model.UserStarConnection{
+		Edges:    make([]*model.UserStarEdge, 0, len(result.Items)),
+		PageInfo: &result.PageInfo,
+	}
+	for i := range result.Items {
+		userstar := &result.Items[i]
+		connection.Edges = append(connection.Edges, &model.UserStarEdge{Cursor: models.EncodeCursor(userstar.ID), Node: userstar})
+	}
+	return connection, nil
 }
 
 // Userstar is the resolver for the userstar field.
 func (r *queryResolver) Userstar(ctx context.Context, id string) (*models.UserStar, error) {
-	panic(fmt.Errorf("not implemented: Userstar - userstar"))
+	starID, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	userstar, err := r.Repos.Stars.Get(ctx, starID)
+	if err != nil {
+		return nil, notFoundError(err, "userstar not found")
+	}
+	return &userstar, nil
+}
+
+// Userid is the resolver for the userid field.
+func (r *starStatusResolver) Userid(ctx context.Context, obj *models.StarStatus) (int, error) {
+	return int(obj.UserID), nil
+}
+
+// Type is the resolver for the type field.
+func (r *starStatusResolver) Type(ctx context.Context, obj *models.StarStatus) (string, error) {
+	return obj.Type.String(), nil
+}
+
+// Assetid is the resolver for the assetid field.
+func (r *starStatusResolver) Assetid(ctx context.Context, obj *models.StarStatus) (int, error) {
+	return int(obj.AssetID), nil
 }
 
 // ID is the resolver for the id field.
 func (r *userStarResolver) ID(ctx context.Context, obj *models.UserStar) (string, error) {
-	panic(fmt.Errorf("not implemented: ID - id"))
+	return fmt.Sprintf("%d", obj.ID), nil
 }
 
 // Userid is the resolver for the userid field.
 func (r *userStarResolver) Userid(ctx context.Context, obj *models.UserStar) (int, error) {
-	panic(fmt.Errorf("not implemented: Userid - userid"))
+	return int(obj.UserID), nil
+}
+
+// Type is the resolver for the type field.
+func (r *userStarResolver) Type(ctx context.Context, obj *models.UserStar) (string, error) {
+	return obj.Type.String(), nil
 }
 
 // Assetid is the resolver for the assetid field.
 func (r *userStarResolver) Assetid(ctx context.Context, obj *models.UserStar) (int, error) {
-	panic(fmt.Errorf("not implemented: Assetid - assetid"))
+	return int(obj.AssetID), nil
 }
 
+// StarStatus returns graph.StarStatusResolver implementation.
+func (r *Resolver) StarStatus() graph.StarStatusResolver { return &starStatusResolver{r} }
+
 // UserStar returns graph.UserStarResolver implementation.
 func (r *Resolver) UserStar() graph.UserStarResolver { return &userStarResolver{r} }
 
+type starStatusResolver struct{ *Resolver }
 type userStarResolver struct{ *Resolver }
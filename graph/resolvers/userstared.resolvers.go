@@ -12,81 +12,54 @@ import (
 )
 
 // Userstared is the resolver for the userstared field.
-func (r *queryResolver) Userstared(ctx context.Context, userID string) (*model.UserStared, error) {
-	// Fetch all user stars for this user
-	var userStars []models.UserStar
-	if err := r.DB.Where("user_id = ?", userID).Find(&userStars).Error; err != nil {
-		return nil, fmt.Errorf("failed to fetch user stars: %w", err)
-	}
-
-	// Initialize slices to hold IDs for each asset type
-	var audienceIDs []uint
-	var chartIDs []uint
-	var insightIDs []uint
-
-	// Group asset IDs by type
-	for _, star := range userStars {
-		switch star.Type {
-		case models.AssetTypeAudience:
-			audienceIDs = append(audienceIDs, star.AssetID)
-		case models.AssetTypeChart:
-			chartIDs = append(chartIDs, star.AssetID)
-		case models.AssetTypeInsight:
-			insightIDs = append(insightIDs, star.AssetID)
-		}
+func (r *queryResolver) Userstared(ctx context.Context, userID string, first *int, after *string) (*model.UserStared, error) {
+	id, err := parseID("userID", userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 
-	// Fetch all audiences
-	var audiences []models.Audience
-	if len(audienceIDs) > 0 {
-		if err := r.DB.Where("id IN ?", audienceIDs).Find(&audiences).Error; err != nil {
-			return nil, fmt.Errorf("failed to fetch audiences: %w", err)
-		}
+	page, err := pageRequest(first, after)
+	if err != nil {
+		return nil, err
 	}
 
-	// Fetch all charts
-	var charts []models.Chart
-	if len(chartIDs) > 0 {
-		if err := r.DB.Where("id IN ?", chartIDs).Find(&charts).Error; err != nil {
-			return nil, fmt.Errorf("failed to fetch charts: %w", err)
-		}
+	// Fetch a page of the stars of this user with the starred assets
+	favouritePage, err := r.Repos.Favourites.ListByUser(ctx, id, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch favourites: %w", err)
 	}
+	favourites := favouritePage.Items
 
-	// Fetch all insights
-	var insights []models.Insight
-	if len(insightIDs) > 0 {
-		if err := r.DB.Where("id IN ?", insightIDs).Find(&insights).Error; err != nil {
-			return nil, fmt.Errorf("failed to fetch insights: %w", err)
+	// Group the assets by type for the GraphQL response
+	gqlAudiences := []*models.Audience{}
+	gqlCharts := []*models.Chart{}
+	gqlInsights := []*models.Insight{}
+	for _, favourite := range favourites {
+		switch asset := favourite.Asset.(type) {
+		case models.Audience:
+			gqlAudiences = append(gqlAudiences, &asset)
+		case models.Chart:
+			gqlCharts = append(gqlCharts, &asset)
+		case models.Insight:
+			gqlInsights = append(gqlInsights, &asset)
 		}
 	}
 
-	// Convert to pointers for GraphQL response
-	gqlAudiences := make([]*models.Audience, len(audiences))
-	for i := range audiences {
-		gqlAudiences[i] = &audiences[i]
-	}
-
-	gqlCharts := make([]*models.Chart, len(charts))
-	for i := range charts {
-		gqlCharts[i] = &charts[i]
-	}
-
-	gqlInsights := make([]*models.Insight, len(insights))
-	for i := range insights {
-		gqlInsights[i] = &insights[i]
-	}
-
-	// Parse userID to int
-	var userIDInt int
-	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+	gqlStars := make([]*models.UserStar, len(favourites))
+	gqlEdges := make([]*model.UserStarEdge, len(favourites))
+	for i := range favourites {
+		gqlStars[i] = &favourites[i].UserStar
+		gqlEdges[i] = &model.UserStarEdge{Cursor: models.EncodeCursor(favourites[i].ID), Node: gqlStars[i]}
 	}
 
 	// Build and return the UserStared response
 	return &model.UserStared{
-		Userid:   userIDInt,
+		Userid:   int(id),
 		Audience: gqlAudiences,
 		Chart:    gqlCharts,
 		Insight:  gqlInsights,
+		Stars:    gqlStars,
+		Edges:    gqlEdges,
+		PageInfo: &favouritePage.PageInfo,
 	}, nil
 }
@@ -1,18 +1,26 @@
 package main
 
 import (
+	"context"
+	"log"
+	"os"
+
 	"platform-go-challenge/api"
+	"platform-go-challenge/dataset"
 	"platform-go-challenge/db"
 	"platform-go-challenge/graph"
 	"platform-go-challenge/graph/resolvers"
+	"platform-go-challenge/repository"
+	"platform-go-challenge/repository/cache"
+	"platform-go-challenge/repository/memory"
 
-	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
 )
 
 func graphqlHandler(resolver graph.ResolverRoot) gin.HandlerFunc {
-	h := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: resolver}))
+	h := graph.NewHandler(resolver)
 
 	return func(c *gin.Context) {
 		h.ServeHTTP(c.Writer, c.Request)
@@ -27,43 +35,83 @@ func playgroundHandler() gin.HandlerFunc {
 	}
 }
 
+// openStorage returns the repositories of the storage backend selected by
+// the STORAGE environment variable and, if the backend may become
+// unreachable, its availability. The "db" backend connects to DB_URL, which
+// may point to Postgres or SQLite, and is monitored until ctx is done.
+func openStorage(ctx context.Context, policy repository.AssetDeletePolicy) (repository.Repositories, api.Availability) {
+	switch storage := os.Getenv("STORAGE"); storage {
+	case "", "db":
+		db.InitDB()
+		return db.NewRepositories(db.GormDB, policy), db.StartMonitor(ctx)
+	case "memory":
+		log.Println("Using in-memory storage, data is lost on exit")
+		return memory.NewRepositories(policy), nil
+	default:
+		log.Fatalf("Unknown STORAGE %q: must be db or memory", storage)
+		return repository.Repositories{}, nil
+	}
+}
+
+// loadRespondents replaces the respondents dataset with the respondents of
+// the file at path
+func loadRespondents(ctx context.Context, respondents repository.RespondentRepository, path string) error {
+	loaded, err := dataset.Load(path)
+	if err != nil {
+		return err
+	}
+	if err := respondents.Replace(ctx, loaded); err != nil {
+		return err
+	}
+	log.Printf("Loaded %d respondents from %s", len(loaded), path)
+	return nil
+}
+
 func main() {
-	db.InitDB()
+	// Settings may come from a .env file, variables already set take precedence
+	_ = godotenv.Load()
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+		return
+	}
+
+	policy, err := repository.AssetDeletePolicyFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure asset deletion:", err)
+	}
+	log.Printf("Asset delete policy: %s", policy)
+
+	repos, availability := openStorage(context.Background(), policy)
+	if path := os.Getenv("RESPONDENTS_FILE"); path != "" {
+		if err := loadRespondents(context.Background(), repos.Respondents, path); err != nil {
+			log.Fatal("Failed to load respondents: ", err)
+		}
+	}
+
+	favouritesCache, ttl, err := cache.FromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure cache:", err)
+	}
+	if favouritesCache != nil {
+		log.Printf("Caching favourites and audience sizes for %s", ttl)
+		repos = cache.Wrap(repos, favouritesCache, ttl)
+	}
 	router := gin.Default()
+	if availability != nil {
+		// answer with 503 while the database is unreachable
+		router.Use(api.RequireAvailable(availability))
+	}
 
 	// GraphQL resolver
 	resolver := &resolvers.Resolver{
-		DB: db.GormDB,
+		Repos: repos,
 	}
 
-	//REST routes
-	// Audience routes
-	router.POST("/audience", api.CreateAudience)
-	router.GET("/audiences", api.GetAudiences)
-	router.GET("/audience/:id", api.GetAudience)
-	router.PUT("/audience/:id", api.UpdateAudience)
-	router.DELETE("/audience/:id", api.DeleteAudience)
-
-	// Chart routes
-	router.POST("/chart", api.CreateChart)
-	router.GET("/charts", api.GetCharts)
-	router.GET("/chart/:id", api.GetChart)
-	router.PUT("/chart/:id", api.UpdateChart)
-	router.DELETE("/chart/:id", api.DeleteChart)
-
-	// Insight routes
-	router.POST("/insight", api.CreateInsight)
-	router.GET("/insights", api.GetInsights)
-	router.GET("/insight/:id", api.GetInsight)
-	router.PUT("/insight/:id", api.UpdateInsight)
-	router.DELETE("/insight/:id", api.DeleteInsight)
-
-	// UserStar routes
-	router.POST("/userstar", api.CreateUserStar)
-	router.GET("/userstars", api.GetUserStars)
-	router.GET("/userstar/:id", api.GetUserStar)
-	router.PUT("/userstar/:id", api.UpdateUserStar)
-	router.DELETE("/userstar/:id", api.DeleteUserStar)
+	// REST routes
+	api.RegisterRoutes(router, repos)
 
 	// GraphQL routes
 	router.POST("/graphql", graphqlHandler(resolver))
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"platform-go-challenge/db"
+)
+
+const migrateUsage = `usage: migrate <command>
+
+commands:
+  up            apply all pending migrations
+  down          revert the most recently applied migration
+  status        list the migrations and whether they are applied
+  to <version>  apply or revert migrations to reach version, 0 reverts all`
+
+// runMigrate runs the migrate subcommand against the database at DB_URL
+func runMigrate(args []string) error {
+	command, err := parseMigrateCommand(args)
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, migrateUsage)
+	}
+
+	db.Connect()
+	migrator, err := db.NewMigrator(db.GormDB)
+	if err != nil {
+		return err
+	}
+	return command(context.Background(), migrator)
+}
+
+// parseMigrateCommand returns the migrate command selected by args
+func parseMigrateCommand(args []string) (func(context.Context, *db.Migrator) error, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing command")
+	}
+
+	switch command := args[0]; {
+	case command == "up" && len(args) == 1:
+		return func(ctx context.Context, migrator *db.Migrator) error {
+			migrations, err := migrator.Up(ctx)
+			return printMigrations("Applied", migrations, err)
+		}, nil
+	case command == "down" && len(args) == 1:
+		return func(ctx context.Context, migrator *db.Migrator) error {
+			migrations, err := migrator.Down(ctx)
+			return printMigrations("Reverted", migrations, err)
+		}, nil
+	case command == "status" && len(args) == 1:
+		return printStatus, nil
+	case command == "to" && len(args) == 2:
+		version, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q", args[1])
+		}
+		return func(ctx context.Context, migrator *db.Migrator) error {
+			migrations, err := migrator.To(ctx, uint(version))
+			return printMigrations("Migrated", migrations, err)
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid command %q", strings.Join(args, " "))
+}
+
+// printMigrations lists the migrations a command applied or reverted,
+// including those done before it failed with err, and returns err
+func printMigrations(action string, migrations []db.Migration, err error) error {
+	for _, migration := range migrations {
+		fmt.Printf("%s %04d_%s\n", action, migration.Version, migration.Name)
+	}
+	if err == nil && len(migrations) == 0 {
+		fmt.Println("Nothing to migrate")
+	}
+	return err
+}
+
+// printStatus writes a table of the migrations and their state
+func printStatus(ctx context.Context, migrator *db.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.Applied {
+			state, appliedAt = "applied", status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if status.Modified {
+			state = "modified"
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
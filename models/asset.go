@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Asset is implemented by the starrable Chart, Insight and Audience models
+type Asset interface {
+	AssetType() AssetType
+	AssetID() uint
+	// WithStar returns a copy of the asset carrying the star it was loaded through
+	WithStar(star *UserStar) Asset
+}
+
+// Starred carries the star through which an asset was loaded, so that a
+// favourite can expose the user's description and the time it was starred.
+// It is not persisted and is empty for assets loaded directly.
+type Starred struct {
+	Star *UserStar
+}
+
+// Description returns the user's description of the asset, or nil if the
+// asset was not loaded through a star
+func (s Starred) Description() *string {
+	if s.Star == nil {
+		return nil
+	}
+	return &s.Star.Description
+}
+
+// StarredAt returns when the user starred the asset, or nil if the asset was
+// not loaded through a star
+func (s Starred) StarredAt() *time.Time {
+	if s.Star == nil || s.Star.CreatedAt.IsZero() {
+		return nil
+	}
+	return &s.Star.CreatedAt
+}
@@ -1,10 +1,313 @@
 package models
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Audience is a group of people described by criteria on their demographics
+// and habits, e.g. males and females aged 18-24 or 25-34, born in GR or CY,
+// spending more than 3 hours a day on social media. Conditions the criteria
+// cannot express are given as an audience expression, which people must
+// satisfy as well.
 type Audience struct {
-	ID            uint   `json:"id" gorm:"primaryKey"`
-	Gender        string `json:"gender"`
-	BirthCountry  string `json:"birthcountry"`
-	AgeGroup      string `json:"agegroup"`
-	DailyHours    int    `json:"dailyhours"`
-	NoOfPurchases int    `json:"noofpurchases"`
+	ID       uint             `json:"id" gorm:"primaryKey"`
+	Criteria AudienceCriteria `json:"criteria" gorm:"serializer:json"`
+	// Expression is an audience expression in canonical form, or empty
+	Expression string `json:"expression" validate:"max=2000"`
+	Starred    `gorm:"-" json:"-"`
+}
+
+// AudienceCriteria are the characteristics shared by the people of an
+// audience. A person matches when they satisfy every criterion that is set.
+// Within a multi-valued criterion any of the values matches, and an empty
+// list places no restriction.
+type AudienceCriteria struct {
+	// Genders are any of Male, Female or Other
+	Genders []string `json:"genders" validate:"max=3,dive,oneof=Male Female Other"`
+	// BirthCountries are ISO 3166-1 alpha-2 or alpha-3 country codes, stored
+	// as alpha-2
+	BirthCountries []string `json:"birthcountries" validate:"max=250,dive,country"`
+	// AgeGroups are any of the buckets 18-24, 25-34, 35-44, 45-54, 55-64 or 65+
+	AgeGroups []string `json:"agegroups" validate:"max=6,dive,oneof=18-24 25-34 35-44 45-54 55-64 65+"`
+	// DailyHours are the hours spent daily on social media, from 0 to 24
+	DailyHours *NumericCriterion `json:"dailyhours,omitempty"`
+	// NoOfPurchases is the number of purchases last month
+	NoOfPurchases *NumericCriterion `json:"noofpurchases,omitempty"`
+}
+
+// MarshalJSON encodes missing multi-valued criteria as empty lists, like
+// the GraphQL API does
+func (c AudienceCriteria) MarshalJSON() ([]byte, error) {
+	// the alias type has no methods, so that encoding it does not recurse
+	type criteria AudienceCriteria
+	for _, values := range []*[]string{&c.Genders, &c.BirthCountries, &c.AgeGroups} {
+		if *values == nil {
+			*values = []string{}
+		}
+	}
+	return json.Marshal(criteria(c))
+}
+
+// ComparisonOperator compares a numeric characteristic with the value of a
+// NumericCriterion
+type ComparisonOperator string
+
+// Comparison operators
+const (
+	OperatorEQ  ComparisonOperator = "EQ"
+	OperatorGT  ComparisonOperator = "GT"
+	OperatorGTE ComparisonOperator = "GTE"
+	OperatorLT  ComparisonOperator = "LT"
+	OperatorLTE ComparisonOperator = "LTE"
+	// OperatorBetween matches values from Value to Upper, both inclusive
+	OperatorBetween ComparisonOperator = "BETWEEN"
+)
+
+// NumericCriterion restricts a numeric characteristic, e.g. GT 3 or
+// BETWEEN 1 and 5
+type NumericCriterion struct {
+	Operator ComparisonOperator `json:"operator" validate:"required,oneof=EQ GT GTE LT LTE BETWEEN"`
+	Value    float64            `json:"value"`
+	// Upper is the inclusive upper bound of BETWEEN and unset otherwise
+	Upper *float64 `json:"upper,omitempty"`
+}
+
+// Matches reports whether value satisfies the criterion
+func (n NumericCriterion) Matches(value float64) bool {
+	switch n.Operator {
+	case OperatorEQ:
+		return value == n.Value
+	case OperatorGT:
+		return value > n.Value
+	case OperatorGTE:
+		return value >= n.Value
+	case OperatorLT:
+		return value < n.Value
+	case OperatorLTE:
+		return value <= n.Value
+	case OperatorBetween:
+		return n.Upper != nil && value >= n.Value && value <= *n.Upper
+	}
+	return false
+}
+
+// AssetType returns AssetTypeAudience
+func (a Audience) AssetType() AssetType { return AssetTypeAudience }
+
+// AssetID returns the audience ID
+func (a Audience) AssetID() uint { return a.ID }
+
+// WithStar returns a copy of the audience carrying the given star
+func (a Audience) WithStar(star *UserStar) Asset {
+	a.Star = star
+	return a
+}
+
+// Definition returns the expression selecting the people of the audience:
+// its criteria and its expression combined, or nil if it selects everyone
+func (a Audience) Definition() (Expression, error) {
+	if a.Expression == "" {
+		return a.Criteria.Expression(), nil
+	}
+	expression, err := ParseExpression(a.Expression)
+	if err != nil {
+		return nil, err
+	}
+	return AllOf(a.Criteria.Expression(), expression), nil
+}
+
+// Restrict returns an audience with the same ID selecting the people of the
+// audience who also match where, e.g. to size a part of the audience
+func (a Audience) Restrict(where Expression) (Audience, error) {
+	definition, err := a.Definition()
+	if err != nil {
+		return Audience{}, err
+	}
+	restricted := Audience{ID: a.ID}
+	if combined := AllOf(definition, where); combined != nil {
+		restricted.Expression = combined.String()
+	}
+	return restricted, nil
+}
+
+// Summary describes the people of the audience in words
+func (a Audience) Summary() (string, error) {
+	definition, err := a.Definition()
+	if err != nil {
+		return "", err
+	}
+	return DescribeExpression(definition), nil
+}
+
+// Version identifies what the audience selects. It is derived from the
+// canonical form of its definition, so it changes whenever an update changes
+// the criteria or the expression, and only then.
+func (a Audience) Version() (string, error) {
+	definition, err := a.Definition()
+	if err != nil {
+		return "", err
+	}
+	canonical := ""
+	if definition != nil {
+		canonical = definition.String()
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// Expression returns the criteria as an audience expression, or nil if no
+// criterion is set
+func (c AudienceCriteria) Expression() Expression {
+	var operands []Expression
+	for _, criterion := range []struct {
+		field  string
+		values []string
+	}{
+		{"gender", c.Genders},
+		{"birth_country", c.BirthCountries},
+		{"age_group", c.AgeGroups},
+	} {
+		switch len(criterion.values) {
+		case 0:
+		case 1:
+			operands = append(operands, &comparison{field: expressionFields[criterion.field], op: "=", values: criterion.values})
+		default:
+			operands = append(operands, &comparison{field: expressionFields[criterion.field], op: "IN", values: criterion.values})
+		}
+	}
+	operands = append(operands, c.DailyHours.expression("social_hours"), c.NoOfPurchases.expression("purchases"))
+	return AllOf(operands...)
+}
+
+// comparisonOperators are the expression operators of the criterion operators
+var comparisonOperators = map[ComparisonOperator]string{
+	OperatorEQ: "=", OperatorGT: ">", OperatorGTE: ">=", OperatorLT: "<", OperatorLTE: "<=",
+}
+
+// expression returns the criterion as a comparison of field, or nil for a
+// nil criterion
+func (n *NumericCriterion) expression(field string) Expression {
+	if n == nil {
+		return nil
+	}
+	compare := func(op string, value float64) Expression {
+		return &comparison{field: expressionFields[field], op: op, values: []string{strconv.FormatFloat(value, 'f', -1, 64)}}
+	}
+	if n.Operator == OperatorBetween && n.Upper != nil {
+		return AllOf(compare(">=", n.Value), compare("<=", *n.Upper))
+	}
+	return compare(comparisonOperators[n.Operator], n.Value)
+}
+
+// Clone returns a copy of the criteria that shares no memory with them
+func (c AudienceCriteria) Clone() AudienceCriteria {
+	c.Genders = slices.Clone(c.Genders)
+	c.BirthCountries = slices.Clone(c.BirthCountries)
+	c.AgeGroups = slices.Clone(c.AgeGroups)
+	if c.DailyHours != nil {
+		c.DailyHours = c.DailyHours.clone()
+	}
+	if c.NoOfPurchases != nil {
+		c.NoOfPurchases = c.NoOfPurchases.clone()
+	}
+	return c
+}
+
+func (n *NumericCriterion) clone() *NumericCriterion {
+	cloned := *n
+	if n.Upper != nil {
+		upper := *n.Upper
+		cloned.Upper = &upper
+	}
+	return &cloned
+}
+
+// Validate checks the criteria of the audience, where every value must be
+// known and listed once and numeric criteria must be within the range of
+// their characteristic, and parses its expression, which it rewrites in
+// canonical form. Birth countries are rewritten as alpha-2 codes. It returns a
+// validation error listing every invalid field.
+func (a *Audience) Validate() error {
+	for i, country := range a.Criteria.BirthCountries {
+		if code, ok := CountryCode(country); ok {
+			a.Criteria.BirthCountries[i] = code
+		}
+	}
+
+	fields, err := structFieldErrors(a)
+	if err != nil {
+		return err
+	}
+
+	tooLong := slices.ContainsFunc(fields, func(field FieldError) bool { return field.Field == "expression" })
+	if strings.TrimSpace(a.Expression) == "" {
+		a.Expression = ""
+	} else if expression, err := ParseExpression(a.Expression); err != nil && !tooLong {
+		fields = append(fields, FieldError{Field: "expression", Message: err.Error()})
+	} else if err == nil {
+		a.Expression = expression.String()
+	}
+
+	criteria := a.Criteria
+	fields = append(fields, duplicateValues("criteria.genders", criteria.Genders)...)
+	fields = append(fields, duplicateValues("criteria.birthcountries", criteria.BirthCountries)...)
+	fields = append(fields, duplicateValues("criteria.agegroups", criteria.AgeGroups)...)
+	fields = append(fields, criteria.DailyHours.rangeErrors("criteria.dailyhours", 0, 24)...)
+	fields = append(fields, criteria.NoOfPurchases.rangeErrors("criteria.noofpurchases", 0, -1)...)
+
+	if len(fields) > 0 {
+		return ValidationError(fields...)
+	}
+	return nil
+}
+
+// duplicateValues reports the values of a multi-valued criterion listed more
+// than once
+func duplicateValues(field string, values []string) []FieldError {
+	var fields []FieldError
+	for i, value := range values {
+		if slices.Contains(values[:i], value) {
+			fields = append(fields, FieldError{Field: fmt.Sprintf("%s[%d]", field, i), Message: fmt.Sprintf("duplicate value %q", value)})
+		}
+	}
+	return fields
+}
+
+// rangeErrors checks the bounds of a criterion on a characteristic taking
+// values from lowest to highest, where a negative highest means unbounded,
+// and that only BETWEEN has an upper bound
+func (n *NumericCriterion) rangeErrors(field string, lowest, highest float64) []FieldError {
+	if n == nil {
+		return nil
+	}
+
+	var fields []FieldError
+	outOfRange := func(name string, value float64) {
+		switch {
+		case value < lowest:
+			fields = append(fields, FieldError{Field: field + "." + name, Message: fmt.Sprintf("must be at least %g", lowest)})
+		case highest >= 0 && value > highest:
+			fields = append(fields, FieldError{Field: field + "." + name, Message: fmt.Sprintf("must be at most %g", highest)})
+		}
+	}
+
+	outOfRange("value", n.Value)
+	switch {
+	case n.Operator == OperatorBetween && n.Upper == nil:
+		fields = append(fields, FieldError{Field: field + ".upper", Message: "is required with BETWEEN"})
+	case n.Operator == OperatorBetween:
+		outOfRange("upper", *n.Upper)
+		if *n.Upper < n.Value {
+			fields = append(fields, FieldError{Field: field + ".upper", Message: "must not be less than value"})
+		}
+	case n.Upper != nil:
+		fields = append(fields, FieldError{Field: field + ".upper", Message: "is only allowed with BETWEEN"})
+	}
+	return fields
 }
@@ -1,8 +1,139 @@
 package models
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Chart is a titled chart with one point per label in each of its series.
+// Labels are the categories along the X axis, or the slices of a pie chart.
 type Chart struct {
-	ID         uint   `json:"id" gorm:"primaryKey"`
-	Title      string `json:"title"`
-	XAxisTitle string `json:"xaxistitle"`
-	YAxisTitle string `json:"yaxistitle"`
+	ID         uint          `json:"id" gorm:"primaryKey"`
+	Title      string        `json:"title" validate:"required,max=200"`
+	ChartType  ChartType     `json:"charttype"`
+	XAxisTitle string        `json:"xaxistitle" validate:"max=100"`
+	YAxisTitle string        `json:"yaxistitle" validate:"max=100"`
+	Labels     []string      `json:"labels" gorm:"serializer:json" validate:"max=1000,dive,max=100"`
+	Series     []ChartSeries `json:"series" gorm:"constraint:OnDelete:CASCADE" validate:"max=50,dive"`
+	// Config holds the settings specific to the chart type
+	Config ChartConfig `json:"config" gorm:"serializer:json"`
+	// Provenance records the audience and the dimension a generated chart was
+	// computed from, and is nil for charts created with their series
+	Provenance *ChartProvenance `json:"provenance" gorm:"serializer:json"`
+	Starred    `gorm:"-" json:"-"`
+}
+
+// ChartProvenance records how a chart was generated, so that it can be
+// generated again from the current audience and respondents
+type ChartProvenance struct {
+	AudienceID uint      `json:"audienceid"`
+	Dimension  Dimension `json:"dimension"`
+	// AudienceVersion and DatasetVersion identify the audience definition and
+	// the dataset the series were computed from
+	AudienceVersion string    `json:"audienceversion"`
+	DatasetVersion  string    `json:"datasetversion"`
+	GeneratedAt     time.Time `json:"generatedat"`
+}
+
+// Chart series of generated charts
+const (
+	SeriesRespondents = "Respondents"
+	SeriesShare       = "Share (%)"
+)
+
+// NewAudienceChart returns a chart breaking the respondents of an audience
+// down by dimension, from the number of respondents by value of the
+// dimension. It has a series of the respondents and one of their share of the
+// audience in percent.
+func NewAudienceChart(audienceID uint, dimension Dimension, counts map[string]int64) Chart {
+	chart := Chart{
+		Title:      fmt.Sprintf("Audience %d by %s", audienceID, strings.ToLower(dimension.Title())),
+		ChartType:  ChartTypeBar,
+		XAxisTitle: dimension.Title(),
+		YAxisTitle: SeriesRespondents,
+	}
+	chart.SetBreakdown(dimension, counts)
+	return chart
+}
+
+// SetBreakdown replaces the labels and series of a generated chart with the
+// breakdown of an audience by dimension, keeping its titles
+func (c *Chart) SetBreakdown(dimension Dimension, counts map[string]int64) {
+	var total int64
+	for _, count := range counts {
+		total += count
+	}
+
+	c.Labels = dimension.Labels(counts)
+	respondents := ChartSeries{Name: SeriesRespondents, Points: make([]float64, len(c.Labels))}
+	share := ChartSeries{Name: SeriesShare, Points: make([]float64, len(c.Labels))}
+	for i, label := range c.Labels {
+		respondents.Points[i] = float64(counts[label])
+		if total > 0 {
+			share.Points[i] = float64(counts[label]) * 100 / float64(total)
+		}
+	}
+	c.Series = []ChartSeries{respondents, share}
+}
+
+// AssetType returns AssetTypeChart
+func (c Chart) AssetType() AssetType { return AssetTypeChart }
+
+// AssetID returns the chart ID
+func (c Chart) AssetID() uint { return c.ID }
+
+// WithStar returns a copy of the chart carrying the given star
+func (c Chart) WithStar(star *UserStar) Asset {
+	c.Star = star
+	return c
+}
+
+// ChartSeries is a named set of numeric points of a chart, stored in its own
+// table so that a chart can have any number of series
+type ChartSeries struct {
+	ID      uint      `json:"-" gorm:"primaryKey"`
+	ChartID uint      `json:"-" gorm:"index"`
+	Name    string    `json:"name" validate:"required,max=100"`
+	Points  []float64 `json:"points" gorm:"serializer:json" validate:"max=1000"`
+}
+
+// Validate checks the fields of the chart, that every series is named
+// uniquely and has exactly one point per label, and that the chart fits its
+// type, which it rewrites in canonical form. A chart without a type is a bar
+// chart. It returns a validation error listing every invalid field.
+func (c *Chart) Validate() error {
+	fields, err := structFieldErrors(c)
+	if err != nil {
+		return err
+	}
+
+	if c.ChartType == "" {
+		c.ChartType = ChartTypeBar
+	}
+	if chartType, err := ParseChartType(string(c.ChartType)); err != nil {
+		fields = append(fields, AsError(err).Fields...)
+	} else {
+		c.ChartType = chartType
+		fields = append(fields, c.typeErrors()...)
+	}
+
+	names := make(map[string]bool, len(c.Series))
+	for i, series := range c.Series {
+		if series.Name != "" && names[series.Name] {
+			fields = append(fields, FieldError{Field: fmt.Sprintf("series[%d].name", i), Message: fmt.Sprintf("duplicate name %q", series.Name)})
+		}
+		names[series.Name] = true
+
+		if len(series.Points) != len(c.Labels) {
+			fields = append(fields, FieldError{
+				Field:   fmt.Sprintf("series[%d].points", i),
+				Message: fmt.Sprintf("has %d points but the chart has %d labels", len(series.Points), len(c.Labels)),
+			})
+		}
+	}
+	if len(fields) > 0 {
+		return ValidationError(fields...)
+	}
+	return nil
 }
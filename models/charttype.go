@@ -0,0 +1,192 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ChartType is the kind of a chart, which decides how its labels and series
+// are drawn and which configuration it accepts
+type ChartType string
+
+// Chart types
+const (
+	ChartTypeBar        ChartType = "BAR"
+	ChartTypeLine       ChartType = "LINE"
+	ChartTypePie        ChartType = "PIE"
+	ChartTypeStackedBar ChartType = "STACKED_BAR"
+	ChartTypeScatter    ChartType = "SCATTER"
+)
+
+// chartTypes lists the chart types in the order they are documented
+var chartTypes = []ChartType{ChartTypeBar, ChartTypeLine, ChartTypePie, ChartTypeStackedBar, ChartTypeScatter}
+
+// IsValid checks if the ChartType is one of the valid types
+func (ct ChartType) IsValid() bool {
+	return slices.Contains(chartTypes, ct)
+}
+
+// ParseChartType converts a string into a ChartType, ignoring case
+func ParseChartType(value string) (ChartType, error) {
+	for _, ct := range chartTypes {
+		if strings.EqualFold(value, string(ct)) {
+			return ct, nil
+		}
+	}
+	return "", InvalidField("charttype", "invalid chart type %q: must be one of %s, %s, %s, %s or %s",
+		value, ChartTypeBar, ChartTypeLine, ChartTypePie, ChartTypeStackedBar, ChartTypeScatter)
+}
+
+// String returns the string representation of ChartType
+func (ct ChartType) String() string {
+	return string(ct)
+}
+
+// Value implements the driver.Valuer interface for database serialization. A
+// chart without a type is stored as a bar chart, the kind of every chart
+// before charts had types.
+func (ct ChartType) Value() (driver.Value, error) {
+	if ct == "" {
+		return string(ChartTypeBar), nil
+	}
+	if !ct.IsValid() {
+		return nil, fmt.Errorf("invalid chart type: %s", ct)
+	}
+	return string(ct), nil
+}
+
+// Scan implements the sql.Scanner interface for database deserialization
+func (ct *ChartType) Scan(value any) error {
+	if value == nil {
+		return fmt.Errorf("chart type cannot be null")
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		// Handle []byte as well (some drivers return bytes)
+		bytes, ok := value.([]byte)
+		if !ok {
+			return fmt.Errorf("chart type must be a string, got %T", value)
+		}
+		str = string(bytes)
+	}
+
+	*ct = ChartType(str)
+	if !ct.IsValid() {
+		return fmt.Errorf("invalid chart type: %s", str)
+	}
+	return nil
+}
+
+// HasAxes reports whether charts of the type are drawn on X and Y axes, which
+// is all of them but pie charts
+func (ct ChartType) HasAxes() bool {
+	return ct != ChartTypePie
+}
+
+// ChartConfig holds the settings of a chart that only some chart types have
+type ChartConfig struct {
+	// Stacks group the series of a stacked bar chart: the series of a stack
+	// are drawn on top of each other. Every series is in exactly one stack.
+	Stacks []ChartStack `json:"stacks" validate:"max=50,dive"`
+}
+
+// MarshalJSON encodes missing stacks as an empty list, like the GraphQL API
+// does
+func (c ChartConfig) MarshalJSON() ([]byte, error) {
+	// the alias type has no methods, so that encoding it does not recurse
+	type config ChartConfig
+	if c.Stacks == nil {
+		c.Stacks = []ChartStack{}
+	}
+	return json.Marshal(config(c))
+}
+
+// ChartStack is a named group of series of a stacked bar chart
+type ChartStack struct {
+	Name   string   `json:"name" validate:"required,max=100"`
+	Series []string `json:"series" validate:"min=1,max=50"`
+}
+
+// typeErrors checks that the axes, labels, series and configuration of the
+// chart fit its type:
+//
+//   - pie charts have no axis titles and at most one series of non-negative points
+//   - stacked bar charts put every series in exactly one stack
+//   - scatter charts have numeric labels, the X values of their points
+//
+// Only stacked bar charts have stacks.
+func (c *Chart) typeErrors() []FieldError {
+	var fields []FieldError
+	invalid := func(field, format string, args ...any) {
+		fields = append(fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if !c.ChartType.HasAxes() {
+		if c.XAxisTitle != "" {
+			invalid("xaxistitle", "must be empty: a %s chart has no axes", c.ChartType)
+		}
+		if c.YAxisTitle != "" {
+			invalid("yaxistitle", "must be empty: a %s chart has no axes", c.ChartType)
+		}
+	}
+
+	switch c.ChartType {
+	case ChartTypePie:
+		if len(c.Series) > 1 {
+			invalid("series", "a %s chart has at most one series, got %d", c.ChartType, len(c.Series))
+		}
+		for i, series := range c.Series {
+			if slices.ContainsFunc(series.Points, func(point float64) bool { return point < 0 }) {
+				invalid(fmt.Sprintf("series[%d].points", i), "must not be negative in a %s chart", c.ChartType)
+			}
+		}
+	case ChartTypeScatter:
+		for i, label := range c.Labels {
+			if _, err := strconv.ParseFloat(label, 64); err != nil {
+				invalid(fmt.Sprintf("labels[%d]", i), "must be a number: the labels of a %s chart are X values", c.ChartType)
+			}
+		}
+	}
+
+	if c.ChartType != ChartTypeStackedBar {
+		if len(c.Config.Stacks) > 0 {
+			invalid("config.stacks", "only %s charts have stacks", ChartTypeStackedBar)
+		}
+		return fields
+	}
+	return append(fields, c.stackErrors()...)
+}
+
+// stackErrors checks that the stacks of a stacked bar chart are named
+// uniquely and hold every series of the chart exactly once
+func (c *Chart) stackErrors() []FieldError {
+	var fields []FieldError
+	stackOf := make(map[string]string, len(c.Series))
+	for i, stack := range c.Config.Stacks {
+		if stack.Name != "" && slices.ContainsFunc(c.Config.Stacks[:i], func(other ChartStack) bool { return other.Name == stack.Name }) {
+			fields = append(fields, FieldError{Field: fmt.Sprintf("config.stacks[%d].name", i), Message: fmt.Sprintf("duplicate name %q", stack.Name)})
+		}
+		for j, name := range stack.Series {
+			field := fmt.Sprintf("config.stacks[%d].series[%d]", i, j)
+			switch previous, found := stackOf[name]; {
+			case !slices.ContainsFunc(c.Series, func(series ChartSeries) bool { return series.Name == name }):
+				fields = append(fields, FieldError{Field: field, Message: fmt.Sprintf("unknown series %q", name)})
+			case found:
+				fields = append(fields, FieldError{Field: field, Message: fmt.Sprintf("series %q is already in stack %q", name, previous)})
+			default:
+				stackOf[name] = stack.Name
+			}
+		}
+	}
+	for i, series := range c.Series {
+		if _, found := stackOf[series.Name]; !found && series.Name != "" {
+			fields = append(fields, FieldError{Field: fmt.Sprintf("series[%d]", i), Message: fmt.Sprintf("series %q is in no stack", series.Name)})
+		}
+	}
+	return fields
+}
@@ -0,0 +1,119 @@
+package models
+
+import "fmt"
+
+// MaxComparedAudiences is the largest number of audiences compared at once
+const MaxComparedAudiences = 10
+
+// AudienceComparison is the overlap of every pair of audiences on the
+// respondents dataset, e.g. to draw a Venn diagram or a heatmap
+type AudienceComparison struct {
+	// Audiences are the sizes of the compared audiences, in the requested order
+	Audiences []AudienceSize `json:"audiences"`
+	// Matrix holds the overlap of Audiences[i] and Audiences[j] in row i and
+	// column j. It is symmetric and its diagonal compares each audience with
+	// itself.
+	Matrix [][]AudienceOverlap `json:"matrix"`
+	// Base is the number of respondents in the dataset
+	Base           int64  `json:"base"`
+	DatasetVersion string `json:"datasetversion"`
+}
+
+// AudienceOverlap is the overlap of two audiences
+type AudienceOverlap struct {
+	// Intersection is the number of respondents in both audiences
+	Intersection int64 `json:"intersection"`
+	// Union is the number of respondents in either audience
+	Union int64 `json:"union"`
+	// Jaccard is Intersection divided by Union, or 0 if Union is 0
+	Jaccard float64 `json:"jaccard"`
+}
+
+// NewAudienceComparison returns the comparison of the audiences with the
+// given IDs from the number of respondents in each pair of audiences, where
+// counts[i][i] is the size of audience i, out of base respondents
+func NewAudienceComparison(audienceIDs []uint, counts [][]int64, base int64) AudienceComparison {
+	comparison := AudienceComparison{
+		Audiences: make([]AudienceSize, len(audienceIDs)),
+		Matrix:    make([][]AudienceOverlap, len(audienceIDs)),
+		Base:      base,
+	}
+	for i, id := range audienceIDs {
+		comparison.Audiences[i] = NewAudienceSize(id, counts[i][i], base)
+		comparison.Matrix[i] = make([]AudienceOverlap, len(audienceIDs))
+		for j := range audienceIDs {
+			overlap := AudienceOverlap{
+				Intersection: counts[i][j],
+				Union:        counts[i][i] + counts[j][j] - counts[i][j],
+			}
+			if overlap.Union > 0 {
+				overlap.Jaccard = float64(overlap.Intersection) / float64(overlap.Union)
+			}
+			comparison.Matrix[i][j] = overlap
+		}
+	}
+	return comparison
+}
+
+// ValidateComparedAudiences checks the IDs of audiences to compare: from 2 to
+// MaxComparedAudiences audiences, each listed once
+func ValidateComparedAudiences(audienceIDs []uint) error {
+	if len(audienceIDs) < 2 || len(audienceIDs) > MaxComparedAudiences {
+		return InvalidField("ids", "must list from 2 to %d audiences", MaxComparedAudiences)
+	}
+	var fields []FieldError
+	for i, id := range audienceIDs {
+		for _, previous := range audienceIDs[:i] {
+			if id == previous {
+				fields = append(fields, FieldError{Field: fmt.Sprintf("ids[%d]", i), Message: fmt.Sprintf("duplicate audience %d", id)})
+				break
+			}
+		}
+	}
+	if len(fields) > 0 {
+		return ValidationError(fields...)
+	}
+	return nil
+}
+
+// OverlapCounter counts the respondents matching each pair of expressions in
+// a single pass over the dataset. A nil expression matches everyone.
+type OverlapCounter struct {
+	expressions []Expression
+	matched     []bool
+	counts      [][]int64
+	total       int64
+}
+
+// NewOverlapCounter returns a counter of the overlaps of expressions
+func NewOverlapCounter(expressions []Expression) *OverlapCounter {
+	counts := make([][]int64, len(expressions))
+	for i := range counts {
+		counts[i] = make([]int64, len(expressions))
+	}
+	return &OverlapCounter{expressions: expressions, matched: make([]bool, len(expressions)), counts: counts}
+}
+
+// Add counts a respondent
+func (c *OverlapCounter) Add(profile Profile) {
+	c.total++
+	for i, expression := range c.expressions {
+		c.matched[i] = expression == nil || expression.Matches(profile)
+	}
+	for i := range c.expressions {
+		if !c.matched[i] {
+			continue
+		}
+		for j := range c.expressions {
+			if c.matched[j] {
+				c.counts[i][j]++
+			}
+		}
+	}
+}
+
+// Counts returns the number of respondents matching both expressions i and j
+// in counts[i][j], and the number of respondents counted
+func (c *OverlapCounter) Counts() (counts [][]int64, total int64) {
+	return c.counts, c.total
+}
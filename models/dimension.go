@@ -0,0 +1,105 @@
+package models
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Dimension is a categorical characteristic of respondents that an audience
+// is broken down by
+type Dimension string
+
+// Dimensions
+const (
+	DimensionGender       Dimension = "GENDER"
+	DimensionBirthCountry Dimension = "BIRTH_COUNTRY"
+	DimensionAgeGroup     Dimension = "AGE_GROUP"
+)
+
+// dimensionTitles are the titles of the dimensions in charts
+var dimensionTitles = map[Dimension]string{
+	DimensionGender:       "Gender",
+	DimensionBirthCountry: "Birth country",
+	DimensionAgeGroup:     "Age group",
+}
+
+// dimensionValues are the values of the dimensions that every breakdown
+// lists, in order, even when no respondent has them. Birth countries are too
+// many and only those of some respondent are listed.
+var dimensionValues = map[Dimension][]string{
+	DimensionGender:   {"Male", "Female", "Other"},
+	DimensionAgeGroup: {"18-24", "25-34", "35-44", "45-54", "55-64", "65+"},
+}
+
+// ParseDimension converts a string into a Dimension, ignoring case
+func ParseDimension(value string) (Dimension, error) {
+	for _, d := range []Dimension{DimensionGender, DimensionBirthCountry, DimensionAgeGroup} {
+		if strings.EqualFold(value, string(d)) {
+			return d, nil
+		}
+	}
+	return "", InvalidField("dimension", "invalid dimension %q: must be one of %s, %s or %s",
+		value, DimensionGender, DimensionBirthCountry, DimensionAgeGroup)
+}
+
+// Title returns the name of the dimension in charts, e.g. Age group
+func (d Dimension) Title() string {
+	return dimensionTitles[d]
+}
+
+// Value returns the value of the dimension for a respondent
+func (d Dimension) Value(profile Profile) string {
+	switch d {
+	case DimensionGender:
+		return profile.Gender
+	case DimensionBirthCountry:
+		return profile.BirthCountry
+	case DimensionAgeGroup:
+		return profile.AgeGroup
+	}
+	return ""
+}
+
+// Labels returns the values of a breakdown along the dimension in chart
+// order: the fixed order of the dimension values, or the most frequent first
+func (d Dimension) Labels(counts map[string]int64) []string {
+	if values, ok := dimensionValues[d]; ok {
+		return slices.Clone(values)
+	}
+	labels := make([]string, 0, len(counts))
+	for value := range counts {
+		labels = append(labels, value)
+	}
+	slices.SortFunc(labels, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), strings.Compare(a, b))
+	})
+	return labels
+}
+
+// BreakdownCounter counts the respondents matching an expression by their
+// value of a dimension. A nil expression matches everyone.
+type BreakdownCounter struct {
+	expression Expression
+	dimension  Dimension
+	counts     map[string]int64
+}
+
+// NewBreakdownCounter returns a counter of the respondents matching
+// expression by dimension
+func NewBreakdownCounter(expression Expression, dimension Dimension) *BreakdownCounter {
+	return &BreakdownCounter{expression: expression, dimension: dimension, counts: make(map[string]int64)}
+}
+
+// Add counts a respondent
+func (c *BreakdownCounter) Add(profile Profile) {
+	if c.expression != nil && !c.expression.Matches(profile) {
+		return
+	}
+	c.counts[c.dimension.Value(profile)]++
+}
+
+// Counts returns the number of matching respondents by value of the dimension
+func (c *BreakdownCounter) Counts() map[string]int64 {
+	return c.counts
+}
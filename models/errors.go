@@ -0,0 +1,145 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrorKind classifies domain errors. REST maps every kind to an HTTP status.
+type ErrorKind string
+
+// Error kinds
+const (
+	// ErrorNotFound means the requested resource does not exist
+	ErrorNotFound ErrorKind = "NOT_FOUND"
+	// ErrorValidation means the input is malformed or invalid
+	ErrorValidation ErrorKind = "VALIDATION"
+	// ErrorUnprocessable means the input is valid but refers to a state that
+	// does not allow the operation, such as a missing related record
+	ErrorUnprocessable ErrorKind = "UNPROCESSABLE"
+	// ErrorConflict means the operation conflicts with the current state
+	ErrorConflict ErrorKind = "CONFLICT"
+	// ErrorForbidden means the caller is not allowed to perform the operation
+	ErrorForbidden ErrorKind = "FORBIDDEN"
+	// ErrorUnavailable means a dependency is temporarily unreachable and the
+	// request may be retried
+	ErrorUnavailable ErrorKind = "UNAVAILABLE"
+	// ErrorInternal means an unexpected failure whose details are not shown
+	ErrorInternal ErrorKind = "INTERNAL"
+)
+
+// Codes of the errors created by the constructors below. Errors defined
+// elsewhere use more specific codes.
+const (
+	CodeNotFound         = "NOT_FOUND"
+	CodeValidationFailed = "VALIDATION_FAILED"
+	CodeConflict         = "CONFLICT"
+	CodeForbidden        = "FORBIDDEN"
+	CodeInternal         = "INTERNAL"
+)
+
+// FieldError describes why one input field is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a domain error. Its message is meant for API clients, while Code
+// is a stable machine-readable identifier of the error.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	// Fields lists the invalid fields of a validation error
+	Fields []FieldError
+	// RetryAfter hints when an unavailable dependency may be back
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is a domain error with the same code, so that
+// errors derived from a sentinel error match it with errors.Is
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Withf returns a copy of the error with another message
+func (e *Error) Withf(format string, args ...any) *Error {
+	derived := *e
+	derived.Message = fmt.Sprintf(format, args...)
+	return &derived
+}
+
+// WithRetryAfter returns a copy of the error hinting to retry after delay
+func (e *Error) WithRetryAfter(delay time.Duration) *Error {
+	derived := *e
+	derived.RetryAfter = delay
+	return &derived
+}
+
+// WithFields returns a copy of the error listing the given invalid fields
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	derived := *e
+	derived.Fields = fields
+	return &derived
+}
+
+// NewError returns a domain error of the given kind
+func NewError(kind ErrorKind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// NotFoundError returns an error for a missing resource
+func NotFoundError(format string, args ...any) *Error {
+	return NewError(ErrorNotFound, CodeNotFound, fmt.Sprintf(format, args...))
+}
+
+// ValidationError returns an error for invalid input. Its message
+// summarizes the invalid fields.
+func ValidationError(fields ...FieldError) *Error {
+	problems := make([]string, len(fields))
+	for i, field := range fields {
+		problems[i] = field.Field + ": " + field.Message
+	}
+	message := "invalid input"
+	if len(problems) > 0 {
+		message += ": " + strings.Join(problems, "; ")
+	}
+	return &Error{Kind: ErrorValidation, Code: CodeValidationFailed, Message: message, Fields: fields}
+}
+
+// InvalidField returns a validation error for a single field
+func InvalidField(field, format string, args ...any) *Error {
+	return ValidationError(FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// ConflictError returns an error for an operation conflicting with the current state
+func ConflictError(format string, args ...any) *Error {
+	return NewError(ErrorConflict, CodeConflict, fmt.Sprintf(format, args...))
+}
+
+// ForbiddenError returns an error for an operation the caller may not perform
+func ForbiddenError(format string, args ...any) *Error {
+	return NewError(ErrorForbidden, CodeForbidden, fmt.Sprintf(format, args...))
+}
+
+// AsError returns the domain error describing err. If err is or wraps a
+// domain error, the result has its kind, code and fields and the full message
+// of err, which adds the context of the wrapping. Any other error is
+// unexpected: the result is an internal error that does not expose it.
+func AsError(err error) *Error {
+	var domainErr *Error
+	if !errors.As(err, &domainErr) {
+		return NewError(ErrorInternal, CodeInternal, "internal server error")
+	}
+	if domainErr == err {
+		return domainErr
+	}
+	return domainErr.Withf("%s", err.Error())
+}
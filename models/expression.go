@@ -0,0 +1,659 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// An audience expression is a boolean formula over the characteristics of a
+// person, e.g.
+//
+//	gender = Male AND age_group IN [25-34, 35-44] AND social_hours > 3 AND NOT birth_country = US
+//
+// Comparisons are combined with AND, OR and NOT, in decreasing order of
+// precedence, and grouped with parentheses. Gender, birth country and age
+// group support =, != and IN; social hours and purchases support =, !=, >,
+// >=, < and <=. Keywords and values are case-insensitive.
+
+// Profile holds the characteristics of a person that audiences select on
+type Profile struct {
+	Gender       string
+	BirthCountry string
+	AgeGroup     string
+	// DailyHours are the hours spent daily on social media
+	DailyHours float64
+	// Purchases is the number of purchases last month
+	Purchases float64
+}
+
+// Expression is a parsed audience expression
+type Expression interface {
+	// Matches reports whether a person with the given profile satisfies the
+	// expression
+	Matches(profile Profile) bool
+	// String returns the canonical form of the expression
+	String() string
+	// describe returns the expression in words, as a predicate following
+	// "people who"
+	describe() string
+	// precedence is the binding strength of the outermost operator, which
+	// decides where String adds parentheses
+	precedence() int
+}
+
+// Operator precedences, from the loosest to the tightest
+const (
+	precedenceOr = iota + 1
+	precedenceAnd
+	precedenceNot
+	precedenceComparison
+)
+
+// ExpressionSyntaxError reports why an expression could not be parsed
+type ExpressionSyntaxError struct {
+	// Position is the 1-based offset of the offending character
+	Position int
+	Message  string
+}
+
+func (e *ExpressionSyntaxError) Error() string {
+	return fmt.Sprintf("at position %d: %s", e.Position, e.Message)
+}
+
+// expressionField is a characteristic that expressions compare
+type expressionField struct {
+	// name is the canonical name of the field in expressions
+	name    string
+	numeric bool
+	// values lists the known values of an enumeration in canonical spelling
+	values []string
+	// text returns the characteristic of a profile, number returns it for
+	// numeric fields
+	text   func(Profile) string
+	number func(Profile) float64
+	// min and max bound numeric values, where a negative max is unbounded
+	min, max float64
+	// phrase describes a comparison of the field in words
+	phrase func(op string, values string) string
+}
+
+// expressionFields are the comparable fields by name, including aliases
+var expressionFields = map[string]*expressionField{}
+
+func init() {
+	fields := []*expressionField{
+		{
+			name:   "gender",
+			values: []string{"Male", "Female", "Other"},
+			text:   func(p Profile) string { return p.Gender },
+			phrase: func(op, values string) string { return negated(op, "are ", "are not ") + values },
+		},
+		{
+			name:   "birth_country",
+			text:   func(p Profile) string { return p.BirthCountry },
+			phrase: func(op, values string) string { return negated(op, "were born in ", "were not born in ") + values },
+		},
+		{
+			name:   "age_group",
+			values: []string{"18-24", "25-34", "35-44", "45-54", "55-64", "65+"},
+			text:   func(p Profile) string { return p.AgeGroup },
+			phrase: func(op, values string) string { return negated(op, "are aged ", "are not aged ") + values },
+		},
+		{
+			name:    "social_hours",
+			numeric: true,
+			number:  func(p Profile) float64 { return p.DailyHours },
+			max:     24,
+			phrase: func(op, value string) string {
+				return "spend " + quantity(op, value) + " " + plural(value, "hour") + " a day on social media"
+			},
+		},
+		{
+			name:    "purchases",
+			numeric: true,
+			number:  func(p Profile) float64 { return p.Purchases },
+			max:     -1,
+			phrase: func(op, value string) string {
+				return "made " + quantity(op, value) + " " + plural(value, "purchase") + " last month"
+			},
+		},
+	}
+	for _, field := range fields {
+		expressionFields[field.name] = field
+	}
+	// the names of the matching model fields
+	expressionFields["daily_hours"] = expressionFields["social_hours"]
+	expressionFields["no_of_purchases"] = expressionFields["purchases"]
+}
+
+// negated picks the phrase of an operator that excludes its values
+func negated(op, positive, negative string) string {
+	if op == "!=" {
+		return negative
+	}
+	return positive
+}
+
+// quantity describes a numeric comparison, e.g. "more than 3"
+func quantity(op, value string) string {
+	switch op {
+	case ">":
+		return "more than " + value
+	case ">=":
+		return "at least " + value
+	case "<":
+		return "less than " + value
+	case "<=":
+		return "at most " + value
+	case "!=":
+		return "other than " + value
+	}
+	return "exactly " + value
+}
+
+// plural returns noun in the plural unless value is 1
+func plural(value, noun string) string {
+	if value == "1" {
+		return noun
+	}
+	return noun + "s"
+}
+
+// ParseExpression parses an audience expression. Field names and values are
+// checked, so that every parsed expression can be evaluated. Syntax errors
+// are returned as an *ExpressionSyntaxError.
+func ParseExpression(source string) (Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, end: utf8.RuneCountInString(source) + 1}
+	expression, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next != nil {
+		return nil, p.errorAt(next, "unexpected %q", next.text)
+	}
+	return expression, nil
+}
+
+// DescribeExpression returns the expression in words, e.g. "People who are
+// Male and spend more than 3 hours a day on social media". A nil expression
+// selects everyone.
+func DescribeExpression(expression Expression) string {
+	if expression == nil {
+		return "Everyone"
+	}
+	return "People who " + expression.describe()
+}
+
+// AllOf returns the conjunction of the non-nil expressions, or nil if there
+// are none
+func AllOf(expressions ...Expression) Expression {
+	var operands []Expression
+	for _, expression := range expressions {
+		switch e := expression.(type) {
+		case nil:
+		case *andExpression:
+			operands = append(operands, e.operands...)
+		default:
+			operands = append(operands, e)
+		}
+	}
+	switch len(operands) {
+	case 0:
+		return nil
+	case 1:
+		return operands[0]
+	}
+	return &andExpression{operands: operands}
+}
+
+// token is a lexical element of an expression
+type token struct {
+	text string
+	// position is the 1-based offset of the token in the source
+	position int
+	// word reports a field name, keyword or value, as opposed to punctuation
+	// and operators
+	word bool
+}
+
+// isWordRune reports whether r can be part of a name, keyword or value, such
+// as social_hours, 25-34, 65+ or 2.5
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-+.", r)
+}
+
+// tokenize splits an expression into tokens
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case isWordRune(r):
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{text: string(runes[start:i]), position: start + 1, word: true})
+		case strings.ContainsRune("()[],=", r):
+			tokens = append(tokens, token{text: string(r), position: i + 1})
+			i++
+		case strings.ContainsRune("!<>", r):
+			text := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				text += "="
+			}
+			if text == "!" {
+				return nil, &ExpressionSyntaxError{Position: i + 1, Message: `expected "!="`}
+			}
+			tokens = append(tokens, token{text: text, position: i + 1})
+			i += len(text)
+		default:
+			return nil, &ExpressionSyntaxError{Position: i + 1, Message: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+	return tokens, nil
+}
+
+// parser is a recursive descent parser over the tokens of an expression:
+//
+//	or         = and { "OR" and }
+//	and        = unary { "AND" unary }
+//	unary      = "NOT" unary | "(" or ")" | comparison
+//	comparison = field ( "=" | "!=" | ">" | ">=" | "<" | "<=" ) value
+//	           | field "IN" "[" value { "," value } "]"
+type parser struct {
+	tokens []token
+	next   int
+	// end is the position reported for errors at the end of the source
+	end int
+}
+
+func (p *parser) peek() *token {
+	if p.next < len(p.tokens) {
+		return &p.tokens[p.next]
+	}
+	return nil
+}
+
+// accept consumes the next token if it is the given keyword or punctuation
+func (p *parser) accept(text string) bool {
+	if next := p.peek(); next != nil && strings.EqualFold(next.text, text) {
+		p.next++
+		return true
+	}
+	return false
+}
+
+// expect consumes the next token, which must be the given punctuation
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return p.errorAt(p.peek(), "expected %q", text)
+	}
+	return nil
+}
+
+// errorAt returns a syntax error at a token, or at the end of the source if
+// the token is nil
+func (p *parser) errorAt(at *token, format string, args ...any) error {
+	position := p.end
+	message := fmt.Sprintf(format, args...)
+	if at == nil {
+		message += " at end of expression"
+	} else {
+		position = at.position
+	}
+	return &ExpressionSyntaxError{Position: position, Message: message}
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	operands := []Expression{first}
+	for p.accept("OR") {
+		operand, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+
+	// (a OR b) OR c is a OR b OR c
+	var flattened []Expression
+	for _, operand := range operands {
+		if or, ok := operand.(*orExpression); ok {
+			flattened = append(flattened, or.operands...)
+		} else {
+			flattened = append(flattened, operand)
+		}
+	}
+	return &orExpression{operands: flattened}, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	operands := []Expression{first}
+	for p.accept("AND") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	return AllOf(operands...), nil
+}
+
+func (p *parser) parseUnary() (Expression, error) {
+	if p.accept("NOT") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpression{operand: operand}, nil
+	}
+	if p.accept("(") {
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return expression, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expression, error) {
+	name := p.peek()
+	if name == nil || !name.word {
+		return nil, p.errorAt(name, "expected a field")
+	}
+	field, ok := expressionFields[strings.ToLower(name.text)]
+	if !ok {
+		return nil, p.errorAt(name, "unknown field %q, expected one of gender, birth_country, age_group, social_hours or purchases", name.text)
+	}
+	p.next++
+
+	op := p.peek()
+	switch {
+	case op == nil:
+		return nil, p.errorAt(op, "expected an operator")
+	case op.word && strings.EqualFold(op.text, "IN"):
+		p.next++
+		if field.numeric {
+			return nil, p.errorAt(op, "%s does not support IN", field.name)
+		}
+		return p.parseList(field)
+	case slices.Contains([]string{"=", "!="}, op.text), field.numeric && slices.Contains([]string{">", ">=", "<", "<="}, op.text):
+		p.next++
+	case slices.Contains([]string{">", ">=", "<", "<="}, op.text):
+		return nil, p.errorAt(op, "%s does not support %s", field.name, op.text)
+	default:
+		return nil, p.errorAt(op, "expected an operator")
+	}
+
+	value, err := p.parseValue(field)
+	if err != nil {
+		return nil, err
+	}
+	return &comparison{field: field, op: op.text, values: []string{value}}, nil
+}
+
+// parseList parses the bracketed values of IN
+func (p *parser) parseList(field *expressionField) (Expression, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	var values []string
+	for {
+		value, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	return &comparison{field: field, op: "IN", values: values}, nil
+}
+
+// parseValue parses a value of field and returns it in canonical form
+func (p *parser) parseValue(field *expressionField) (string, error) {
+	value := p.peek()
+	if value == nil || !value.word {
+		return "", p.errorAt(value, "expected a value")
+	}
+	p.next++
+
+	switch {
+	case field.numeric:
+		number, err := strconv.ParseFloat(value.text, 64)
+		if err != nil || !isDecimal(value.text) || math.IsNaN(number) || math.IsInf(number, 0) {
+			return "", p.errorAt(value, "%s must be a number", field.name)
+		}
+		if number < field.min || (field.max >= 0 && number > field.max) {
+			if field.max < 0 {
+				return "", p.errorAt(value, "%s must be at least %g", field.name, field.min)
+			}
+			return "", p.errorAt(value, "%s must be between %g and %g", field.name, field.min, field.max)
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	case field.values != nil:
+		i := slices.IndexFunc(field.values, func(known string) bool { return strings.EqualFold(known, value.text) })
+		if i < 0 {
+			return "", p.errorAt(value, "%s must be one of %s", field.name, strings.Join(field.values, ", "))
+		}
+		return field.values[i], nil
+	}
+
+	country, ok := CountryCode(strings.ToUpper(value.text))
+	if !ok {
+		return "", p.errorAt(value, "%s must be an ISO 3166-1 alpha-2 or alpha-3 country code", field.name)
+	}
+	return country, nil
+}
+
+// isDecimal reports whether text is a plain decimal number such as 3, -1 or
+// 2.5, leaving out the special values, underscores, hexadecimal and exponent
+// forms that strconv.ParseFloat also accepts
+func isDecimal(text string) bool {
+	text = strings.TrimPrefix(text, "-")
+	whole, fraction, hasFraction := strings.Cut(text, ".")
+	digits := func(s string) bool {
+		return s != "" && strings.Trim(s, "0123456789") == ""
+	}
+	return digits(whole) && (!hasFraction || digits(fraction))
+}
+
+// andExpression matches when all of its operands do
+type andExpression struct {
+	operands []Expression
+}
+
+func (e *andExpression) Matches(profile Profile) bool {
+	for _, operand := range e.operands {
+		if !operand.Matches(profile) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *andExpression) String() string { return joinOperands(e.operands, " AND ", e.precedence()) }
+
+func (e *andExpression) describe() string { return describeOperands(e.operands, "and") }
+
+func (e *andExpression) precedence() int { return precedenceAnd }
+
+// orExpression matches when any of its operands does
+type orExpression struct {
+	operands []Expression
+}
+
+func (e *orExpression) Matches(profile Profile) bool {
+	for _, operand := range e.operands {
+		if operand.Matches(profile) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *orExpression) String() string { return joinOperands(e.operands, " OR ", e.precedence()) }
+
+func (e *orExpression) describe() string { return describeOperands(e.operands, "or") }
+
+func (e *orExpression) precedence() int { return precedenceOr }
+
+// notExpression matches when its operand does not
+type notExpression struct {
+	operand Expression
+}
+
+func (e *notExpression) Matches(profile Profile) bool { return !e.operand.Matches(profile) }
+
+func (e *notExpression) String() string {
+	return "NOT " + parenthesize(e.operand, e.operand.String(), e.precedence())
+}
+
+func (e *notExpression) describe() string { return negate(e.operand).describe() }
+
+func (e *notExpression) precedence() int { return precedenceNot }
+
+// comparison compares a field with one value, or with a list for IN
+type comparison struct {
+	field  *expressionField
+	op     string
+	values []string
+}
+
+func (c *comparison) Matches(profile Profile) bool {
+	if !c.field.numeric {
+		actual := c.field.text(profile)
+		found := slices.ContainsFunc(c.values, func(value string) bool { return strings.EqualFold(value, actual) })
+		return found != (c.op == "!=")
+	}
+
+	actual := c.field.number(profile)
+	value, _ := strconv.ParseFloat(c.values[0], 64)
+	switch c.op {
+	case "=":
+		return actual == value
+	case "!=":
+		return actual != value
+	case ">":
+		return actual > value
+	case ">=":
+		return actual >= value
+	case "<":
+		return actual < value
+	case "<=":
+		return actual <= value
+	}
+	return false
+}
+
+func (c *comparison) String() string {
+	if c.op == "IN" {
+		return c.field.name + " IN [" + strings.Join(c.values, ", ") + "]"
+	}
+	return c.field.name + " " + c.op + " " + c.values[0]
+}
+
+func (c *comparison) describe() string {
+	return c.field.phrase(c.op, joinWords(c.values, "or"))
+}
+
+func (c *comparison) precedence() int { return precedenceComparison }
+
+// inverses are the operators matching exactly the values the other one does not
+var inverses = map[string]string{"=": "!=", "!=": "=", "IN": "!=", ">": "<=", ">=": "<", "<": ">=", "<=": ">"}
+
+// negate returns an expression matching exactly when e does not, with the
+// negation pushed down to the comparisons. It only serves descriptions, as
+// the comparisons it returns may have != with several values.
+func negate(e Expression) Expression {
+	switch e := e.(type) {
+	case *notExpression:
+		return e.operand
+	case *andExpression:
+		return &orExpression{operands: negateAll(e.operands)}
+	case *orExpression:
+		return &andExpression{operands: negateAll(e.operands)}
+	case *comparison:
+		return &comparison{field: e.field, op: inverses[e.op], values: e.values}
+	}
+	return &notExpression{operand: e}
+}
+
+func negateAll(operands []Expression) []Expression {
+	negated := make([]Expression, len(operands))
+	for i, operand := range operands {
+		negated[i] = negate(operand)
+	}
+	return negated
+}
+
+// parenthesize wraps the text of an operand in parentheses if its operator
+// binds more loosely than the enclosing one
+func parenthesize(operand Expression, text string, enclosing int) string {
+	if operand.precedence() < enclosing {
+		return "(" + text + ")"
+	}
+	return text
+}
+
+func joinOperands(operands []Expression, separator string, enclosing int) string {
+	texts := make([]string, len(operands))
+	for i, operand := range operands {
+		texts[i] = parenthesize(operand, operand.String(), enclosing)
+	}
+	return strings.Join(texts, separator)
+}
+
+func describeOperands(operands []Expression, conjunction string) string {
+	texts := make([]string, len(operands))
+	for i, operand := range operands {
+		// a negation is described as the expression it expands to
+		if not, ok := operand.(*notExpression); ok {
+			operand = negate(not.operand)
+		}
+		// nested groups are always marked, as words have no precedence
+		texts[i] = parenthesize(operand, operand.describe(), precedenceNot)
+	}
+	return joinWords(texts, conjunction)
+}
+
+// joinWords joins items as in "a, b and c"
+func joinWords(items []string, conjunction string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " " + conjunction + " " + items[len(items)-1]
+}
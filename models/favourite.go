@@ -0,0 +1,8 @@
+package models
+
+// Favourite is a UserStar together with the full asset it points to.
+// Asset holds a Chart, Insight or Audience depending on the star's Type.
+type Favourite struct {
+	UserStar
+	Asset Asset `json:"asset"`
+}
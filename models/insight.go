@@ -1,6 +1,49 @@
 package models
 
+// Insight is a text about the data. Its text is an insight template, whose
+// placeholders are replaced by current numbers when the insight is read.
 type Insight struct {
-	ID   uint   `json:"id" gorm:"primaryKey"`
-	Text string `json:"text"`
+	ID      uint   `json:"id" gorm:"primaryKey"`
+	Text    string `json:"text" validate:"required,max=10000"`
+	Starred `gorm:"-" json:"-"`
+}
+
+// RenderedInsight is an insight as read, with the placeholders of its text
+// rendered, the template it was rendered from and the audiences and charts
+// backing it
+type RenderedInsight struct {
+	Insight
+	Text     string     `json:"text"`
+	Template string     `json:"template"`
+	Sources  []AssetRef `json:"sources"`
+}
+
+// AssetType returns AssetTypeInsight
+func (i Insight) AssetType() AssetType { return AssetTypeInsight }
+
+// AssetID returns the insight ID
+func (i Insight) AssetID() uint { return i.ID }
+
+// WithStar returns a copy of the insight carrying the given star
+func (i Insight) WithStar(star *UserStar) Asset {
+	i.Star = star
+	return i
+}
+
+// Validate checks the fields of the insight and the placeholders of its
+// text. It returns a validation error listing every invalid field.
+func (i *Insight) Validate() error {
+	fields, err := structFieldErrors(i)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		if _, err := ParseInsightTemplate(i.Text); err != nil {
+			fields = append(fields, FieldError{Field: "text", Message: err.Error()})
+		}
+	}
+	if len(fields) > 0 {
+		return ValidationError(fields...)
+	}
+	return nil
 }
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// AssetLink links an asset to an asset derived from it: an audience to the
+// charts and insights about it, or a chart to the insights drawn from it. An
+// asset is linked to another at most once, enforced by the idx_asset_link
+// unique index.
+type AssetLink struct {
+	ID         uint      `json:"-" gorm:"primaryKey"`
+	SourceType AssetType `json:"sourcetype" gorm:"uniqueIndex:idx_asset_link"`
+	SourceID   uint      `json:"sourceid" gorm:"uniqueIndex:idx_asset_link"`
+	TargetType AssetType `json:"targettype" gorm:"uniqueIndex:idx_asset_link"`
+	TargetID   uint      `json:"targetid" gorm:"uniqueIndex:idx_asset_link"`
+}
+
+// linkTargets are the asset types that each asset type can be a source of
+var linkTargets = map[AssetType][]AssetType{
+	AssetTypeAudience: {AssetTypeChart, AssetTypeInsight},
+	AssetTypeChart:    {AssetTypeInsight},
+}
+
+// Validate checks that the source of the link can back its target: an
+// audience backs charts and insights and a chart backs insights
+func (l AssetLink) Validate() error {
+	if !slices.Contains(linkTargets[l.SourceType], l.TargetType) {
+		return InvalidField("sourcetype",
+			"a %s cannot be a source of a %s: charts are backed by audiences and insights by audiences and charts",
+			l.SourceType, l.TargetType)
+	}
+	return nil
+}
+
+// Source returns a reference to the source of the link
+func (l AssetLink) Source() AssetRef {
+	return NewAssetRef(l.SourceType, l.SourceID)
+}
+
+// Target returns a reference to the target of the link
+func (l AssetLink) Target() AssetRef {
+	return NewAssetRef(l.TargetType, l.TargetID)
+}
+
+// AssetRef is a link to an asset embedded in the REST payloads of the assets
+// it is linked to. Href is the REST path of the asset.
+type AssetRef struct {
+	Type AssetType `json:"type"`
+	ID   uint      `json:"id"`
+	Href string    `json:"href"`
+}
+
+// NewAssetRef returns a reference to the asset of the given type and ID
+func NewAssetRef(assetType AssetType, id uint) AssetRef {
+	return AssetRef{Type: assetType, ID: id, Href: fmt.Sprintf("/%s/%d", strings.ToLower(string(assetType)), id)}
+}
+
+// LinkedAudience is an audience as read, with the charts derived from it
+type LinkedAudience struct {
+	Audience
+	Charts []AssetRef `json:"charts"`
+}
+
+// LinkedChart is a chart as read, with the insights drawn from it
+type LinkedChart struct {
+	Chart
+	Insights []AssetRef `json:"insights"`
+}
+
+// LinkStatus is the linked state of two assets, as returned by the idempotent
+// link and unlink operations
+type LinkStatus struct {
+	Source AssetRef `json:"source"`
+	Target AssetRef `json:"target"`
+	Linked bool     `json:"linked"`
+}
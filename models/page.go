@@ -0,0 +1,107 @@
+package models
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+// Page size limits for cursor-paginated lists
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// cursorPrefix namespaces cursors so that arbitrary base64 strings are rejected
+const cursorPrefix = "id:"
+
+// PageRequest asks for at most Limit items whose ID is greater than After
+type PageRequest struct {
+	Limit int
+	After uint
+}
+
+// PageInfo describes where a page ends and whether more items follow it
+type PageInfo struct {
+	EndCursor   string `json:"endcursor"`
+	HasNextPage bool   `json:"hasnextpage"`
+}
+
+// Page is one page of a cursor-paginated list
+type Page[T any] struct {
+	Items    []T      `json:"items"`
+	PageInfo PageInfo `json:"pageinfo"`
+}
+
+// EncodeCursor returns the opaque cursor pointing at the item with the given ID
+func EncodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatUint(uint64(id), 10)))
+}
+
+// DecodeCursor returns the item ID a cursor points at
+func DecodeCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, InvalidField("after", "invalid cursor %q", cursor)
+	}
+
+	value, found := strings.CutPrefix(string(raw), cursorPrefix)
+	if !found {
+		return 0, InvalidField("after", "invalid cursor %q", cursor)
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, InvalidField("after", "invalid cursor %q", cursor)
+	}
+	return uint(id), nil
+}
+
+// NewPageRequest builds a PageRequest from user input. An omitted limit
+// selects DefaultPageSize, larger limits are capped at MaxPageSize and an
+// empty cursor starts from the beginning of the list. A zero limit asks for an
+// empty page, which only tells whether the list has items after the cursor.
+func NewPageRequest(limit *int, after string) (PageRequest, error) {
+	size := DefaultPageSize
+	if limit != nil {
+		size = *limit
+	}
+	if size < 0 {
+		return PageRequest{}, InvalidField("limit", "must not be negative")
+	}
+	if size > MaxPageSize {
+		size = MaxPageSize
+	}
+
+	page := PageRequest{Limit: size}
+	if after != "" {
+		id, err := DecodeCursor(after)
+		if err != nil {
+			return PageRequest{}, err
+		}
+		page.After = id
+	}
+	return page, nil
+}
+
+// NewPage builds a page from rows fetched in ID order with one row more than
+// the requested limit; the extra row only signals that a next page exists.
+// An empty page ends where it started, at the cursor of the request, so that
+// following it never restarts the list.
+func NewPage[T any](rows []T, request PageRequest, id func(T) uint) Page[T] {
+	page := Page[T]{Items: rows}
+	if len(rows) > request.Limit {
+		page.Items = rows[:request.Limit]
+		page.PageInfo.HasNextPage = true
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	switch {
+	case len(page.Items) > 0:
+		page.PageInfo.EndCursor = EncodeCursor(id(page.Items[len(page.Items)-1]))
+	case request.After > 0:
+		page.PageInfo.EndCursor = EncodeCursor(request.After)
+	}
+	return page
+}
@@ -0,0 +1,56 @@
+package models
+
+// Respondent is a surveyed person of the dataset that audiences are sized
+// against. Its characteristics take the same values as audience criteria.
+type Respondent struct {
+	ID            uint    `json:"id" gorm:"primaryKey"`
+	Gender        string  `json:"gender" validate:"required,oneof=Male Female Other"`
+	BirthCountry  string  `json:"birthcountry" validate:"required,country"`
+	AgeGroup      string  `json:"agegroup" validate:"required,oneof=18-24 25-34 35-44 45-54 55-64 65+"`
+	DailyHours    float64 `json:"dailyhours" validate:"min=0,max=24"`
+	NoOfPurchases int     `json:"noofpurchases" validate:"min=0"`
+}
+
+// Profile returns the characteristics audience expressions select on
+func (r Respondent) Profile() Profile {
+	return Profile{
+		Gender:       r.Gender,
+		BirthCountry: r.BirthCountry,
+		AgeGroup:     r.AgeGroup,
+		DailyHours:   r.DailyHours,
+		Purchases:    float64(r.NoOfPurchases),
+	}
+}
+
+// Validate rewrites the birth country as an alpha-2 code, the form audiences
+// store, and returns a validation error listing every invalid field
+func (r *Respondent) Validate() error {
+	if code, ok := CountryCode(r.BirthCountry); ok {
+		r.BirthCountry = code
+	}
+	return ValidateStruct(r)
+}
+
+// AudienceSize is the number of respondents matching an audience
+type AudienceSize struct {
+	AudienceID uint `json:"audienceid"`
+	// Count is the number of matching respondents
+	Count int64 `json:"count"`
+	// Base is the number of respondents in the dataset
+	Base int64 `json:"base"`
+	// Percentage is Count as a percentage of Base, or 0 for an empty dataset
+	Percentage float64 `json:"percentage"`
+	// AudienceVersion and DatasetVersion identify the audience definition and
+	// the dataset the size was computed for
+	AudienceVersion string `json:"audienceversion"`
+	DatasetVersion  string `json:"datasetversion"`
+}
+
+// NewAudienceSize returns the size of an audience matching count of base respondents
+func NewAudienceSize(audienceID uint, count, base int64) AudienceSize {
+	size := AudienceSize{AudienceID: audienceID, Count: count, Base: base}
+	if base > 0 {
+		size.Percentage = float64(count) * 100 / float64(base)
+	}
+	return size
+}
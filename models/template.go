@@ -0,0 +1,215 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Insight templates are texts with placeholders computed from the
+// respondents whenever the insight is read, e.g.
+//
+//	{{pct audience:12 where social_hours > 3}} of millennials spend more than 3 hours on social media daily
+//
+// A placeholder names a metric, optionally the audience it is about and
+// optionally a where clause in the audience expression language:
+//
+//	{{count}}                       respondents in the dataset
+//	{{count audience:12}}           respondents in audience 12
+//	{{count audience:12 where C}}   respondents in audience 12 matching C
+//	{{pct audience:12}}             audience 12 as a percentage of the dataset
+//	{{pct audience:12 where C}}     respondents matching C as a percentage of audience 12
+//
+// Without an audience the numbers are about every respondent.
+
+// InsightMetric is the number a placeholder renders
+type InsightMetric string
+
+// Insight metrics
+const (
+	MetricCount InsightMetric = "count"
+	MetricPct   InsightMetric = "pct"
+)
+
+// PlaceholderUnavailable is rendered for a number that cannot be computed,
+// such as a percentage of nobody or a number about a deleted audience
+const PlaceholderUnavailable = "n/a"
+
+// Placeholder is a number of an insight template computed from the respondents
+type Placeholder struct {
+	Metric InsightMetric
+	// AudienceID is the audience the number is about, or 0 for every respondent
+	AudienceID uint
+	// Where restricts the respondents counted, or is nil
+	Where Expression
+}
+
+// Format renders the number of a placeholder from the number of matching
+// respondents out of base respondents: a count, or a percentage rounded to a
+// whole number
+func (p Placeholder) Format(matched, base int64) string {
+	if p.Metric == MetricCount {
+		return strconv.FormatInt(matched, 10)
+	}
+	if base == 0 {
+		return PlaceholderUnavailable
+	}
+	return strconv.FormatFloat(math.Round(float64(matched)*100/float64(base)), 'f', 0, 64) + "%"
+}
+
+// InsightTemplate is the text of an insight split into literal text and
+// placeholders
+type InsightTemplate struct {
+	segments []templateSegment
+}
+
+// templateSegment is either literal text or a placeholder
+type templateSegment struct {
+	text        string
+	placeholder *Placeholder
+}
+
+// TemplateSyntaxError reports why an insight template could not be parsed
+type TemplateSyntaxError struct {
+	// Position is the 1-based offset of the offending character
+	Position int
+	Message  string
+}
+
+func (e *TemplateSyntaxError) Error() string {
+	return fmt.Sprintf("at position %d: %s", e.Position, e.Message)
+}
+
+// ParseInsightTemplate splits text into literal text and placeholders.
+// Errors are returned as a *TemplateSyntaxError.
+func ParseInsightTemplate(text string) (InsightTemplate, error) {
+	var template InsightTemplate
+	runes := []rune(text)
+	literal := 0
+	for i := 0; i+1 < len(runes); i++ {
+		if runes[i] != '{' || runes[i+1] != '{' {
+			continue
+		}
+		end := closingBraces(runes, i+2)
+		if end < 0 {
+			return InsightTemplate{}, &TemplateSyntaxError{Position: i + 1, Message: `placeholder is not closed with "}}"`}
+		}
+		placeholder, err := parsePlaceholder(runes[i+2:end], i+3)
+		if err != nil {
+			return InsightTemplate{}, err
+		}
+		if i > literal {
+			template.segments = append(template.segments, templateSegment{text: string(runes[literal:i])})
+		}
+		template.segments = append(template.segments, templateSegment{placeholder: placeholder})
+		literal = end + 2
+		i = end + 1
+	}
+	if literal < len(runes) {
+		template.segments = append(template.segments, templateSegment{text: string(runes[literal:])})
+	}
+	return template, nil
+}
+
+// closingBraces returns the index of the "}}" closing a placeholder opened
+// before from, or -1
+func closingBraces(runes []rune, from int) int {
+	for i := from; i+1 < len(runes); i++ {
+		if runes[i] == '}' && runes[i+1] == '}' {
+			return i
+		}
+	}
+	return -1
+}
+
+// parsePlaceholder parses the body of a placeholder, between the braces.
+// offset is the position of the body in the template.
+func parsePlaceholder(body []rune, offset int) (*Placeholder, error) {
+	errorAt := func(i int, format string, args ...any) error {
+		return &TemplateSyntaxError{Position: offset + i, Message: fmt.Sprintf(format, args...)}
+	}
+	// word returns the word starting at the first non-space rune from i, and
+	// its start and end
+	word := func(i int) (string, int, int) {
+		for i < len(body) && unicode.IsSpace(body[i]) {
+			i++
+		}
+		start := i
+		for i < len(body) && !unicode.IsSpace(body[i]) {
+			i++
+		}
+		return string(body[start:i]), start, i
+	}
+
+	metric, start, i := word(0)
+	placeholder := &Placeholder{Metric: InsightMetric(strings.ToLower(metric))}
+	switch {
+	case metric == "":
+		return nil, errorAt(start, "expected a metric: count or pct")
+	case placeholder.Metric != MetricCount && placeholder.Metric != MetricPct:
+		return nil, errorAt(start, "unknown metric %q: must be count or pct", metric)
+	}
+
+	next, start, end := word(i)
+	if name, id, found := strings.Cut(next, ":"); found && strings.EqualFold(name, "audience") {
+		audienceID, err := strconv.ParseUint(id, 10, 64)
+		if err != nil || audienceID == 0 {
+			return nil, errorAt(start, "invalid audience %q", next)
+		}
+		placeholder.AudienceID = uint(audienceID)
+		next, start, end = word(end)
+	}
+
+	switch {
+	case next == "":
+		return placeholder, nil
+	case !strings.EqualFold(next, "where"):
+		return nil, errorAt(start, "unexpected %q: expected audience:<id> or where", next)
+	}
+	condition := string(body[end:])
+	if strings.TrimSpace(condition) == "" {
+		return nil, errorAt(end, "expected a condition after where")
+	}
+	where, err := ParseExpression(condition)
+	var syntaxErr *ExpressionSyntaxError
+	if errors.As(err, &syntaxErr) {
+		return nil, errorAt(end+syntaxErr.Position-1, "%s", syntaxErr.Message)
+	}
+	if err != nil {
+		return nil, errorAt(end, "%s", err.Error())
+	}
+	placeholder.Where = where
+	return placeholder, nil
+}
+
+// Placeholders returns the placeholders of the template in order
+func (t InsightTemplate) Placeholders() []Placeholder {
+	var placeholders []Placeholder
+	for _, segment := range t.segments {
+		if segment.placeholder != nil {
+			placeholders = append(placeholders, *segment.placeholder)
+		}
+	}
+	return placeholders
+}
+
+// Render returns the text of the template with every placeholder replaced by
+// its value
+func (t InsightTemplate) Render(value func(Placeholder) (string, error)) (string, error) {
+	var text strings.Builder
+	for _, segment := range t.segments {
+		if segment.placeholder == nil {
+			text.WriteString(segment.text)
+			continue
+		}
+		rendered, err := value(*segment.placeholder)
+		if err != nil {
+			return "", err
+		}
+		text.WriteString(rendered)
+	}
+	return text.String(), nil
+}
@@ -3,6 +3,8 @@ package models
 import (
 	"database/sql/driver"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // AssetType represents the type of asset that can be starred
@@ -24,6 +26,17 @@ func (at AssetType) IsValid() bool {
 	return false
 }
 
+// ParseAssetType converts a string into an AssetType, ignoring case
+func ParseAssetType(value string) (AssetType, error) {
+	for _, at := range []AssetType{AssetTypeAudience, AssetTypeChart, AssetTypeInsight} {
+		if strings.EqualFold(value, string(at)) {
+			return at, nil
+		}
+	}
+	return "", InvalidField("type", "invalid asset type %q: must be one of %s, %s or %s",
+		value, AssetTypeAudience, AssetTypeChart, AssetTypeInsight)
+}
+
 // String returns the string representation of AssetType
 func (at AssetType) String() string {
 	return string(at)
@@ -60,9 +73,25 @@ func (at *AssetType) Scan(value any) error {
 	return nil
 }
 
+// UserStar marks an asset as a favourite of a user. A user can star each
+// asset at most once, enforced by the idx_user_star_asset unique index.
+// Description is the user's own note on the favourite, so two users can
+// annotate the same asset differently.
 type UserStar struct {
-	ID      uint      `json:"id" gorm:"primaryKey"`
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"userid" gorm:"uniqueIndex:idx_user_star_asset"`
+	Type        AssetType `json:"type" gorm:"uniqueIndex:idx_user_star_asset"`
+	AssetID     uint      `json:"assetid" gorm:"uniqueIndex:idx_user_star_asset"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdat"`
+}
+
+// StarStatus is the starred state of an asset for a user, as returned by the
+// idempotent star and unstar operations
+type StarStatus struct {
 	UserID  uint      `json:"userid"`
 	Type    AssetType `json:"type"`
 	AssetID uint      `json:"assetid"`
+	Starred bool      `json:"starred"`
+	Star    *UserStar `json:"star,omitempty"`
 }
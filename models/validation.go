@@ -0,0 +1,129 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/language"
+)
+
+// validate checks the `validate` struct tags of the models, reporting fields
+// by their JSON names
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(JSONFieldName)
+	v.RegisterAlias("country", "iso3166_1_alpha2|iso3166_1_alpha3")
+	return v
+}
+
+// CountryCode returns the ISO 3166-1 alpha-2 code of a country given by its
+// upper-case alpha-2 or alpha-3 code, so that both forms of a country compare
+// equal, and false if code is neither
+func CountryCode(code string) (string, bool) {
+	if validate.Var(code, "country") != nil {
+		return "", false
+	}
+	if len(code) == 2 {
+		return code, true
+	}
+	// Kosovo has no ISO code, UNK and XK are the codes in common use
+	if code == "UNK" {
+		return "XK", true
+	}
+	region, err := language.ParseRegion(code)
+	if err != nil {
+		return "", false
+	}
+	return region.String(), true
+}
+
+// JSONFieldName returns the name of a struct field in JSON, or "" if the
+// field is not serialized
+func JSONFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// ValidateStruct checks the `validate` tags of value, a pointer to a model,
+// and returns a validation error listing every invalid field
+func ValidateStruct(value any) error {
+	fields, err := structFieldErrors(value)
+	if err != nil {
+		return err
+	}
+	if len(fields) > 0 {
+		return ValidationError(fields...)
+	}
+	return nil
+}
+
+// structFieldErrors returns the fields of value violating their `validate` tags
+func structFieldErrors(value any) ([]FieldError, error) {
+	err := validate.Struct(value)
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return FieldErrors(validationErrs), nil
+	}
+	return nil, err
+}
+
+// FieldErrors describes the failed rules of a struct validation. Fields are
+// named by their path below the validated struct, e.g. series[0].name.
+func FieldErrors(validationErrs validator.ValidationErrors) []FieldError {
+	fields := make([]FieldError, len(validationErrs))
+	for i, fieldErr := range validationErrs {
+		path := fieldErr.Namespace()
+		if _, below, found := strings.Cut(path, "."); found {
+			path = below
+		}
+		fields[i] = FieldError{Field: path, Message: ruleMessage(fieldErr)}
+	}
+	return fields
+}
+
+// ruleMessage describes a failed validation rule
+func ruleMessage(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+	isText := fieldErr.Kind() == reflect.String
+	isList := fieldErr.Kind() == reflect.Slice || fieldErr.Kind() == reflect.Map
+
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	case "country":
+		return "must be an ISO 3166-1 alpha-2 or alpha-3 country code"
+	case "min", "gte":
+		switch {
+		case isText:
+			return fmt.Sprintf("must be at least %s characters long", param)
+		case isList:
+			return fmt.Sprintf("must have at least %s items", param)
+		}
+		return "must be at least " + param
+	case "max", "lte":
+		switch {
+		case isText:
+			return fmt.Sprintf("must be at most %s characters long", param)
+		case isList:
+			return fmt.Sprintf("must have at most %s items", param)
+		}
+		return "must be at most " + param
+	}
+	if param != "" {
+		return fmt.Sprintf("must satisfy %s=%s", fieldErr.Tag(), param)
+	}
+	return "must satisfy " + fieldErr.Tag()
+}
@@ -0,0 +1,63 @@
+// Package cache puts a read-through cache in front of the favourites of
+// users and of the sizes and comparisons of audiences. Writes made through
+// the wrapped repositories invalidate the cached favourites of exactly the
+// users they affect.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Default settings of the cache configured from the environment
+const (
+	DefaultSize = 10000
+	DefaultTTL  = 5 * time.Minute
+)
+
+// Cache stores byte values under string keys. Its operations map onto the
+// Redis GET, SET with PX and DEL commands, so that a shared Redis can replace
+// the in-process LRU.
+type Cache interface {
+	// Get returns the value stored under key and whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key. Entries expire after ttl, or never if it is zero.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the given keys, ignoring missing ones
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// FromEnv returns the cache selected by the CACHE environment variable and
+// the time to live of its entries. CACHE is "lru" (the default) for an
+// in-process LRU holding CACHE_SIZE entries, or "none" to disable caching,
+// in which case the returned Cache is nil. CACHE_TTL is a Go duration.
+func FromEnv() (Cache, time.Duration, error) {
+	ttl := DefaultTTL
+	if value := os.Getenv("CACHE_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			return nil, 0, fmt.Errorf("invalid CACHE_TTL %q: must be a non-negative duration", value)
+		}
+		ttl = parsed
+	}
+
+	switch backend := os.Getenv("CACHE"); backend {
+	case "", "lru":
+		size := DefaultSize
+		if value := os.Getenv("CACHE_SIZE"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed <= 0 {
+				return nil, 0, fmt.Errorf("invalid CACHE_SIZE %q: must be a positive number", value)
+			}
+			size = parsed
+		}
+		return NewLRU(size), ttl, nil
+	case "none":
+		return nil, 0, nil
+	default:
+		return nil, 0, fmt.Errorf("invalid CACHE %q: must be lru or none", backend)
+	}
+}
@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+)
+
+// The favourites of a user are cached as a whole under a key that includes a
+// generation token, which is itself cached under the user's generation key.
+// Invalidating deletes the generation key, so that the next read starts a new
+// generation. A read that raced with an invalidation can only store its
+// result under the old generation, where it is never served again.
+
+// generationKey returns the key of the current generation of a user's favourites
+func generationKey(userID uint) string {
+	return fmt.Sprintf("favourites:%d:generation", userID)
+}
+
+// favouritesFormat is the version of the cached form of favourites. Changing
+// the cached form needs a new version, so that a shared cache never serves
+// entries written by an older binary.
+const favouritesFormat = 2
+
+// favouritesKey returns the key of a user's favourites in a generation
+func favouritesKey(userID uint, generation string) string {
+	return fmt.Sprintf("favourites:v%d:%d:%s", favouritesFormat, userID, generation)
+}
+
+// cachedFavourite is the cached form of a models.Favourite. Exactly one of
+// the asset fields is set, as the Asset interface cannot be decoded.
+type cachedFavourite struct {
+	Star     models.UserStar  `json:"star"`
+	Audience *models.Audience `json:"audience,omitempty"`
+	Chart    *models.Chart    `json:"chart,omitempty"`
+	Insight  *models.Insight  `json:"insight,omitempty"`
+}
+
+// favouriteRepository reads the favourites of users through the cache
+type favouriteRepository struct {
+	next  repository.FavouriteRepository
+	cache Cache
+	ttl   time.Duration
+}
+
+func (r *favouriteRepository) ListByUser(ctx context.Context, userID uint, page models.PageRequest) (models.Page[models.Favourite], error) {
+	favourites, err := r.AllByUser(ctx, userID)
+	if err != nil {
+		return models.Page[models.Favourite]{}, err
+	}
+
+	// Favourites are ordered by star ID, the key of the page cursor
+	start := 0
+	for start < len(favourites) && favourites[start].ID <= page.After {
+		start++
+	}
+	end := min(start+page.Limit+1, len(favourites))
+	return models.NewPage(favourites[start:end], page, func(favourite models.Favourite) uint { return favourite.ID }), nil
+}
+
+// AllByUser returns the cached favourites of a user, loading and caching
+// them on a miss. Cache failures are logged and the favourites are loaded
+// from the underlying repository instead.
+func (r *favouriteRepository) AllByUser(ctx context.Context, userID uint) ([]models.Favourite, error) {
+	generation, err := r.generation(ctx, userID)
+	if err != nil {
+		log.Printf("cache: failed to read favourites generation of user %d: %v", userID, err)
+		return r.next.AllByUser(ctx, userID)
+	}
+
+	key := favouritesKey(userID, generation)
+	if value, found, err := r.cache.Get(ctx, key); err != nil {
+		log.Printf("cache: failed to read favourites of user %d: %v", userID, err)
+	} else if found {
+		favourites, err := decodeFavourites(value)
+		if err == nil {
+			return favourites, nil
+		}
+		log.Printf("cache: failed to decode favourites of user %d: %v", userID, err)
+	}
+
+	favourites, err := r.next.AllByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := encodeFavourites(favourites)
+	if err == nil {
+		err = r.cache.Set(ctx, key, value, r.ttl)
+	}
+	if err != nil {
+		log.Printf("cache: failed to store favourites of user %d: %v", userID, err)
+	}
+	return favourites, nil
+}
+
+// generation returns the current generation of a user's favourites, starting
+// a new one if there is none. The new generation is stored before any
+// favourites are loaded, so that an invalidation happening during the load
+// discards them.
+func (r *favouriteRepository) generation(ctx context.Context, userID uint) (string, error) {
+	value, found, err := r.cache.Get(ctx, generationKey(userID))
+	if err != nil {
+		return "", err
+	}
+	if found {
+		return string(value), nil
+	}
+
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	generation := hex.EncodeToString(token)
+	if err := r.cache.Set(ctx, generationKey(userID), []byte(generation), r.ttl); err != nil {
+		return "", err
+	}
+	return generation, nil
+}
+
+// encodeFavourites serializes favourites for the cache
+func encodeFavourites(favourites []models.Favourite) ([]byte, error) {
+	cached := make([]cachedFavourite, len(favourites))
+	for i, favourite := range favourites {
+		cached[i].Star = favourite.UserStar
+		switch asset := favourite.Asset.(type) {
+		case models.Audience:
+			cached[i].Audience = &asset
+		case models.Chart:
+			cached[i].Chart = &asset
+		case models.Insight:
+			cached[i].Insight = &asset
+		default:
+			return nil, fmt.Errorf("unsupported asset %T", favourite.Asset)
+		}
+	}
+	return json.Marshal(cached)
+}
+
+// decodeFavourites restores favourites serialized by encodeFavourites
+func decodeFavourites(value []byte) ([]models.Favourite, error) {
+	var cached []cachedFavourite
+	if err := json.Unmarshal(value, &cached); err != nil {
+		return nil, err
+	}
+
+	favourites := make([]models.Favourite, len(cached))
+	for i, entry := range cached {
+		var asset models.Asset
+		switch {
+		case entry.Audience != nil:
+			asset = *entry.Audience
+		case entry.Chart != nil:
+			asset = *entry.Chart
+		case entry.Insight != nil:
+			asset = *entry.Insight
+		default:
+			return nil, fmt.Errorf("favourite %d has no asset", entry.Star.ID)
+		}
+		favourites[i] = models.Favourite{UserStar: entry.Star, Asset: asset.WithStar(&entry.Star)}
+	}
+	return favourites, nil
+}
@@ -0,0 +1,185 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+)
+
+// Wrap returns repos with the favourites and the audience sizes and
+// comparisons read through c, cached for ttl. Writes through the returned
+// star and asset repositories invalidate the cached favourites of the users
+// they affect; writes that bypass them leave the cache stale until the
+// entries expire. Audience sizes and comparisons are cached per version of
+// the audiences and of the respondents and are never stale.
+func Wrap(repos repository.Repositories, c Cache, ttl time.Duration) repository.Repositories {
+	invalidator := &invalidator{cache: c, stars: repos.Stars}
+	wrapped := repository.Repositories{
+		Charts: &assetRepository[models.Chart]{
+			AssetRepository: repos.Charts, invalidator: invalidator, assetType: models.AssetTypeChart,
+		},
+		Insights: &assetRepository[models.Insight]{
+			AssetRepository: repos.Insights, invalidator: invalidator, assetType: models.AssetTypeInsight,
+		},
+		Audiences: &assetRepository[models.Audience]{
+			AssetRepository: repos.Audiences, invalidator: invalidator, assetType: models.AssetTypeAudience,
+		},
+		Stars:       &starRepository{StarRepository: repos.Stars, invalidator: invalidator},
+		Links:       repos.Links,
+		Favourites:  &favouriteRepository{next: repos.Favourites, cache: c, ttl: ttl},
+		Respondents: repos.Respondents,
+		AudienceSizes: &audienceSizeRepository{
+			next: repos.AudienceSizes, respondents: repos.Respondents, cache: c, ttl: ttl,
+		},
+	}
+	// generated charts are stored through the wrapped charts, so that
+	// refreshing a starred chart invalidates the favourites showing it, and
+	// insights are rendered from cached sizes
+	wrapped.ChartGenerator = repository.NewChartGenerator(wrapped)
+	wrapped.InsightRenderer = repository.NewInsightRenderer(wrapped)
+	return wrapped
+}
+
+// invalidator discards the cached favourites of users
+type invalidator struct {
+	cache Cache
+	stars repository.StarRepository
+}
+
+// users invalidates the cached favourites of the given users. Failures are
+// logged, the write that caused the invalidation has already succeeded.
+func (i *invalidator) users(ctx context.Context, userIDs ...uint) {
+	keys := make([]string, len(userIDs))
+	for n, userID := range userIDs {
+		keys[n] = generationKey(userID)
+	}
+	if err := i.cache.Delete(ctx, keys...); err != nil {
+		log.Printf("cache: failed to invalidate favourites of users %v: %v", userIDs, err)
+	}
+}
+
+// starredBy returns the users who starred an asset
+func (i *invalidator) starredBy(ctx context.Context, assetType models.AssetType, assetID uint) ([]uint, error) {
+	stars, err := i.stars.AllByAsset(ctx, assetType, assetID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]uint, len(stars))
+	for n, star := range stars {
+		userIDs[n] = star.UserID
+	}
+	return userIDs, nil
+}
+
+// assetRepository invalidates the favourites of the users who starred an
+// asset when it is updated or deleted
+type assetRepository[T models.Asset] struct {
+	repository.AssetRepository[T]
+	invalidator *invalidator
+	assetType   models.AssetType
+}
+
+func (r *assetRepository[T]) Update(ctx context.Context, asset *T) error {
+	if err := r.AssetRepository.Update(ctx, asset); err != nil {
+		return err
+	}
+
+	userIDs, err := r.invalidator.starredBy(ctx, r.assetType, (*asset).AssetID())
+	if err != nil {
+		log.Printf("cache: failed to find the users who starred %s %d: %v", r.assetType, (*asset).AssetID(), err)
+		return nil
+	}
+	r.invalidator.users(ctx, userIDs...)
+	return nil
+}
+
+// Delete looks up the users who starred the asset first, as the delete
+// policy may remove their stars
+func (r *assetRepository[T]) Delete(ctx context.Context, id uint) error {
+	userIDs, err := r.invalidator.starredBy(ctx, r.assetType, id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.AssetRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.invalidator.users(ctx, userIDs...)
+	return nil
+}
+
+// starRepository invalidates the favourites of the users whose stars change
+type starRepository struct {
+	repository.StarRepository
+	invalidator *invalidator
+}
+
+func (r *starRepository) Create(ctx context.Context, star *models.UserStar) error {
+	if err := r.StarRepository.Create(ctx, star); err != nil {
+		return err
+	}
+	r.invalidator.users(ctx, star.UserID)
+	return nil
+}
+
+// Update invalidates both the previous and the new owner of the star
+func (r *starRepository) Update(ctx context.Context, star *models.UserStar) error {
+	userIDs := []uint{star.UserID}
+	previous, err := r.StarRepository.Get(ctx, star.ID)
+	switch {
+	case err == nil:
+		userIDs = append(userIDs, previous.UserID)
+	case !errors.Is(err, repository.ErrNotFound):
+		return err
+	}
+
+	if err := r.StarRepository.Update(ctx, star); err != nil {
+		return err
+	}
+	r.invalidator.users(ctx, userIDs...)
+	return nil
+}
+
+func (r *starRepository) Delete(ctx context.Context, id uint) error {
+	star, err := r.StarRepository.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return r.StarRepository.Delete(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := r.StarRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.invalidator.users(ctx, star.UserID)
+	return nil
+}
+
+func (r *starRepository) Star(ctx context.Context, userID uint, assetType models.AssetType, assetID uint) (models.UserStar, bool, error) {
+	star, created, err := r.StarRepository.Star(ctx, userID, assetType, assetID)
+	if err == nil && created {
+		r.invalidator.users(ctx, userID)
+	}
+	return star, created, err
+}
+
+func (r *starRepository) Unstar(ctx context.Context, userID uint, assetType models.AssetType, assetID uint) (bool, error) {
+	removed, err := r.StarRepository.Unstar(ctx, userID, assetType, assetID)
+	if err == nil && removed {
+		r.invalidator.users(ctx, userID)
+	}
+	return removed, err
+}
+
+func (r *starRepository) UpdateDescription(ctx context.Context, userID uint, assetType models.AssetType, assetID uint, description string) (models.UserStar, error) {
+	star, err := r.StarRepository.UpdateDescription(ctx, userID, assetType, assetID, description)
+	if err == nil {
+		r.invalidator.users(ctx, userID)
+	}
+	return star, err
+}
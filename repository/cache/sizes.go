@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+)
+
+// Audience sizes and comparisons are cached under the versions of the audience and of the
+// respondents dataset. Updating either changes the key, so cached sizes never
+// need to be invalidated and outdated ones simply expire.
+
+// sizeKey returns the key of the size of an audience version on a dataset version
+func sizeKey(audienceID uint, audienceVersion, datasetVersion string) string {
+	return fmt.Sprintf("audience-size:%d:%s:%s", audienceID, audienceVersion, datasetVersion)
+}
+
+// audienceSizeRepository reads audience sizes and comparisons through the cache
+type audienceSizeRepository struct {
+	next        repository.AudienceSizeRepository
+	respondents repository.RespondentRepository
+	cache       Cache
+	ttl         time.Duration
+}
+
+// Size returns the cached size of the audience, computing and caching it on
+// a miss
+func (r *audienceSizeRepository) Size(ctx context.Context, audience models.Audience) (models.AudienceSize, error) {
+	audienceVersion, err := audience.Version()
+	if err != nil {
+		return models.AudienceSize{}, err
+	}
+	datasetVersion, err := r.respondents.Version(ctx)
+	if err != nil {
+		return models.AudienceSize{}, err
+	}
+
+	what := fmt.Sprintf("size of audience %d", audience.ID)
+	return readThrough(ctx, r, sizeKey(audience.ID, audienceVersion, datasetVersion), what, datasetVersion,
+		func() (models.AudienceSize, string, error) {
+			size, err := r.next.Size(ctx, audience)
+			return size, size.DatasetVersion, err
+		})
+}
+
+// Compare returns the cached comparison of the audiences, computing and
+// caching it on a miss
+func (r *audienceSizeRepository) Compare(ctx context.Context, audiences []models.Audience) (models.AudienceComparison, error) {
+	audienceKeys := make([]string, len(audiences))
+	for i, audience := range audiences {
+		version, err := audience.Version()
+		if err != nil {
+			return models.AudienceComparison{}, err
+		}
+		audienceKeys[i] = fmt.Sprintf("%d:%s", audience.ID, version)
+	}
+	datasetVersion, err := r.respondents.Version(ctx)
+	if err != nil {
+		return models.AudienceComparison{}, err
+	}
+
+	key := fmt.Sprintf("audience-comparison:%s:%s", strings.Join(audienceKeys, ","), datasetVersion)
+	return readThrough(ctx, r, key, "audience comparison", datasetVersion,
+		func() (models.AudienceComparison, string, error) {
+			comparison, err := r.next.Compare(ctx, audiences)
+			return comparison, comparison.DatasetVersion, err
+		})
+}
+
+// readThrough returns the value cached under key, or computes and caches it.
+// compute returns the dataset version the value was computed on, and a value
+// computed on a dataset replaced in the meantime belongs to another key so it
+// is not cached. Cache failures are logged and the value is computed instead.
+func readThrough[T any](ctx context.Context, r *audienceSizeRepository, key, what, datasetVersion string, compute func() (T, string, error)) (T, error) {
+	if value, found, err := r.cache.Get(ctx, key); err != nil {
+		log.Printf("cache: failed to read %s: %v", what, err)
+	} else if found {
+		var cached T
+		err := json.Unmarshal(value, &cached)
+		if err == nil {
+			return cached, nil
+		}
+		log.Printf("cache: failed to decode %s: %v", what, err)
+	}
+
+	result, computedOn, err := compute()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if computedOn != datasetVersion {
+		return result, nil
+	}
+
+	value, err := json.Marshal(result)
+	if err == nil {
+		err = r.cache.Set(ctx, key, value, r.ttl)
+	}
+	if err != nil {
+		log.Printf("cache: failed to store %s: %v", what, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"platform-go-challenge/models"
+)
+
+// chartGenerator generates charts by breaking audiences down on the
+// respondents and stores them through the chart repository
+type chartGenerator struct {
+	audiences   AudienceRepository
+	charts      ChartRepository
+	links       LinkRepository
+	respondents RespondentRepository
+}
+
+// NewChartGenerator returns a ChartGenerator reading the audiences and the
+// respondents of repos, storing the charts in repos.Charts and linking them
+// in repos.Links
+func NewChartGenerator(repos Repositories) ChartGenerator {
+	return &chartGenerator{audiences: repos.Audiences, charts: repos.Charts, links: repos.Links, respondents: repos.Respondents}
+}
+
+// Generate returns ErrNotFound if the audience does not exist. The chart is
+// deleted again if it cannot be linked to its audience, e.g. because the
+// audience was deleted meanwhile, so that no chart is left without its link.
+func (g *chartGenerator) Generate(ctx context.Context, audienceID uint, dimension models.Dimension) (models.Chart, error) {
+	audience, err := g.audiences.Get(ctx, audienceID)
+	if err != nil {
+		return models.Chart{}, err
+	}
+
+	counts, provenance, err := g.breakdown(ctx, audience, dimension)
+	if err != nil {
+		return models.Chart{}, err
+	}
+	chart := models.NewAudienceChart(audience.ID, dimension, counts)
+	chart.Provenance = provenance
+	if err := g.charts.Create(ctx, &chart); err != nil {
+		return models.Chart{}, err
+	}
+	link := models.AssetLink{
+		SourceType: models.AssetTypeAudience, SourceID: audience.ID,
+		TargetType: models.AssetTypeChart, TargetID: chart.ID,
+	}
+	if _, err := g.links.Link(ctx, link); err != nil {
+		if deleteErr := g.charts.Delete(ctx, chart.ID); deleteErr != nil {
+			return models.Chart{}, errors.Join(err, deleteErr)
+		}
+		return models.Chart{}, err
+	}
+	return chart, nil
+}
+
+// Refresh replaces the labels and series of the chart, keeping its titles.
+// It returns ErrNotFound if the chart does not exist and a conflict if it was
+// not generated or its audience was deleted since.
+func (g *chartGenerator) Refresh(ctx context.Context, chartID uint) (models.Chart, error) {
+	chart, err := g.charts.Get(ctx, chartID)
+	if err != nil {
+		return models.Chart{}, err
+	}
+	if chart.Provenance == nil {
+		return models.Chart{}, models.ConflictError("chart %d was not generated from an audience", chartID)
+	}
+
+	audience, err := g.audiences.Get(ctx, chart.Provenance.AudienceID)
+	if errors.Is(err, ErrNotFound) {
+		return models.Chart{}, models.ConflictError("audience %d of chart %d no longer exists", chart.Provenance.AudienceID, chartID)
+	}
+	if err != nil {
+		return models.Chart{}, err
+	}
+
+	dimension := chart.Provenance.Dimension
+	counts, provenance, err := g.breakdown(ctx, audience, dimension)
+	if err != nil {
+		return models.Chart{}, err
+	}
+	chart.SetBreakdown(dimension, counts)
+	chart.Provenance = provenance
+	if err := g.charts.Update(ctx, &chart); err != nil {
+		return models.Chart{}, err
+	}
+	return chart, nil
+}
+
+// breakdown counts the respondents of the audience by dimension. The dataset
+// version is read before counting, like for audience sizes.
+func (g *chartGenerator) breakdown(ctx context.Context, audience models.Audience, dimension models.Dimension) (map[string]int64, *models.ChartProvenance, error) {
+	definition, err := audience.Definition()
+	if err != nil {
+		return nil, nil, err
+	}
+	audienceVersion, err := audience.Version()
+	if err != nil {
+		return nil, nil, err
+	}
+	datasetVersion, err := g.respondents.Version(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	counts, err := g.respondents.Breakdown(ctx, definition, dimension)
+	if err != nil {
+		return nil, nil, err
+	}
+	return counts, &models.ChartProvenance{
+		AudienceID:      audience.ID,
+		Dimension:       dimension,
+		AudienceVersion: audienceVersion,
+		DatasetVersion:  datasetVersion,
+		GeneratedAt:     time.Now().UTC(),
+	}, nil
+}
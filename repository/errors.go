@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"platform-go-challenge/models"
+)
+
+var (
+	// ErrNotFound is returned when the requested record does not exist
+	ErrNotFound = models.NewError(models.ErrorNotFound, models.CodeNotFound, "record not found")
+	// ErrInvalidAssetType is returned when a star references an unknown asset type
+	ErrInvalidAssetType = models.NewError(models.ErrorValidation, "INVALID_ASSET_TYPE", "invalid asset type")
+	// ErrAssetNotFound is returned when a star references an asset that does not exist
+	ErrAssetNotFound = models.NewError(models.ErrorUnprocessable, "ASSET_NOT_FOUND", "asset not found")
+	// ErrAssetStarred is returned when deleting a starred asset under the restrict policy
+	ErrAssetStarred = models.NewError(models.ErrorConflict, "ASSET_STARRED", "asset is starred by at least one user")
+	// ErrDuplicateStar is returned when a user stars the same asset twice
+	ErrDuplicateStar = models.NewError(models.ErrorConflict, "DUPLICATE_STAR", "user has already starred this asset")
+	// ErrStarNotFound is returned when updating a favourite the user has not starred
+	ErrStarNotFound = models.NewError(models.ErrorNotFound, "STAR_NOT_FOUND", "asset is not starred by this user")
+	// ErrUnavailable is returned when the storage cannot be reached. The
+	// request may succeed once it is back.
+	ErrUnavailable = models.NewError(models.ErrorUnavailable, "UNAVAILABLE", "storage is unavailable").
+			WithRetryAfter(DefaultRetryAfter)
+)
+
+// DefaultRetryAfter is how long clients are asked to wait before retrying a
+// request that failed because the storage was unavailable
+const DefaultRetryAfter = 5 * time.Second
+
+// CheckAssetType returns ErrInvalidAssetType if a star references an unknown asset type
+func CheckAssetType(assetType models.AssetType) error {
+	if !assetType.IsValid() {
+		message := fmt.Sprintf("must be one of %s, %s or %s",
+			models.AssetTypeAudience, models.AssetTypeChart, models.AssetTypeInsight)
+		return ErrInvalidAssetType.
+			Withf("%s %q: %s", ErrInvalidAssetType.Message, assetType, message).
+			WithFields(models.FieldError{Field: "type", Message: message})
+	}
+	return nil
+}
+
+// CheckLink returns ErrInvalidAssetType if a link references an unknown asset
+// type and a validation error if its source cannot back its target
+func CheckLink(link models.AssetLink) error {
+	if err := CheckAssetType(link.SourceType); err != nil {
+		return err
+	}
+	if err := CheckAssetType(link.TargetType); err != nil {
+		return err
+	}
+	return link.Validate()
+}
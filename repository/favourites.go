@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"platform-go-challenge/models"
+)
+
+// favouriteRepository reads favourites from the star and asset repositories
+type favouriteRepository struct {
+	repos Repositories
+}
+
+// NewFavouriteRepository returns a FavouriteRepository reading the stars and
+// assets of repos
+func NewFavouriteRepository(repos Repositories) FavouriteRepository {
+	return &favouriteRepository{repos: repos}
+}
+
+func (r *favouriteRepository) ListByUser(ctx context.Context, userID uint, page models.PageRequest) (models.Page[models.Favourite], error) {
+	starPage, err := r.repos.Stars.ListByUser(ctx, userID, page)
+	if err != nil {
+		return models.Page[models.Favourite]{}, err
+	}
+
+	favourites, err := r.repos.LoadFavourites(ctx, starPage.Items)
+	if err != nil {
+		return models.Page[models.Favourite]{}, err
+	}
+	return models.Page[models.Favourite]{Items: favourites, PageInfo: starPage.PageInfo}, nil
+}
+
+func (r *favouriteRepository) AllByUser(ctx context.Context, userID uint) ([]models.Favourite, error) {
+	stars, err := r.repos.Stars.AllByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return r.repos.LoadFavourites(ctx, stars)
+}
+
+// LoadFavourites loads the assets of the given stars with one lookup per
+// asset type and returns them in the order of the stars. Each asset carries
+// the star it was loaded through; stars whose asset no longer exists are skipped.
+func (r Repositories) LoadFavourites(ctx context.Context, stars []models.UserStar) ([]models.Favourite, error) {
+	// Group asset IDs by type
+	ids := make(map[models.AssetType][]uint)
+	for _, star := range stars {
+		ids[star.Type] = append(ids[star.Type], star.AssetID)
+	}
+
+	assets, err := r.loadAssetsByType(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	favourites := make([]models.Favourite, 0, len(stars))
+	for _, star := range stars {
+		asset, found := assets[star.Type][star.AssetID]
+		if !found {
+			continue
+		}
+		favourites = append(favourites, models.Favourite{UserStar: star, Asset: asset.WithStar(&star)})
+	}
+	return favourites, nil
+}
+
+// loadAssetsByType fetches the assets with the given IDs by type, with one
+// lookup per asset type, keyed by type and ID
+func (r Repositories) loadAssetsByType(ctx context.Context, ids map[models.AssetType][]uint) (map[models.AssetType]map[uint]models.Asset, error) {
+	assets := make(map[models.AssetType]map[uint]models.Asset, len(ids))
+	var err error
+	if assets[models.AssetTypeAudience], err = loadAssets(ctx, r.Audiences, ids[models.AssetTypeAudience]); err != nil {
+		return nil, err
+	}
+	if assets[models.AssetTypeChart], err = loadAssets(ctx, r.Charts, ids[models.AssetTypeChart]); err != nil {
+		return nil, err
+	}
+	if assets[models.AssetTypeInsight], err = loadAssets(ctx, r.Insights, ids[models.AssetTypeInsight]); err != nil {
+		return nil, err
+	}
+	return assets, nil
+}
+
+// loadAssets fetches the assets with the given IDs from a repository, keyed by ID
+func loadAssets[T models.Asset](ctx context.Context, repo AssetRepository[T], ids []uint) (map[uint]models.Asset, error) {
+	assets := make(map[uint]models.Asset, len(ids))
+	if len(ids) == 0 {
+		return assets, nil
+	}
+
+	rows, err := repo.GetMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		assets[row.AssetID()] = row
+	}
+	return assets, nil
+}
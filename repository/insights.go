@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"platform-go-challenge/models"
+)
+
+// insightRenderer computes the placeholders of insights as audience sizes, so
+// that they are cached like sizes when the sizes are
+type insightRenderer struct {
+	audiences AudienceRepository
+	sizes     AudienceSizeRepository
+}
+
+// NewInsightRenderer returns an InsightRenderer reading the audiences of
+// repos and sizing them with repos.AudienceSizes
+func NewInsightRenderer(repos Repositories) InsightRenderer {
+	return &insightRenderer{audiences: repos.Audiences, sizes: repos.AudienceSizes}
+}
+
+// Render renders placeholders about deleted audiences as unavailable. A text
+// stored before it had to be a valid template is returned unchanged.
+func (r *insightRenderer) Render(ctx context.Context, insight models.Insight) (string, error) {
+	template, err := models.ParseInsightTemplate(insight.Text)
+	if err != nil {
+		return insight.Text, nil
+	}
+
+	// the audiences of the placeholders, loaded once per insight
+	audiences := make(map[uint]*models.Audience)
+	return template.Render(func(placeholder models.Placeholder) (string, error) {
+		audience, ok := audiences[placeholder.AudienceID]
+		if !ok {
+			audience = &models.Audience{}
+			if placeholder.AudienceID != 0 {
+				stored, err := r.audiences.Get(ctx, placeholder.AudienceID)
+				switch {
+				case errors.Is(err, ErrNotFound):
+					audience = nil
+				case err != nil:
+					return "", err
+				default:
+					audience = &stored
+				}
+			}
+			audiences[placeholder.AudienceID] = audience
+		}
+		if audience == nil {
+			return models.PlaceholderUnavailable, nil
+		}
+		return r.value(ctx, placeholder, *audience)
+	})
+}
+
+// value computes a placeholder about the audience. Without a where clause it
+// counts the audience out of the dataset, with one it counts the part of the
+// audience matching it out of the audience.
+func (r *insightRenderer) value(ctx context.Context, placeholder models.Placeholder, audience models.Audience) (string, error) {
+	size, err := r.sizes.Size(ctx, audience)
+	if err != nil {
+		return "", err
+	}
+	if placeholder.Where == nil {
+		return placeholder.Format(size.Count, size.Base), nil
+	}
+
+	restricted, err := audience.Restrict(placeholder.Where)
+	if err != nil {
+		return "", err
+	}
+	part, err := r.sizes.Size(ctx, restricted)
+	if err != nil {
+		return "", err
+	}
+	return placeholder.Format(part.Count, size.Count), nil
+}
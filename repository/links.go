@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"platform-go-challenge/models"
+)
+
+// LoadSources returns the audiences and charts backing an asset, in the order
+// they were linked. Sources deleted concurrently are skipped.
+func (r Repositories) LoadSources(ctx context.Context, targetType models.AssetType, targetID uint) ([]models.Asset, error) {
+	links, err := r.Links.Sources(ctx, targetType, []uint{targetID})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[models.AssetType][]uint)
+	for _, link := range links {
+		ids[link.SourceType] = append(ids[link.SourceType], link.SourceID)
+	}
+	assets, err := r.loadAssetsByType(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]models.Asset, 0, len(links))
+	for _, link := range links {
+		if asset, found := assets[link.SourceType][link.SourceID]; found {
+			sources = append(sources, asset)
+		}
+	}
+	return sources, nil
+}
+
+// LoadTargets returns the assets of repo derived from an asset, in the order
+// they were linked. Targets deleted concurrently are skipped.
+func LoadTargets[T models.Asset](ctx context.Context, links LinkRepository, repo AssetRepository[T], sourceType models.AssetType, sourceID uint) ([]T, error) {
+	var target T
+	found, err := links.Targets(ctx, sourceType, []uint{sourceID}, target.AssetType())
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(found))
+	for i, link := range found {
+		ids[i] = link.TargetID
+	}
+	assets, err := loadAssets(ctx, repo, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]T, 0, len(ids))
+	for _, id := range ids {
+		if asset, found := assets[id]; found {
+			targets = append(targets, asset.(T))
+		}
+	}
+	return targets, nil
+}
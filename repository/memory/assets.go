@@ -0,0 +1,221 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+)
+
+// assetRepository is the in-memory implementation of repository.AssetRepository
+type assetRepository[T models.Asset] struct {
+	store     *Store
+	table     *table[T]
+	assetType models.AssetType
+	policy    repository.AssetDeletePolicy
+	setID     func(*T, uint)
+	// clone deep copies slices of an asset so that callers never share
+	// memory with the store, if the asset has any
+	clone func(T) T
+}
+
+// copy returns a copy of asset that shares no memory with it
+func (r *assetRepository[T]) copy(asset T) T {
+	if r.clone == nil {
+		return asset
+	}
+	return r.clone(asset)
+}
+
+func (r *assetRepository[T]) List(ctx context.Context, request models.PageRequest) (models.Page[T], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rows := page(r.table.rows, request, nil)
+	for i := range rows {
+		rows[i] = r.copy(rows[i])
+	}
+	return models.NewPage(rows, request, func(asset T) uint { return asset.AssetID() }), nil
+}
+
+func (r *assetRepository[T]) Get(ctx context.Context, id uint) (T, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	asset, found := r.table.rows[id]
+	if !found {
+		return asset, fmt.Errorf("%w: %s %d", repository.ErrNotFound, r.assetType, id)
+	}
+	return r.copy(asset), nil
+}
+
+func (r *assetRepository[T]) GetMany(ctx context.Context, ids []uint) ([]T, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	assets := make([]T, 0, len(ids))
+	for _, id := range ids {
+		if asset, found := r.table.rows[id]; found {
+			assets = append(assets, r.copy(asset))
+		}
+	}
+	return assets, nil
+}
+
+func (r *assetRepository[T]) Create(ctx context.Context, asset *T) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.table.nextID++
+	r.setID(asset, r.table.nextID)
+	r.table.rows[r.table.nextID] = r.copy(*asset)
+	return nil
+}
+
+func (r *assetRepository[T]) Update(ctx context.Context, asset *T) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.checkExists((*asset).AssetID()); err != nil {
+		return err
+	}
+	r.put(asset)
+	return nil
+}
+
+// checkExists returns repository.ErrNotFound if there is no asset with the
+// given ID. The caller must hold the lock.
+func (r *assetRepository[T]) checkExists(id uint) error {
+	if !r.table.has(id) {
+		return fmt.Errorf("%w: %s %d", repository.ErrNotFound, r.assetType, id)
+	}
+	return nil
+}
+
+// put stores an asset, assigning an ID to new ones. The caller must hold the lock.
+func (r *assetRepository[T]) put(asset *T) {
+	id := (*asset).AssetID()
+	if id == 0 {
+		r.table.nextID++
+		id = r.table.nextID
+		r.setID(asset, id)
+	}
+	if id > r.table.nextID {
+		r.table.nextID = id
+	}
+	r.table.rows[id] = r.copy(*asset)
+}
+
+// Delete deletes an asset and its links and applies the delete policy to its
+// stars. Under the restrict policy it returns repository.ErrAssetStarred and
+// leaves the asset untouched if anyone has starred it.
+func (r *assetRepository[T]) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.checkExists(id); err != nil {
+		return err
+	}
+
+	var starIDs []uint
+	for starID, star := range r.store.stars {
+		if star.Type == r.assetType && star.AssetID == id {
+			starIDs = append(starIDs, starID)
+		}
+	}
+
+	if r.policy == repository.AssetDeleteRestrict && len(starIDs) > 0 {
+		return fmt.Errorf("%w: %s %d has %d star(s)", repository.ErrAssetStarred, r.assetType, id, len(starIDs))
+	}
+	for _, starID := range starIDs {
+		r.store.deleteStar(starID)
+	}
+	r.store.deleteAssetLinks(r.assetType, id)
+
+	delete(r.table.rows, id)
+	return nil
+}
+
+// chartRepository stores charts and numbers their series
+type chartRepository struct {
+	assetRepository[models.Chart]
+}
+
+func (r *chartRepository) Create(ctx context.Context, chart *models.Chart) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	chart.ID = 0
+	setDefaultChartType(chart)
+	r.put(chart)
+	r.numberSeries(chart)
+	return nil
+}
+
+// Update replaces the chart together with all of its series
+func (r *chartRepository) Update(ctx context.Context, chart *models.Chart) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.checkExists(chart.ID); err != nil {
+		return err
+	}
+	setDefaultChartType(chart)
+	r.put(chart)
+	r.numberSeries(chart)
+	return nil
+}
+
+// numberSeries assigns new IDs to the series of a stored chart, as the
+// database backend does when it recreates them. The caller must hold the lock.
+func (r *chartRepository) numberSeries(chart *models.Chart) {
+	for i := range chart.Series {
+		r.store.nextSeriesID++
+		chart.Series[i].ID = r.store.nextSeriesID
+		chart.Series[i].ChartID = chart.ID
+	}
+	r.table.rows[chart.ID] = cloneChart(*chart)
+}
+
+// setDefaultChartType stores a chart without a type as a bar chart, as the
+// database backend does
+func setDefaultChartType(chart *models.Chart) {
+	if chart.ChartType == "" {
+		chart.ChartType = models.ChartTypeBar
+	}
+}
+
+// cloneAudience deep copies the criteria of an audience
+func cloneAudience(audience models.Audience) models.Audience {
+	audience.Criteria = audience.Criteria.Clone()
+	return audience
+}
+
+// cloneChart deep copies the labels, series, configuration and provenance of
+// a chart
+func cloneChart(chart models.Chart) models.Chart {
+	chart.Labels = slices.Clone(chart.Labels)
+	if chart.Config.Stacks != nil {
+		stacks := make([]models.ChartStack, len(chart.Config.Stacks))
+		for i, stack := range chart.Config.Stacks {
+			stack.Series = slices.Clone(stack.Series)
+			stacks[i] = stack
+		}
+		chart.Config.Stacks = stacks
+	}
+	if chart.Provenance != nil {
+		provenance := *chart.Provenance
+		chart.Provenance = &provenance
+	}
+
+	// Series are always loaded with the chart, so a chart without series has an empty list
+	series := make([]models.ChartSeries, len(chart.Series))
+	for i, s := range chart.Series {
+		s.Points = slices.Clone(s.Points)
+		series[i] = s
+	}
+	chart.Series = series
+	return chart
+}
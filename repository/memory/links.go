@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+)
+
+// linkRepository is the in-memory implementation of repository.LinkRepository
+type linkRepository struct {
+	store *Store
+}
+
+// linkKey identifies a link by its ends, mirroring the idx_asset_link unique
+// index of the database backend
+func linkKey(link models.AssetLink) models.AssetLink {
+	link.ID = 0
+	return link
+}
+
+func (r *linkRepository) Link(ctx context.Context, link models.AssetLink) (bool, error) {
+	if err := repository.CheckLink(link); err != nil {
+		return false, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.checkStarTarget(link.SourceType, link.SourceID); err != nil {
+		return false, err
+	}
+	if err := r.store.checkStarTarget(link.TargetType, link.TargetID); err != nil {
+		return false, err
+	}
+	key := linkKey(link)
+	if _, found := r.store.linkIndex[key]; found {
+		return false, nil
+	}
+
+	r.store.nextLinkID++
+	link.ID = r.store.nextLinkID
+	r.store.links[link.ID] = link
+	r.store.linkIndex[key] = link.ID
+	return true, nil
+}
+
+func (r *linkRepository) Unlink(ctx context.Context, link models.AssetLink) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	linkID, found := r.store.linkIndex[linkKey(link)]
+	if !found {
+		return false, nil
+	}
+	r.store.deleteLink(linkID)
+	return true, nil
+}
+
+func (r *linkRepository) Sources(ctx context.Context, targetType models.AssetType, targetIDs []uint) ([]models.AssetLink, error) {
+	return r.all(func(link models.AssetLink) bool {
+		return link.TargetType == targetType && slices.Contains(targetIDs, link.TargetID)
+	}), nil
+}
+
+func (r *linkRepository) Targets(ctx context.Context, sourceType models.AssetType, sourceIDs []uint, targetType models.AssetType) ([]models.AssetLink, error) {
+	return r.all(func(link models.AssetLink) bool {
+		return link.SourceType == sourceType && link.TargetType == targetType && slices.Contains(sourceIDs, link.SourceID)
+	}), nil
+}
+
+// all returns the links kept by keep in ID order
+func (r *linkRepository) all(keep func(models.AssetLink) bool) []models.AssetLink {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return page(r.store.links, models.PageRequest{Limit: len(r.store.links)}, keep)
+}
+
+// deleteLink removes a link. The caller must hold the write lock.
+func (s *Store) deleteLink(id uint) {
+	link, found := s.links[id]
+	if !found {
+		return
+	}
+	delete(s.links, id)
+	delete(s.linkIndex, linkKey(link))
+}
+
+// deleteAssetLinks removes the links from and to an asset. The caller must
+// hold the write lock.
+func (s *Store) deleteAssetLinks(assetType models.AssetType, assetID uint) {
+	for id, link := range s.links {
+		if (link.SourceType == assetType && link.SourceID == assetID) ||
+			(link.TargetType == assetType && link.TargetID == assetID) {
+			s.deleteLink(id)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"platform-go-challenge/models"
+)
+
+// respondentRepository implements repository.RespondentRepository on the store
+type respondentRepository struct {
+	store *Store
+}
+
+func (r *respondentRepository) Replace(ctx context.Context, respondents []models.Respondent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i := range respondents {
+		r.store.nextRespondentID++
+		respondents[i].ID = r.store.nextRespondentID
+	}
+	r.store.respondents = slices.Clone(respondents)
+	r.store.datasetVersion++
+	return nil
+}
+
+func (r *respondentRepository) Count(ctx context.Context, expression models.Expression) (int64, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	total := int64(len(r.store.respondents))
+	if expression == nil {
+		return total, total, nil
+	}
+	var matched int64
+	for _, respondent := range r.store.respondents {
+		if expression.Matches(respondent.Profile()) {
+			matched++
+		}
+	}
+	return matched, total, nil
+}
+
+func (r *respondentRepository) CountOverlaps(ctx context.Context, expressions []models.Expression) ([][]int64, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counter := models.NewOverlapCounter(expressions)
+	for _, respondent := range r.store.respondents {
+		counter.Add(respondent.Profile())
+	}
+	counts, total := counter.Counts()
+	return counts, total, nil
+}
+
+func (r *respondentRepository) Breakdown(ctx context.Context, expression models.Expression, dimension models.Dimension) (map[string]int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counter := models.NewBreakdownCounter(expression, dimension)
+	for _, respondent := range r.store.respondents {
+		counter.Add(respondent.Profile())
+	}
+	return counter.Counts(), nil
+}
+
+func (r *respondentRepository) Version(ctx context.Context) (string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return strconv.FormatUint(uint64(r.store.datasetVersion), 10), nil
+}
@@ -0,0 +1,190 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+)
+
+// starRepository is the in-memory implementation of repository.StarRepository
+type starRepository struct {
+	store *Store
+}
+
+func (r *starRepository) List(ctx context.Context, request models.PageRequest) (models.Page[models.UserStar], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rows := page(r.store.stars, request, nil)
+	return models.NewPage(rows, request, func(star models.UserStar) uint { return star.ID }), nil
+}
+
+func (r *starRepository) ListByUser(ctx context.Context, userID uint, request models.PageRequest) (models.Page[models.UserStar], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rows := page(r.store.stars, request, func(star models.UserStar) bool { return star.UserID == userID })
+	return models.NewPage(rows, request, func(star models.UserStar) uint { return star.ID }), nil
+}
+
+func (r *starRepository) AllByUser(ctx context.Context, userID uint) ([]models.UserStar, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	request := models.PageRequest{Limit: len(r.store.stars)}
+	return page(r.store.stars, request, func(star models.UserStar) bool { return star.UserID == userID }), nil
+}
+
+func (r *starRepository) AllByAsset(ctx context.Context, assetType models.AssetType, assetID uint) ([]models.UserStar, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	request := models.PageRequest{Limit: len(r.store.stars)}
+	return page(r.store.stars, request, func(star models.UserStar) bool {
+		return star.Type == assetType && star.AssetID == assetID
+	}), nil
+}
+
+func (r *starRepository) Get(ctx context.Context, id uint) (models.UserStar, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	star, found := r.store.stars[id]
+	if !found {
+		return star, fmt.Errorf("%w: userstar %d", repository.ErrNotFound, id)
+	}
+	return star, nil
+}
+
+func (r *starRepository) Create(ctx context.Context, star *models.UserStar) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	star.ID = 0
+	return r.store.putStar(star)
+}
+
+func (r *starRepository) Update(ctx context.Context, star *models.UserStar) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, found := r.store.stars[star.ID]; !found {
+		return fmt.Errorf("%w: userstar %d", repository.ErrNotFound, star.ID)
+	}
+	return r.store.putStar(star)
+}
+
+func (r *starRepository) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, found := r.store.stars[id]; !found {
+		return fmt.Errorf("%w: userstar %d", repository.ErrNotFound, id)
+	}
+	r.store.deleteStar(id)
+	return nil
+}
+
+func (r *starRepository) Star(ctx context.Context, userID uint, assetType models.AssetType, assetID uint) (models.UserStar, bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.checkStarTarget(assetType, assetID); err != nil {
+		return models.UserStar{}, false, err
+	}
+	if starID, found := r.store.starIndex[starKey{userID, assetType, assetID}]; found {
+		return r.store.stars[starID], false, nil
+	}
+
+	star := models.UserStar{UserID: userID, Type: assetType, AssetID: assetID}
+	if err := r.store.putStar(&star); err != nil {
+		return models.UserStar{}, false, err
+	}
+	return star, true, nil
+}
+
+func (r *starRepository) Unstar(ctx context.Context, userID uint, assetType models.AssetType, assetID uint) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	starID, found := r.store.starIndex[starKey{userID, assetType, assetID}]
+	if !found {
+		return false, nil
+	}
+	r.store.deleteStar(starID)
+	return true, nil
+}
+
+func (r *starRepository) UpdateDescription(ctx context.Context, userID uint, assetType models.AssetType, assetID uint, description string) (models.UserStar, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	starID, found := r.store.starIndex[starKey{userID, assetType, assetID}]
+	if !found {
+		return models.UserStar{}, repository.ErrStarNotFound
+	}
+
+	star := r.store.stars[starID]
+	star.Description = description
+	r.store.stars[starID] = star
+	return star, nil
+}
+
+// checkStarTarget verifies that a star, or either end of a link, points to a
+// valid asset type and an existing asset. The caller must hold the lock.
+func (s *Store) checkStarTarget(assetType models.AssetType, assetID uint) error {
+	if err := repository.CheckAssetType(assetType); err != nil {
+		return err
+	}
+	if !s.assetExists(assetType, assetID) {
+		return fmt.Errorf("%w: %s %d", repository.ErrAssetNotFound, assetType, assetID)
+	}
+	return nil
+}
+
+// putStar checks and stores a star, assigning an ID and creation time to new
+// ones. The caller must hold the write lock.
+func (s *Store) putStar(star *models.UserStar) error {
+	if err := s.checkStarTarget(star.Type, star.AssetID); err != nil {
+		return err
+	}
+
+	key := starKey{star.UserID, star.Type, star.AssetID}
+	if existing, found := s.starIndex[key]; found && existing != star.ID {
+		return repository.ErrDuplicateStar
+	}
+
+	if previous, found := s.stars[star.ID]; found && star.ID != 0 {
+		delete(s.starIndex, starKey{previous.UserID, previous.Type, previous.AssetID})
+		if star.CreatedAt.IsZero() {
+			star.CreatedAt = previous.CreatedAt
+		}
+	}
+	if star.ID == 0 {
+		s.nextStarID++
+		star.ID = s.nextStarID
+	}
+	if star.ID > s.nextStarID {
+		s.nextStarID = star.ID
+	}
+	if star.CreatedAt.IsZero() {
+		star.CreatedAt = time.Now()
+	}
+
+	s.stars[star.ID] = *star
+	s.starIndex[key] = star.ID
+	return nil
+}
+
+// deleteStar removes a star, if present. The caller must hold the write lock.
+func (s *Store) deleteStar(id uint) {
+	star, found := s.stars[id]
+	if !found {
+		return
+	}
+	delete(s.stars, id)
+	delete(s.starIndex, starKey{star.UserID, star.Type, star.AssetID})
+}
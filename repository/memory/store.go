@@ -0,0 +1,166 @@
+// Package memory implements the repositories in memory. It is meant for
+// tests and local runs: all data is lost when the process exits.
+package memory
+
+import (
+	"slices"
+	"sync"
+
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+)
+
+// starKey identifies the star of a user on an asset, mirroring the
+// idx_user_star_asset unique index of the database backend
+type starKey struct {
+	userID    uint
+	assetType models.AssetType
+	assetID   uint
+}
+
+// Store holds all records behind a single lock, so that star and link checks
+// and the asset delete policy always see a consistent state
+type Store struct {
+	mu sync.RWMutex
+
+	audiences *table[models.Audience]
+	charts    *table[models.Chart]
+	insights  *table[models.Insight]
+
+	stars      map[uint]models.UserStar
+	starIndex  map[starKey]uint
+	nextStarID uint
+
+	links      map[uint]models.AssetLink
+	linkIndex  map[models.AssetLink]uint
+	nextLinkID uint
+
+	nextSeriesID uint
+
+	respondents      []models.Respondent
+	nextRespondentID uint
+	// datasetVersion counts the changes of the respondents dataset
+	datasetVersion uint
+}
+
+// NewStore returns an empty store
+func NewStore() *Store {
+	return &Store{
+		audiences: newTable[models.Audience](),
+		charts:    newTable[models.Chart](),
+		insights:  newTable[models.Insight](),
+		stars:     make(map[uint]models.UserStar),
+		starIndex: make(map[starKey]uint),
+		links:     make(map[uint]models.AssetLink),
+		linkIndex: make(map[models.AssetLink]uint),
+	}
+}
+
+// Reset removes all records from the store. Repositories created before
+// keep working on the emptied store.
+func (s *Store) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.audiences.reset()
+	s.charts.reset()
+	s.insights.reset()
+	clear(s.stars)
+	clear(s.starIndex)
+	clear(s.links)
+	clear(s.linkIndex)
+	s.nextStarID, s.nextLinkID, s.nextSeriesID = 0, 0, 0
+	s.respondents, s.nextRespondentID = nil, 0
+	// the version keeps increasing, so that cached sizes are not reused
+	s.datasetVersion++
+}
+
+// NewRepositories returns repositories backed by a new empty store. Deleted
+// assets are handled according to policy.
+func NewRepositories(policy repository.AssetDeletePolicy) repository.Repositories {
+	return NewStore().Repositories(policy)
+}
+
+// Repositories returns repositories backed by the store. Deleted assets are
+// handled according to policy.
+func (s *Store) Repositories(policy repository.AssetDeletePolicy) repository.Repositories {
+	repos := repository.Repositories{
+		Charts: &chartRepository{assetRepository[models.Chart]{
+			store: s, table: s.charts, assetType: models.AssetTypeChart, policy: policy,
+			setID: func(chart *models.Chart, id uint) { chart.ID = id },
+			clone: cloneChart,
+		}},
+		Insights: &assetRepository[models.Insight]{
+			store: s, table: s.insights, assetType: models.AssetTypeInsight, policy: policy,
+			setID: func(insight *models.Insight, id uint) { insight.ID = id },
+		},
+		Audiences: &assetRepository[models.Audience]{
+			store: s, table: s.audiences, assetType: models.AssetTypeAudience, policy: policy,
+			setID: func(audience *models.Audience, id uint) { audience.ID = id },
+			clone: cloneAudience,
+		},
+		Stars:       &starRepository{store: s},
+		Links:       &linkRepository{store: s},
+		Respondents: &respondentRepository{store: s},
+	}
+	repos.Favourites = repository.NewFavouriteRepository(repos)
+	repos.AudienceSizes = repository.NewAudienceSizeRepository(repos.Respondents)
+	repos.ChartGenerator = repository.NewChartGenerator(repos)
+	repos.InsightRenderer = repository.NewInsightRenderer(repos)
+	return repos
+}
+
+// assetExists reports whether the asset of the given type and ID is present.
+// The caller must hold the lock.
+func (s *Store) assetExists(assetType models.AssetType, assetID uint) bool {
+	switch assetType {
+	case models.AssetTypeAudience:
+		return s.audiences.has(assetID)
+	case models.AssetTypeChart:
+		return s.charts.has(assetID)
+	case models.AssetTypeInsight:
+		return s.insights.has(assetID)
+	}
+	return false
+}
+
+// table holds the rows of one asset type keyed by ID
+type table[T models.Asset] struct {
+	rows   map[uint]T
+	nextID uint
+}
+
+func newTable[T models.Asset]() *table[T] {
+	return &table[T]{rows: make(map[uint]T)}
+}
+
+func (t *table[T]) reset() {
+	clear(t.rows)
+	t.nextID = 0
+}
+
+func (t *table[T]) has(id uint) bool {
+	_, found := t.rows[id]
+	return found
+}
+
+// page returns the rows selected by page in ID order, with one row more than
+// the limit so that models.NewPage can tell whether there is a next page
+func page[T any](rows map[uint]T, request models.PageRequest, keep func(T) bool) []T {
+	ids := make([]uint, 0, len(rows))
+	for id, row := range rows {
+		if id > request.After && (keep == nil || keep(row)) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	if len(ids) > request.Limit+1 {
+		ids = ids[:request.Limit+1]
+	}
+
+	result := make([]T, len(ids))
+	for i, id := range ids {
+		result[i] = rows[id]
+	}
+	return result
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+)
+
+// AssetDeletePolicy decides what happens to the stars of an asset when the asset is deleted
+type AssetDeletePolicy string
+
+const (
+	// AssetDeleteCascade removes the stars together with the asset
+	AssetDeleteCascade AssetDeletePolicy = "cascade"
+	// AssetDeleteRestrict refuses to delete an asset that is still starred
+	AssetDeleteRestrict AssetDeletePolicy = "restrict"
+)
+
+// ParseAssetDeletePolicy converts a configuration value into an AssetDeletePolicy
+func ParseAssetDeletePolicy(value string) (AssetDeletePolicy, error) {
+	switch policy := AssetDeletePolicy(value); policy {
+	case AssetDeleteCascade, AssetDeleteRestrict:
+		return policy, nil
+	}
+	return "", fmt.Errorf("invalid asset delete policy %q: must be %q or %q", value, AssetDeleteCascade, AssetDeleteRestrict)
+}
+
+// AssetDeletePolicyFromEnv reads the policy from ASSET_DELETE_POLICY,
+// defaulting to AssetDeleteCascade when it is not set
+func AssetDeletePolicyFromEnv() (AssetDeletePolicy, error) {
+	value, isSet := os.LookupEnv("ASSET_DELETE_POLICY")
+	if !isSet {
+		return AssetDeleteCascade, nil
+	}
+	return ParseAssetDeletePolicy(value)
+}
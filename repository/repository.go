@@ -0,0 +1,166 @@
+// Package repository defines the storage interfaces shared by the REST
+// handlers and the GraphQL resolvers. Each storage backend implements them.
+package repository
+
+import (
+	"context"
+
+	"platform-go-challenge/models"
+)
+
+// AssetRepository stores one kind of starrable asset
+type AssetRepository[T models.Asset] interface {
+	// List returns a page of assets in ID order
+	List(ctx context.Context, page models.PageRequest) (models.Page[T], error)
+	// Get returns the asset with the given ID or ErrNotFound
+	Get(ctx context.Context, id uint) (T, error)
+	// GetMany returns the assets with the given IDs, skipping missing ones
+	GetMany(ctx context.Context, ids []uint) ([]T, error)
+	// Create stores a new asset and sets its ID
+	Create(ctx context.Context, asset *T) error
+	// Update stores all fields of an existing asset. It returns ErrNotFound
+	// if the asset does not exist.
+	Update(ctx context.Context, asset *T) error
+	// Delete removes an asset and its links and applies the
+	// AssetDeletePolicy to its stars. It returns ErrNotFound if the asset
+	// does not exist.
+	Delete(ctx context.Context, id uint) error
+}
+
+// ChartRepository stores charts together with their series
+type ChartRepository interface {
+	AssetRepository[models.Chart]
+}
+
+// InsightRepository stores insights
+type InsightRepository interface {
+	AssetRepository[models.Insight]
+}
+
+// AudienceRepository stores audiences
+type AudienceRepository interface {
+	AssetRepository[models.Audience]
+}
+
+// StarRepository stores the stars users put on assets. Writes check that the
+// starred asset exists and that a user stars an asset at most once.
+type StarRepository interface {
+	// List returns a page of all stars in ID order
+	List(ctx context.Context, page models.PageRequest) (models.Page[models.UserStar], error)
+	// ListByUser returns a page of the stars of a user in the order they were starred
+	ListByUser(ctx context.Context, userID uint, page models.PageRequest) (models.Page[models.UserStar], error)
+	// AllByUser returns every star of a user in the order they were starred
+	AllByUser(ctx context.Context, userID uint) ([]models.UserStar, error)
+	// AllByAsset returns every star on an asset in the order they were starred
+	AllByAsset(ctx context.Context, assetType models.AssetType, assetID uint) ([]models.UserStar, error)
+	// Get returns the star with the given ID or ErrNotFound
+	Get(ctx context.Context, id uint) (models.UserStar, error)
+	// Create stores a new star. It returns ErrInvalidAssetType, ErrAssetNotFound
+	// or ErrDuplicateStar if the star cannot be stored.
+	Create(ctx context.Context, star *models.UserStar) error
+	// Update stores all fields of an existing star, with the same checks as
+	// Create. It returns ErrNotFound if the star does not exist.
+	Update(ctx context.Context, star *models.UserStar) error
+	// Delete removes the star with the given ID or returns ErrNotFound
+	Delete(ctx context.Context, id uint) error
+
+	// Star stars an asset for a user. Starring an asset that is already
+	// starred is not an error: the existing star is returned and created is false.
+	Star(ctx context.Context, userID uint, assetType models.AssetType, assetID uint) (star models.UserStar, created bool, err error)
+	// Unstar removes the star of a user on an asset, if any. It reports whether
+	// a star was removed; unstarring an asset that is not starred is not an error.
+	Unstar(ctx context.Context, userID uint, assetType models.AssetType, assetID uint) (bool, error)
+	// UpdateDescription sets the user's description on a starred asset. It
+	// returns ErrStarNotFound if the user has not starred the asset.
+	UpdateDescription(ctx context.Context, userID uint, assetType models.AssetType, assetID uint, description string) (models.UserStar, error)
+}
+
+// LinkRepository stores the links from audiences and charts to the charts
+// and insights derived from them. Writes check that both assets exist, and
+// deleting an asset removes its links.
+type LinkRepository interface {
+	// Link links two assets. It returns a validation error if the source
+	// cannot back the target and ErrInvalidAssetType or ErrAssetNotFound if
+	// either asset cannot be linked. Linking assets that are already linked
+	// is not an error and created is false.
+	Link(ctx context.Context, link models.AssetLink) (created bool, err error)
+	// Unlink removes a link, if any. It reports whether a link was removed;
+	// unlinking assets that are not linked is not an error.
+	Unlink(ctx context.Context, link models.AssetLink) (bool, error)
+	// Sources returns the links to the targets of the given type and IDs, in
+	// the order they were linked
+	Sources(ctx context.Context, targetType models.AssetType, targetIDs []uint) ([]models.AssetLink, error)
+	// Targets returns the links from the sources of the given type and IDs to
+	// targets of targetType, in the order they were linked
+	Targets(ctx context.Context, sourceType models.AssetType, sourceIDs []uint, targetType models.AssetType) ([]models.AssetLink, error)
+}
+
+// FavouriteRepository reads the favourites of users: their stars together
+// with the starred assets, in the order they were starred. Stars whose asset
+// no longer exists are skipped.
+type FavouriteRepository interface {
+	// ListByUser returns a page of the favourites of a user
+	ListByUser(ctx context.Context, userID uint, page models.PageRequest) (models.Page[models.Favourite], error)
+	// AllByUser returns every favourite of a user
+	AllByUser(ctx context.Context, userID uint) ([]models.Favourite, error)
+}
+
+// RespondentRepository stores the dataset of respondents that audiences are
+// sized against
+type RespondentRepository interface {
+	// Replace replaces the whole dataset with respondents and sets their IDs
+	Replace(ctx context.Context, respondents []models.Respondent) error
+	// Count returns the number of respondents matching expression, where nil
+	// matches everyone, and the number of respondents in the dataset
+	Count(ctx context.Context, expression models.Expression) (matched, total int64, err error)
+	// CountOverlaps returns the number of respondents matching both
+	// expressions i and j in counts[i][j], where nil matches everyone, and the
+	// number of respondents in the dataset
+	CountOverlaps(ctx context.Context, expressions []models.Expression) (counts [][]int64, total int64, err error)
+	// Breakdown returns the number of respondents matching expression, where
+	// nil matches everyone, by their value of dimension
+	Breakdown(ctx context.Context, expression models.Expression, dimension models.Dimension) (map[string]int64, error)
+	// Version identifies the current dataset. It changes whenever the dataset
+	// is replaced.
+	Version(ctx context.Context) (string, error)
+}
+
+// AudienceSizeRepository sizes audiences against the respondents
+type AudienceSizeRepository interface {
+	// Size returns the number of respondents matching the criteria and the
+	// expression of audience
+	Size(ctx context.Context, audience models.Audience) (models.AudienceSize, error)
+	// Compare returns the overlap of every pair of audiences
+	Compare(ctx context.Context, audiences []models.Audience) (models.AudienceComparison, error)
+}
+
+// ChartGenerator generates charts from audiences and the respondents
+type ChartGenerator interface {
+	// Generate stores a chart breaking the audience down by dimension,
+	// linked to the audience
+	Generate(ctx context.Context, audienceID uint, dimension models.Dimension) (models.Chart, error)
+	// Refresh generates the series of a generated chart again from the
+	// current audience and respondents
+	Refresh(ctx context.Context, chartID uint) (models.Chart, error)
+}
+
+// InsightRenderer renders the placeholders of insight templates
+type InsightRenderer interface {
+	// Render returns the text of the insight with its placeholders replaced
+	// by numbers computed from the current respondents
+	Render(ctx context.Context, insight models.Insight) (string, error)
+}
+
+// Repositories bundles the repositories of one storage backend
+type Repositories struct {
+	Charts          ChartRepository
+	Insights        InsightRepository
+	Audiences       AudienceRepository
+	Stars           StarRepository
+	Links           LinkRepository
+	Favourites      FavouriteRepository
+	Respondents     RespondentRepository
+	AudienceSizes   AudienceSizeRepository
+	ChartGenerator  ChartGenerator
+	InsightRenderer InsightRenderer
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"platform-go-challenge/models"
+)
+
+// audienceSizeRepository sizes audiences by counting the matching respondents
+type audienceSizeRepository struct {
+	respondents RespondentRepository
+}
+
+// NewAudienceSizeRepository returns an AudienceSizeRepository counting the
+// respondents of respondents
+func NewAudienceSizeRepository(respondents RespondentRepository) AudienceSizeRepository {
+	return &audienceSizeRepository{respondents: respondents}
+}
+
+// Size reads the dataset version before counting, so that a size computed
+// while the dataset is replaced is attributed to the older dataset
+func (r *audienceSizeRepository) Size(ctx context.Context, audience models.Audience) (models.AudienceSize, error) {
+	definition, err := audience.Definition()
+	if err != nil {
+		return models.AudienceSize{}, err
+	}
+	audienceVersion, err := audience.Version()
+	if err != nil {
+		return models.AudienceSize{}, err
+	}
+	datasetVersion, err := r.respondents.Version(ctx)
+	if err != nil {
+		return models.AudienceSize{}, err
+	}
+
+	count, base, err := r.respondents.Count(ctx, definition)
+	if err != nil {
+		return models.AudienceSize{}, err
+	}
+	size := models.NewAudienceSize(audience.ID, count, base)
+	size.AudienceVersion, size.DatasetVersion = audienceVersion, datasetVersion
+	return size, nil
+}
+
+// Compare counts the overlaps of the audiences in a single pass over the
+// respondents
+func (r *audienceSizeRepository) Compare(ctx context.Context, audiences []models.Audience) (models.AudienceComparison, error) {
+	ids := make([]uint, len(audiences))
+	versions := make([]string, len(audiences))
+	definitions := make([]models.Expression, len(audiences))
+	for i, audience := range audiences {
+		var err error
+		if definitions[i], err = audience.Definition(); err != nil {
+			return models.AudienceComparison{}, err
+		}
+		if versions[i], err = audience.Version(); err != nil {
+			return models.AudienceComparison{}, err
+		}
+		ids[i] = audience.ID
+	}
+	datasetVersion, err := r.respondents.Version(ctx)
+	if err != nil {
+		return models.AudienceComparison{}, err
+	}
+
+	counts, base, err := r.respondents.CountOverlaps(ctx, definitions)
+	if err != nil {
+		return models.AudienceComparison{}, err
+	}
+	comparison := models.NewAudienceComparison(ids, counts, base)
+	comparison.DatasetVersion = datasetVersion
+	for i := range comparison.Audiences {
+		comparison.Audiences[i].AudienceVersion = versions[i]
+		comparison.Audiences[i].DatasetVersion = datasetVersion
+	}
+	return comparison, nil
+}
@@ -0,0 +1,204 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"platform-go-challenge/models"
+)
+
+// TestAsset_FavouritesQuery tests that favourites come back as one list of assets in star order
+func TestAsset_FavouritesQuery(t *testing.T) {
+	CleanupTestData()
+
+	audienceID, chartID, insightID := SeedTestData(t)
+
+	// Star in an order that differs from the asset type grouping
+	Seed(t, &models.UserStar{UserID: 5, Type: models.AssetTypeInsight, AssetID: insightID, Description: "Read later"})
+	Seed(t, &models.UserStar{UserID: 5, Type: models.AssetTypeChart, AssetID: chartID})
+	Seed(t, &models.UserStar{UserID: 5, Type: models.AssetTypeAudience, AssetID: audienceID})
+	Seed(t, &models.UserStar{UserID: 6, Type: models.AssetTypeChart, AssetID: chartID})
+
+	resp := ExecuteGraphQL(t, `
+		query Favourites($userID: ID!) {
+			favourites(userID: $userID) {
+				edges {
+					node {
+						__typename
+						id
+						type
+						description
+						starredAt
+						... on Chart { title }
+						... on Insight { text }
+						... on Audience { criteria { genders } }
+					}
+				}
+			}
+		}
+	`, map[string]interface{}{"userID": "5"})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+
+	type favourite struct {
+		Typename    string     `json:"__typename"`
+		Type        string     `json:"type"`
+		Description *string    `json:"description"`
+		StarredAt   *time.Time `json:"starredAt"`
+		Title       string     `json:"title"`
+		Text        string     `json:"text"`
+		Criteria    struct {
+			Genders []string `json:"genders"`
+		} `json:"criteria"`
+	}
+	var result struct {
+		Favourites struct {
+			Edges []struct {
+				Node favourite `json:"node"`
+			} `json:"edges"`
+		} `json:"favourites"`
+	}
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	if len(result.Favourites.Edges) != 3 {
+		t.Fatalf("expected 3 favourites, got %d", len(result.Favourites.Edges))
+	}
+	favourites := make([]favourite, len(result.Favourites.Edges))
+	for i, edge := range result.Favourites.Edges {
+		favourites[i] = edge.Node
+	}
+
+	wantTypes := []string{"Insight", "Chart", "Audience"}
+	for i, favourite := range favourites {
+		if favourite.Typename != wantTypes[i] || favourite.Type != wantTypes[i] {
+			t.Errorf("favourite %d: expected %s, got __typename %s and type %s", i, wantTypes[i], favourite.Typename, favourite.Type)
+		}
+		if favourite.StarredAt == nil {
+			t.Errorf("favourite %d: expected starredAt to be set", i)
+		}
+	}
+
+	if d := favourites[0].Description; d == nil || *d != "Read later" {
+		t.Errorf("expected insight description %q, got %v", "Read later", d)
+	}
+	if favourites[0].Text != "Revenue increased by 20% this quarter" {
+		t.Errorf("expected insight text, got %q", favourites[0].Text)
+	}
+	if favourites[1].Title != "Sales Chart" {
+		t.Errorf("expected chart title, got %q", favourites[1].Title)
+	}
+	if genders := favourites[2].Criteria.Genders; len(genders) != 1 || genders[0] != "Male" {
+		t.Errorf("expected audience genders [Male], got %v", genders)
+	}
+}
+
+// TestAsset_UnstarredFields tests that star fields are null on assets not loaded through a star
+func TestAsset_UnstarredFields(t *testing.T) {
+	CleanupTestData()
+
+	_, chartID, _ := SeedTestData(t)
+	Seed(t, &models.UserStar{UserID: 5, Type: models.AssetTypeChart, AssetID: chartID, Description: "Mine"})
+
+	resp := ExecuteGraphQL(t, `
+		query Chart($id: ID!) {
+			chart(id: $id) { type description starredAt }
+		}
+	`, map[string]interface{}{"id": fmt.Sprintf("%d", chartID)})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+
+	var result struct {
+		Chart struct {
+			Type        string  `json:"type"`
+			Description *string `json:"description"`
+			StarredAt   *string `json:"starredAt"`
+		} `json:"chart"`
+	}
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if result.Chart.Type != "Chart" {
+		t.Errorf("expected type Chart, got %q", result.Chart.Type)
+	}
+	if result.Chart.Description != nil || result.Chart.StarredAt != nil {
+		t.Errorf("expected null star fields, got %+v", result.Chart)
+	}
+}
+
+// TestAsset_FavouritesInvalidUser tests that a malformed user ID is rejected
+func TestAsset_FavouritesInvalidUser(t *testing.T) {
+	resp := ExecuteGraphQL(t, `query { favourites(userID: "abc") { edges { cursor } } }`, nil)
+	if len(resp.Errors) == 0 {
+		t.Errorf("expected error for invalid user ID, got none")
+	}
+}
+
+// TestAsset_FavouritesPagination tests paging through favourites with the
+// first and after arguments
+func TestAsset_FavouritesPagination(t *testing.T) {
+	CleanupTestData()
+
+	audienceID, chartID, insightID := SeedTestData(t)
+	Seed(t, &models.UserStar{UserID: 5, Type: models.AssetTypeInsight, AssetID: insightID})
+	Seed(t, &models.UserStar{UserID: 5, Type: models.AssetTypeChart, AssetID: chartID})
+	Seed(t, &models.UserStar{UserID: 5, Type: models.AssetTypeAudience, AssetID: audienceID})
+
+	query := `
+		query Favourites($userID: ID!, $first: Int, $after: String) {
+			favourites(userID: $userID, first: $first, after: $after) {
+				edges { cursor node { type } }
+				pageInfo { hasNextPage endCursor }
+			}
+		}
+	`
+	type page struct {
+		Favourites struct {
+			Edges []struct {
+				Cursor string `json:"cursor"`
+				Node   struct {
+					Type string `json:"type"`
+				} `json:"node"`
+			} `json:"edges"`
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+		} `json:"favourites"`
+	}
+
+	var types []string
+	variables := map[string]interface{}{"userID": "5", "first": 2}
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatalf("pagination did not terminate")
+		}
+		resp := ExecuteGraphQL(t, query, variables)
+		if len(resp.Errors) > 0 {
+			t.Fatalf("expected no errors, got: %v", resp.Errors)
+		}
+		var result page
+		if err := json.Unmarshal(resp.Data, &result); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(result.Favourites.Edges) > 2 {
+			t.Fatalf("expected at most 2 favourites, got %d", len(result.Favourites.Edges))
+		}
+		for _, edge := range result.Favourites.Edges {
+			types = append(types, edge.Node.Type)
+		}
+		if !result.Favourites.PageInfo.HasNextPage {
+			break
+		}
+		variables["after"] = result.Favourites.PageInfo.EndCursor
+	}
+
+	if fmt.Sprint(types) != fmt.Sprint([]string{"Insight", "Chart", "Audience"}) {
+		t.Errorf("expected favourites in star order, got %v", types)
+	}
+}
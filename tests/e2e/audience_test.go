@@ -0,0 +1,233 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"platform-go-challenge/models"
+	"testing"
+)
+
+// TestAudience_RESTExpression tests that REST stores audience expressions in canonical form
+func TestAudience_RESTExpression(t *testing.T) {
+	CleanupTestData()
+
+	status, resp := ExecuteREST(t, http.MethodPost, "/audience", map[string]any{
+		"criteria":   map[string]any{"genders": []string{"Male"}},
+		"expression": "(age_group in [25-34,35-44]) and social_hours>3 and not birth_country=us",
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %v", status, resp["message"])
+	}
+	audienceID := uint(resp["data"].(map[string]any)["id"].(float64))
+
+	want := "age_group IN [25-34, 35-44] AND social_hours > 3 AND NOT birth_country = US"
+	_, resp = ExecuteREST(t, http.MethodGet, fmt.Sprintf("/audience/%d", audienceID), nil)
+	if got := resp["data"].(map[string]any)["expression"]; got != want {
+		t.Errorf("expected expression %q, got %v", want, got)
+	}
+
+	status, resp = ExecuteREST(t, http.MethodPut, fmt.Sprintf("/audience/%d", audienceID), map[string]any{"expression": "social_hours >"})
+	if status != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", status)
+	}
+	if fields := fieldNames(t, resp["errors"]); len(fields) != 1 || fields[0] != "expression" {
+		t.Errorf("expected an invalid expression, got %v", resp["errors"])
+	}
+}
+
+// TestAudience_GraphQLSummary tests the canonical expression and the summary of audiences in GraphQL
+func TestAudience_GraphQLSummary(t *testing.T) {
+	CleanupTestData()
+
+	resp := ExecuteGraphQL(t, `
+		mutation {
+			createAudience(input: {
+				criteria: {genders: ["Male"], dailyhours: {operator: GT, value: 3}}
+				expression: "age_group = 25-34 OR age_group = 35-44"
+			}) { id expression summary }
+		}
+	`, nil)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+
+	var created struct {
+		CreateAudience struct {
+			ID         string `json:"id"`
+			Expression string `json:"expression"`
+			Summary    string `json:"summary"`
+		} `json:"createAudience"`
+	}
+	if err := json.Unmarshal(resp.Data, &created); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	want := "People who are Male, spend more than 3 hours a day on social media and (are aged 25-34 or are aged 35-44)"
+	if created.CreateAudience.Summary != want {
+		t.Errorf("expected summary %q, got %q", want, created.CreateAudience.Summary)
+	}
+
+	// An empty expression removes it, leaving the criteria
+	resp = ExecuteGraphQL(t, `
+		mutation Update($id: ID!) {
+			updateAudience(id: $id, input: {expression: ""}) { expression summary }
+		}
+	`, map[string]interface{}{"id": created.CreateAudience.ID})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+
+	var updated struct {
+		UpdateAudience struct {
+			Expression string `json:"expression"`
+			Summary    string `json:"summary"`
+		} `json:"updateAudience"`
+	}
+	if err := json.Unmarshal(resp.Data, &updated); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	want = "People who are Male and spend more than 3 hours a day on social media"
+	if updated.UpdateAudience.Expression != "" || updated.UpdateAudience.Summary != want {
+		t.Errorf("expected no expression and summary %q, got %+v", want, updated.UpdateAudience)
+	}
+}
+
+// TestAudience_Size tests sizing audiences against the respondents in REST and GraphQL
+func TestAudience_Size(t *testing.T) {
+	CleanupTestData()
+
+	respondents := []models.Respondent{
+		{Gender: "Male", BirthCountry: "GR", AgeGroup: "18-24", DailyHours: 4, NoOfPurchases: 1},
+		{Gender: "Male", BirthCountry: "CY", AgeGroup: "25-34", DailyHours: 1, NoOfPurchases: 0},
+		{Gender: "Female", BirthCountry: "GR", AgeGroup: "25-34", DailyHours: 5, NoOfPurchases: 3},
+		{Gender: "Other", BirthCountry: "US", AgeGroup: "65+", DailyHours: 0, NoOfPurchases: 12},
+	}
+	if err := testRepos.Respondents.Replace(context.Background(), respondents); err != nil {
+		t.Fatalf("failed to seed respondents: %v", err)
+	}
+
+	audience := models.Audience{Criteria: models.AudienceCriteria{BirthCountries: []string{"GR"}}}
+	Seed(t, &audience)
+
+	status, resp := ExecuteREST(t, http.MethodGet, fmt.Sprintf("/audience/%d/size", audience.ID), nil)
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %v", status, resp["message"])
+	}
+	size := resp["data"].(map[string]any)
+	if size["count"] != float64(2) || size["base"] != float64(4) || size["percentage"] != float64(50) {
+		t.Errorf("expected 2 of 4 respondents (50%%), got %v", size)
+	}
+
+	status, _ = ExecuteREST(t, http.MethodGet, fmt.Sprintf("/audience/%d/size", audience.ID+1000), nil)
+	if status != http.StatusNotFound {
+		t.Errorf("expected status 404 for a missing audience, got %d", status)
+	}
+
+	// The size follows updates of the audience
+	status, resp = ExecuteREST(t, http.MethodPut, fmt.Sprintf("/audience/%d", audience.ID), map[string]any{"expression": "social_hours > 4"})
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %v", status, resp["message"])
+	}
+
+	gqlResp := ExecuteGraphQL(t, `
+		query Size($id: ID!) {
+			audienceSize(id: $id) { count base percentage audienceversion datasetversion }
+		}
+	`, map[string]interface{}{"id": fmt.Sprint(audience.ID)})
+	if len(gqlResp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", gqlResp.Errors)
+	}
+
+	var result struct {
+		AudienceSize struct {
+			Count           int64   `json:"count"`
+			Base            int64   `json:"base"`
+			Percentage      float64 `json:"percentage"`
+			AudienceVersion string  `json:"audienceversion"`
+		} `json:"audienceSize"`
+	}
+	if err := json.Unmarshal(gqlResp.Data, &result); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if got := result.AudienceSize; got.Count != 1 || got.Base != 4 || got.Percentage != 25 {
+		t.Errorf("expected 1 of 4 respondents (25%%), got %+v", got)
+	}
+	if result.AudienceSize.AudienceVersion == size["audienceversion"] {
+		t.Errorf("expected a new audience version after the update, got %q", result.AudienceSize.AudienceVersion)
+	}
+}
+
+// TestAudience_Compare tests comparing audiences in REST and GraphQL
+func TestAudience_Compare(t *testing.T) {
+	CleanupTestData()
+
+	respondents := []models.Respondent{
+		{Gender: "Male", BirthCountry: "GR", AgeGroup: "18-24", DailyHours: 4, NoOfPurchases: 1},
+		{Gender: "Male", BirthCountry: "CY", AgeGroup: "25-34", DailyHours: 1, NoOfPurchases: 0},
+		{Gender: "Female", BirthCountry: "GR", AgeGroup: "25-34", DailyHours: 5, NoOfPurchases: 3},
+		{Gender: "Other", BirthCountry: "US", AgeGroup: "65+", DailyHours: 0, NoOfPurchases: 12},
+	}
+	if err := testRepos.Respondents.Replace(context.Background(), respondents); err != nil {
+		t.Fatalf("failed to seed respondents: %v", err)
+	}
+
+	males := models.Audience{Criteria: models.AudienceCriteria{Genders: []string{"Male"}}}
+	greeks := models.Audience{Criteria: models.AudienceCriteria{BirthCountries: []string{"GR"}}}
+	Seed(t, &males)
+	Seed(t, &greeks)
+
+	status, resp := ExecuteREST(t, http.MethodGet, fmt.Sprintf("/audiences/compare?ids=%d,%d", males.ID, greeks.ID), nil)
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %v", status, resp["message"])
+	}
+	data := resp["data"].(map[string]any)
+	overlap := data["matrix"].([]any)[0].([]any)[1].(map[string]any)
+	if overlap["intersection"] != float64(1) || overlap["union"] != float64(3) {
+		t.Errorf("expected 1 of 3 respondents in both audiences, got %v", overlap)
+	}
+	if data["base"] != float64(4) {
+		t.Errorf("expected a base of 4 respondents, got %v", data["base"])
+	}
+
+	for query, want := range map[string]int{
+		fmt.Sprintf("ids=%d", males.ID):                    http.StatusBadRequest,
+		fmt.Sprintf("ids=%d,%d", males.ID, males.ID):       http.StatusBadRequest,
+		fmt.Sprintf("ids=%d,abc", males.ID):                http.StatusBadRequest,
+		fmt.Sprintf("ids=%d,%d", males.ID, greeks.ID+1000): http.StatusNotFound,
+	} {
+		if status, _ := ExecuteREST(t, http.MethodGet, "/audiences/compare?"+query, nil); status != want {
+			t.Errorf("%s: expected status %d, got %d", query, want, status)
+		}
+	}
+
+	gqlResp := ExecuteGraphQL(t, `
+		query Compare($ids: [ID!]!) {
+			compareAudiences(ids: $ids) {
+				audiences { count percentage }
+				matrix { intersection union jaccard }
+				base
+			}
+		}
+	`, map[string]interface{}{"ids": []string{fmt.Sprint(greeks.ID), fmt.Sprint(males.ID)}})
+	if len(gqlResp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", gqlResp.Errors)
+	}
+
+	var result struct {
+		CompareAudiences struct {
+			Matrix [][]struct {
+				Intersection int64   `json:"intersection"`
+				Union        int64   `json:"union"`
+				Jaccard      float64 `json:"jaccard"`
+			} `json:"matrix"`
+		} `json:"compareAudiences"`
+	}
+	if err := json.Unmarshal(gqlResp.Data, &result); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	matrix := result.CompareAudiences.Matrix
+	if len(matrix) != 2 || matrix[1][0].Jaccard != 1.0/3 || matrix[0][0].Jaccard != 1 {
+		t.Errorf("expected a Jaccard score of 1/3 between the audiences, got %+v", matrix)
+	}
+}
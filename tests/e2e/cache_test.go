@@ -0,0 +1,88 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"platform-go-challenge/models"
+	"testing"
+)
+
+// favouriteAssets returns the assets of the REST favourites of a user
+func favouriteAssets(t *testing.T, userID uint) []map[string]any {
+	status, resp := ExecuteREST(t, http.MethodGet, fmt.Sprintf("/users/%d/favourites", userID), nil)
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", status)
+	}
+
+	var assets []map[string]any
+	for _, item := range PageItems(t, resp) {
+		assets = append(assets, item.(map[string]any)["asset"].(map[string]any))
+	}
+	return assets
+}
+
+// TestCache_InvalidatedByStarChanges tests that cached favourites reflect stars
+// added and removed after they were cached
+func TestCache_InvalidatedByStarChanges(t *testing.T) {
+	CleanupTestData()
+
+	_, chartID, insightID := SeedTestData(t)
+	Seed(t, &models.UserStar{UserID: 1, Type: models.AssetTypeChart, AssetID: chartID})
+
+	if assets := favouriteAssets(t, 1); len(assets) != 1 {
+		t.Fatalf("expected 1 favourite, got %d", len(assets))
+	}
+
+	status, _ := ExecuteREST(t, http.MethodPut, fmt.Sprintf("/users/1/favourites/Insight/%d", insightID), nil)
+	if status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", status)
+	}
+	if assets := favouriteAssets(t, 1); len(assets) != 2 {
+		t.Errorf("expected 2 favourites after starring, got %d", len(assets))
+	}
+
+	status, _ = ExecuteREST(t, http.MethodDelete, fmt.Sprintf("/users/1/favourites/Chart/%d", chartID), nil)
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", status)
+	}
+	assets := favouriteAssets(t, 1)
+	if len(assets) != 1 || assets[0]["id"] != float64(insightID) {
+		t.Errorf("expected only insight %d after unstarring the chart, got %v", insightID, assets)
+	}
+}
+
+// TestCache_InvalidatedByAssetChanges tests that cached favourites reflect updates
+// and deletions of the starred assets, for every user who starred them
+func TestCache_InvalidatedByAssetChanges(t *testing.T) {
+	CleanupTestData()
+
+	_, chartID, insightID := SeedTestData(t)
+	for _, userID := range []uint{1, 2} {
+		Seed(t, &models.UserStar{UserID: userID, Type: models.AssetTypeChart, AssetID: chartID})
+		Seed(t, &models.UserStar{UserID: userID, Type: models.AssetTypeInsight, AssetID: insightID})
+		favouriteAssets(t, userID)
+	}
+
+	status, _ := ExecuteREST(t, http.MethodPut, fmt.Sprintf("/chart/%d", chartID), map[string]any{
+		"title": "Renamed Chart", "xaxistitle": "X", "yaxistitle": "Y",
+	})
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", status)
+	}
+
+	resp := ExecuteGraphQL(t, `mutation DeleteInsight($id: ID!) { deleteInsight(id: $id) }`,
+		map[string]interface{}{"id": fmt.Sprintf("%d", insightID)})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+
+	for _, userID := range []uint{1, 2} {
+		assets := favouriteAssets(t, userID)
+		if len(assets) != 1 {
+			t.Fatalf("user %d: expected 1 favourite after deleting the insight, got %d", userID, len(assets))
+		}
+		if assets[0]["title"] != "Renamed Chart" {
+			t.Errorf("user %d: expected renamed chart, got %v", userID, assets[0]["title"])
+		}
+	}
+}
@@ -0,0 +1,467 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+	"reflect"
+	"testing"
+)
+
+// countChartSeries returns the number of stored series of a chart. Against
+// a database it counts the chart_series rows, so that orphaned rows are caught.
+func countChartSeries(t *testing.T, chartID uint) int64 {
+	if testDB != nil {
+		var count int64
+		if err := testDB.Model(&models.ChartSeries{}).Where("chart_id = ?", chartID).Count(&count).Error; err != nil {
+			t.Fatalf("failed to count series: %v", err)
+		}
+		return count
+	}
+
+	chart, err := testRepos.Charts.Get(context.Background(), chartID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return 0
+	}
+	if err != nil {
+		t.Fatalf("failed to load chart: %v", err)
+	}
+	return int64(len(chart.Series))
+}
+
+type gqlChartSeries struct {
+	Name   string    `json:"name"`
+	Points []float64 `json:"points"`
+}
+
+// TestChart_RESTSeries tests creating, reading and updating chart series through REST
+func TestChart_RESTSeries(t *testing.T) {
+	CleanupTestData()
+
+	status, resp := ExecuteREST(t, http.MethodPost, "/chart", map[string]any{
+		"title":      "Revenue",
+		"xaxistitle": "Quarter",
+		"yaxistitle": "EUR",
+		"labels":     []string{"Q1", "Q2", "Q3"},
+		"series": []map[string]any{
+			{"name": "2024", "points": []float64{10, 20, 30}},
+			{"name": "2025", "points": []float64{15, 25, 35}},
+		},
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %v", status, resp["message"])
+	}
+	chartID := uint(resp["data"].(map[string]any)["id"].(float64))
+
+	stored, err := testRepos.Charts.Get(context.Background(), chartID)
+	if err != nil {
+		t.Fatalf("failed to load chart: %v", err)
+	}
+	if len(stored.Series) != 2 || !reflect.DeepEqual(stored.Series[1].Points, []float64{15, 25, 35}) {
+		t.Errorf("expected 2 persisted series, got %+v", stored.Series)
+	}
+
+	// Updating the title only keeps the series
+	path := fmt.Sprintf("/chart/%d", chartID)
+	status, resp = ExecuteREST(t, http.MethodPut, path, map[string]any{"title": "Revenue by quarter"})
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %v", status, resp["message"])
+	}
+
+	_, resp = ExecuteREST(t, http.MethodGet, path, nil)
+	data := resp["data"].(map[string]any)
+	if data["title"] != "Revenue by quarter" || len(data["series"].([]any)) != 2 {
+		t.Errorf("expected title update to keep 2 series, got %v", data)
+	}
+
+	// Updating the series replaces them
+	status, _ = ExecuteREST(t, http.MethodPut, path, map[string]any{
+		"labels": []string{"H1", "H2"},
+		"series": []map[string]any{{"name": "2025", "points": []float64{40, 60}}},
+	})
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", status)
+	}
+
+	if count := countChartSeries(t, chartID); count != 1 {
+		t.Errorf("expected old series to be replaced, got %d series", count)
+	}
+
+	// Deleting the chart removes its series
+	ExecuteREST(t, http.MethodDelete, path, nil)
+	if count := countChartSeries(t, chartID); count != 0 {
+		t.Errorf("expected series to be deleted with the chart, got %d", count)
+	}
+}
+
+// TestChart_RESTSeriesValidation tests that series must match the labels
+func TestChart_RESTSeriesValidation(t *testing.T) {
+	CleanupTestData()
+
+	status, _ := ExecuteREST(t, http.MethodPost, "/chart", map[string]any{
+		"title":  "Broken",
+		"labels": []string{"Q1", "Q2", "Q3"},
+		"series": []map[string]any{{"name": "2024", "points": []float64{1, 2}}},
+	})
+	if status != http.StatusBadRequest {
+		t.Errorf("expected status 400 for mismatched series, got %d", status)
+	}
+
+	chart := models.Chart{
+		Title:  "Single quarter",
+		Labels: []string{"Q1"},
+		Series: []models.ChartSeries{{Name: "2024", Points: []float64{1}}},
+	}
+	Seed(t, &chart)
+
+	// Changing labels without matching series is rejected too
+	status, _ = ExecuteREST(t, http.MethodPut, fmt.Sprintf("/chart/%d", chart.ID),
+		map[string]any{"labels": []string{"Q1", "Q2"}})
+	if status != http.StatusBadRequest {
+		t.Errorf("expected status 400 for labels not matching existing series, got %d", status)
+	}
+}
+
+// TestChart_GraphQLSeries tests the series fields of the chart mutations and queries
+func TestChart_GraphQLSeries(t *testing.T) {
+	CleanupTestData()
+
+	resp := ExecuteGraphQL(t, `
+		mutation CreateChart($input: NewChart!) {
+			createChart(input: $input) {
+				id
+				labels
+				series { name points }
+			}
+		}
+	`, map[string]interface{}{
+		"input": map[string]interface{}{
+			"title":      "Visitors",
+			"xaxistitle": "Day",
+			"yaxistitle": "Count",
+			"labels":     []string{"Mon", "Tue"},
+			"series": []map[string]interface{}{
+				{"name": "Web", "points": []float64{100, 120}},
+				{"name": "App", "points": []float64{80, 95.5}},
+			},
+		},
+	})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+
+	var created struct {
+		CreateChart struct {
+			ID     string           `json:"id"`
+			Labels []string         `json:"labels"`
+			Series []gqlChartSeries `json:"series"`
+		} `json:"createChart"`
+	}
+	if err := json.Unmarshal(resp.Data, &created); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	resp = ExecuteGraphQL(t, `
+		query GetChart($id: ID!) {
+			chart(id: $id) { labels series { name points } }
+		}
+	`, map[string]interface{}{"id": created.CreateChart.ID})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+
+	var read struct {
+		Chart struct {
+			Labels []string         `json:"labels"`
+			Series []gqlChartSeries `json:"series"`
+		} `json:"chart"`
+	}
+	if err := json.Unmarshal(resp.Data, &read); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	wantSeries := []gqlChartSeries{{"Web", []float64{100, 120}}, {"App", []float64{80, 95.5}}}
+	if !reflect.DeepEqual(read.Chart.Labels, []string{"Mon", "Tue"}) || !reflect.DeepEqual(read.Chart.Series, wantSeries) {
+		t.Errorf("unexpected chart data: %+v", read.Chart)
+	}
+
+	// Mismatched series are rejected on update
+	resp = ExecuteGraphQL(t, `
+		mutation UpdateChart($id: ID!, $input: UpdateChart!) {
+			updateChart(id: $id, input: $input) { id }
+		}
+	`, map[string]interface{}{
+		"id":    created.CreateChart.ID,
+		"input": map[string]interface{}{"series": []map[string]interface{}{{"name": "Web", "points": []float64{1}}}},
+	})
+	if len(resp.Errors) == 0 {
+		t.Errorf("expected validation error, got none")
+	}
+}
+
+// TestChart_RESTGenerated tests generating and refreshing charts of an audience in REST
+func TestChart_RESTGenerated(t *testing.T) {
+	CleanupTestData()
+
+	respondents := []models.Respondent{
+		{Gender: "Male", BirthCountry: "GR", AgeGroup: "18-24", DailyHours: 4, NoOfPurchases: 1},
+		{Gender: "Female", BirthCountry: "GR", AgeGroup: "25-34", DailyHours: 5, NoOfPurchases: 3},
+		{Gender: "Male", BirthCountry: "US", AgeGroup: "65+", DailyHours: 0, NoOfPurchases: 12},
+	}
+	if err := testRepos.Respondents.Replace(context.Background(), respondents); err != nil {
+		t.Fatalf("failed to seed respondents: %v", err)
+	}
+	audience := models.Audience{Criteria: models.AudienceCriteria{BirthCountries: []string{"GR"}}}
+	Seed(t, &audience)
+
+	status, resp := ExecuteREST(t, http.MethodPost, fmt.Sprintf("/audience/%d/chart", audience.ID), map[string]any{"dimension": "gender"})
+	if status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %v", status, resp["message"])
+	}
+	chart := resp["data"].(map[string]any)
+	chartID := uint(chart["id"].(float64))
+	if !reflect.DeepEqual(chart["labels"], []any{"Male", "Female", "Other"}) {
+		t.Errorf("expected every gender, got %v", chart["labels"])
+	}
+	if provenance := chart["provenance"].(map[string]any); provenance["dimension"] != "GENDER" || provenance["audienceid"] != float64(audience.ID) {
+		t.Errorf("expected the provenance of the chart, got %v", provenance)
+	}
+
+	// The provenance cannot be changed by updates
+	status, _ = ExecuteREST(t, http.MethodPut, fmt.Sprintf("/chart/%d", chartID), map[string]any{
+		"title": "Greeks by gender", "provenance": nil,
+	})
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", status)
+	}
+
+	status, resp = ExecuteREST(t, http.MethodPut, fmt.Sprintf("/audience/%d", audience.ID), map[string]any{
+		"criteria": map[string]any{"birthcountries": []string{"GR", "US"}},
+	})
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %v", status, resp["message"])
+	}
+	status, resp = ExecuteREST(t, http.MethodPost, fmt.Sprintf("/chart/%d/refresh", chartID), nil)
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %v", status, resp["message"])
+	}
+	refreshed := resp["data"].(map[string]any)
+	points := refreshed["series"].([]any)[0].(map[string]any)["points"]
+	if refreshed["title"] != "Greeks by gender" || !reflect.DeepEqual(points, []any{float64(2), float64(1), float64(0)}) {
+		t.Errorf("expected the updated audience with the title kept, got %q and %v", refreshed["title"], points)
+	}
+
+	manual := models.Chart{Title: "Sales"}
+	Seed(t, &manual)
+	for _, tt := range []struct {
+		path       string
+		body       any
+		wantStatus int
+	}{
+		{fmt.Sprintf("/audience/%d/chart", audience.ID), map[string]any{"dimension": "income"}, http.StatusBadRequest},
+		{fmt.Sprintf("/audience/%d/chart", audience.ID+1000), map[string]any{"dimension": "GENDER"}, http.StatusNotFound},
+		{fmt.Sprintf("/chart/%d/refresh", chartID+1000), nil, http.StatusNotFound},
+		{fmt.Sprintf("/chart/%d/refresh", manual.ID), nil, http.StatusConflict},
+	} {
+		if status, _ := ExecuteREST(t, http.MethodPost, tt.path, tt.body); status != tt.wantStatus {
+			t.Errorf("POST %s: expected status %d, got %d", tt.path, tt.wantStatus, status)
+		}
+	}
+}
+
+// TestChart_GraphQLGenerated tests generating a chart of an audience in GraphQL
+func TestChart_GraphQLGenerated(t *testing.T) {
+	CleanupTestData()
+
+	respondents := []models.Respondent{
+		{Gender: "Male", BirthCountry: "GR", AgeGroup: "18-24", DailyHours: 4, NoOfPurchases: 1},
+		{Gender: "Female", BirthCountry: "CY", AgeGroup: "18-24", DailyHours: 5, NoOfPurchases: 3},
+		{Gender: "Female", BirthCountry: "CY", AgeGroup: "65+", DailyHours: 5, NoOfPurchases: 3},
+	}
+	if err := testRepos.Respondents.Replace(context.Background(), respondents); err != nil {
+		t.Fatalf("failed to seed respondents: %v", err)
+	}
+	audience := models.Audience{Criteria: models.AudienceCriteria{AgeGroups: []string{"18-24"}}}
+	Seed(t, &audience)
+
+	resp := ExecuteGraphQL(t, `
+		mutation Generate($audienceID: ID!) {
+			generateChart(audienceID: $audienceID, dimension: BIRTH_COUNTRY) {
+				title
+				labels
+				series { name points }
+				provenance { audienceid dimension datasetversion }
+			}
+		}
+	`, map[string]interface{}{"audienceID": fmt.Sprint(audience.ID)})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+
+	var generated struct {
+		GenerateChart struct {
+			Title      string           `json:"title"`
+			Labels     []string         `json:"labels"`
+			Series     []gqlChartSeries `json:"series"`
+			Provenance struct {
+				AudienceID string `json:"audienceid"`
+				Dimension  string `json:"dimension"`
+			} `json:"provenance"`
+		} `json:"generateChart"`
+	}
+	if err := json.Unmarshal(resp.Data, &generated); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	chart := generated.GenerateChart
+	wantSeries := []gqlChartSeries{{"Respondents", []float64{1, 1}}, {"Share (%)", []float64{50, 50}}}
+	if !reflect.DeepEqual(chart.Labels, []string{"CY", "GR"}) || !reflect.DeepEqual(chart.Series, wantSeries) {
+		t.Errorf("unexpected chart data: %+v", chart)
+	}
+	if chart.Provenance.AudienceID != fmt.Sprint(audience.ID) || chart.Provenance.Dimension != "BIRTH_COUNTRY" {
+		t.Errorf("expected the provenance of the chart, got %+v", chart.Provenance)
+	}
+}
+
+// TestChart_RESTTypes tests that chart types and their configuration are
+// stored and that configurations not fitting the type are rejected
+func TestChart_RESTTypes(t *testing.T) {
+	CleanupTestData()
+
+	status, resp := ExecuteREST(t, http.MethodPost, "/chart", map[string]any{
+		"title":     "Sales",
+		"charttype": "stacked_bar",
+		"labels":    []string{"Q1", "Q2"},
+		"series": []map[string]any{
+			{"name": "Online", "points": []float64{1, 2}},
+			{"name": "Stores", "points": []float64{3, 4}},
+		},
+		"config": map[string]any{"stacks": []map[string]any{{"name": "Total", "series": []string{"Online", "Stores"}}}},
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %v", status, resp)
+	}
+	chartID := uint(resp["data"].(map[string]any)["id"].(float64))
+
+	_, resp = ExecuteREST(t, http.MethodGet, fmt.Sprintf("/chart/%d", chartID), nil)
+	chart := resp["data"].(map[string]any)
+	stacks := chart["config"].(map[string]any)["stacks"].([]any)
+	if chart["charttype"] != "STACKED_BAR" || len(stacks) != 1 {
+		t.Errorf("expected a stacked bar chart with its stack, got %v", chart)
+	}
+
+	// Changing the type keeps the configuration, which must fit the new type
+	status, _ = ExecuteREST(t, http.MethodPut, fmt.Sprintf("/chart/%d", chartID), map[string]any{"charttype": "LINE"})
+	if status != http.StatusBadRequest {
+		t.Errorf("expected status 400 for stacks on a line chart, got %d", status)
+	}
+	status, resp = ExecuteREST(t, http.MethodPut, fmt.Sprintf("/chart/%d", chartID),
+		map[string]any{"charttype": "LINE", "config": map[string]any{"stacks": []any{}}})
+	if status != http.StatusOK || resp["data"].(map[string]any)["charttype"] != "LINE" {
+		t.Errorf("expected the chart to become a line chart, got %d: %v", status, resp)
+	}
+
+	// Charts created without a type are bar charts
+	status, resp = ExecuteREST(t, http.MethodPost, "/chart", map[string]any{"title": "Untyped"})
+	if status != http.StatusCreated || resp["data"].(map[string]any)["charttype"] != "BAR" {
+		t.Errorf("expected a bar chart, got %d: %v", status, resp)
+	}
+
+	tests := []struct {
+		name  string
+		chart map[string]any
+	}{
+		{"Unknown type", map[string]any{"title": "Sales", "charttype": "DONUT"}},
+		{"Pie with axes", map[string]any{"title": "Share", "charttype": "PIE", "xaxistitle": "Quarter"}},
+		{"Stacked bar without stacks", map[string]any{
+			"title": "Sales", "charttype": "STACKED_BAR", "labels": []string{"Q1"},
+			"series": []map[string]any{{"name": "Online", "points": []float64{1}}},
+		}},
+		{"Scatter with categories", map[string]any{"title": "Spend", "charttype": "SCATTER", "labels": []string{"Q1"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := ExecuteREST(t, http.MethodPost, "/chart", tt.chart)
+			if status != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d: %v", status, resp)
+			}
+		})
+	}
+}
+
+// TestChart_GraphQLTypes tests the chart type and configuration of the chart
+// mutations and queries
+func TestChart_GraphQLTypes(t *testing.T) {
+	CleanupTestData()
+
+	resp := ExecuteGraphQL(t, `
+		mutation {
+			createChart(input: {
+				title: "Share"
+				charttype: PIE
+				labels: ["Online", "Stores"]
+				series: [{ name: "2025", points: [40, 60] }]
+			}) { id charttype xaxistitle config { stacks { name } } }
+		}
+	`, nil)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+	var created struct {
+		CreateChart struct {
+			ID        string `json:"id"`
+			ChartType string `json:"charttype"`
+			Config    struct {
+				Stacks []any `json:"stacks"`
+			} `json:"config"`
+		} `json:"createChart"`
+	}
+	if err := json.Unmarshal(resp.Data, &created); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if created.CreateChart.ChartType != "PIE" || created.CreateChart.Config.Stacks == nil {
+		t.Errorf("expected a pie chart without stacks, got %+v", created.CreateChart)
+	}
+
+	// A pie chart has no axes
+	resp = ExecuteGraphQL(t, `
+		mutation Update($id: ID!) {
+			updateChart(id: $id, input: { yaxistitle: "Sales" }) { id }
+		}
+	`, map[string]interface{}{"id": created.CreateChart.ID})
+	if len(resp.Errors) == 0 {
+		t.Error("expected an error for an axis title on a pie chart")
+	}
+
+	resp = ExecuteGraphQL(t, `
+		mutation Update($id: ID!) {
+			updateChart(id: $id, input: {
+				charttype: STACKED_BAR
+				config: { stacks: [{ name: "Total", series: ["2025"] }] }
+			}) { charttype config { stacks { name series } } }
+		}
+	`, map[string]interface{}{"id": created.CreateChart.ID})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+	var updated struct {
+		UpdateChart struct {
+			ChartType string `json:"charttype"`
+			Config    struct {
+				Stacks []struct {
+					Name   string   `json:"name"`
+					Series []string `json:"series"`
+				} `json:"stacks"`
+			} `json:"config"`
+		} `json:"updateChart"`
+	}
+	if err := json.Unmarshal(resp.Data, &updated); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	stacks := updated.UpdateChart.Config.Stacks
+	if updated.UpdateChart.ChartType != "STACKED_BAR" || len(stacks) != 1 || !reflect.DeepEqual(stacks[0].Series, []string{"2025"}) {
+		t.Errorf("expected a stacked bar chart with its stack, got %+v", updated.UpdateChart)
+	}
+}
@@ -0,0 +1,113 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"platform-go-challenge/models"
+	"testing"
+)
+
+// TestDescription_PerUser tests that two users can describe the same favourite differently
+func TestDescription_PerUser(t *testing.T) {
+	CleanupTestData()
+
+	_, chartID, _ := SeedTestData(t)
+	Seed(t, &models.UserStar{UserID: 1, Type: models.AssetTypeChart, AssetID: chartID})
+	Seed(t, &models.UserStar{UserID: 2, Type: models.AssetTypeChart, AssetID: chartID})
+
+	// User 1 through REST
+	status, resp := ExecuteREST(t, http.MethodPatch, fmt.Sprintf("/users/1/favourites/chart/%d", chartID),
+		map[string]any{"description": "Quarterly review"})
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %v", status, resp["message"])
+	}
+	if got := resp["data"].(map[string]any)["description"]; got != "Quarterly review" {
+		t.Errorf("expected description 'Quarterly review', got %v", got)
+	}
+
+	// User 2 through GraphQL
+	gqlResp := ExecuteGraphQL(t, `
+		mutation Describe($userID: ID!, $type: String!, $assetID: ID!, $description: String!) {
+			updateFavouriteDescription(userID: $userID, type: $type, assetID: $assetID, description: $description) {
+				userid
+				description
+			}
+		}
+	`, map[string]interface{}{
+		"userID":      "2",
+		"type":        "Chart",
+		"assetID":     fmt.Sprintf("%d", chartID),
+		"description": "Share with marketing",
+	})
+	if len(gqlResp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", gqlResp.Errors)
+	}
+
+	// Each user sees their own description in the REST favourites
+	for userID, want := range map[int]string{1: "Quarterly review", 2: "Share with marketing"} {
+		_, resp := ExecuteREST(t, http.MethodGet, fmt.Sprintf("/users/%d/favourites", userID), nil)
+		data := PageItems(t, resp)
+		if len(data) != 1 {
+			t.Fatalf("user %d: expected 1 favourite, got %d", userID, len(data))
+		}
+		if got := data[0].(map[string]any)["description"]; got != want {
+			t.Errorf("user %d: expected description %q, got %v", userID, want, got)
+		}
+	}
+
+	// And inside userstared
+	gqlResp = ExecuteGraphQL(t, `
+		query GetUserStared($userID: ID!) {
+			userstared(userID: $userID) {
+				stars { type assetid description }
+			}
+		}
+	`, map[string]interface{}{"userID": "1"})
+	if len(gqlResp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", gqlResp.Errors)
+	}
+
+	var result struct {
+		Userstared struct {
+			Stars []struct {
+				Type        string `json:"type"`
+				Assetid     int    `json:"assetid"`
+				Description string `json:"description"`
+			} `json:"stars"`
+		} `json:"userstared"`
+	}
+	if err := json.Unmarshal(gqlResp.Data, &result); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(result.Userstared.Stars) != 1 || result.Userstared.Stars[0].Description != "Quarterly review" {
+		t.Errorf("expected userstared to return user 1's description, got %+v", result.Userstared.Stars)
+	}
+}
+
+// TestDescription_NotStarred tests that only starred assets can be described
+func TestDescription_NotStarred(t *testing.T) {
+	CleanupTestData()
+
+	_, chartID, _ := SeedTestData(t)
+	path := fmt.Sprintf("/users/1/favourites/chart/%d", chartID)
+
+	status, _ := ExecuteREST(t, http.MethodPatch, path, map[string]any{"description": "Not mine"})
+	if status != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", status)
+	}
+
+	status, _ = ExecuteREST(t, http.MethodPatch, path, map[string]any{})
+	if status != http.StatusBadRequest {
+		t.Errorf("expected status 400 without description, got %d", status)
+	}
+
+	gqlResp := ExecuteGraphQL(t, `
+		mutation {
+			updateFavouriteDescription(userID: "1", type: "Chart", assetID: "999999", description: "x") { id }
+		}
+	`, nil)
+	if len(gqlResp.Errors) == 0 {
+		t.Errorf("expected error describing an unstarred asset, got none")
+	}
+}
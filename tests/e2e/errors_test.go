@@ -0,0 +1,121 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"platform-go-challenge/models"
+	"testing"
+)
+
+// fieldNames returns the names of the invalid fields of an error response
+func fieldNames(t *testing.T, value any) []string {
+	if value == nil {
+		return nil
+	}
+	fields, ok := value.([]any)
+	if !ok {
+		t.Fatalf("expected a list of invalid fields, got %T", value)
+	}
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i], _ = field.(map[string]any)["field"].(string)
+	}
+	return names
+}
+
+// TestErrors_RESTCodes tests that REST errors carry a status and a machine-readable code
+func TestErrors_RESTCodes(t *testing.T) {
+	CleanupTestData()
+
+	_, chartID, _ := SeedTestData(t)
+	star := models.UserStar{UserID: 1, Type: models.AssetTypeChart, AssetID: chartID}
+	Seed(t, &star)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       any
+		wantStatus int
+		wantCode   string
+		wantFields []string
+	}{
+		{"Missing chart", http.MethodGet, "/chart/999999", nil, http.StatusNotFound, "NOT_FOUND", nil},
+		{"Invalid ID", http.MethodGet, "/chart/abc", nil, http.StatusBadRequest, "VALIDATION_FAILED", []string{"id"}},
+		{"Invalid limit", http.MethodGet, "/charts?limit=-1", nil, http.StatusBadRequest, "VALIDATION_FAILED", []string{"limit"}},
+		{"Wrong field type", http.MethodPost, "/chart", map[string]any{"title": 5}, http.StatusBadRequest, "VALIDATION_FAILED", []string{"title"}},
+		{"Invalid series", http.MethodPost, "/chart", map[string]any{
+			"title":  "Chart",
+			"labels": []string{"a"},
+			"series": []map[string]any{{"name": "", "points": []float64{1}}, {"name": "B", "points": []float64{}}},
+		}, http.StatusBadRequest, "VALIDATION_FAILED", []string{"series[0].name", "series[1].points"}},
+		{"Invalid asset type", http.MethodPost, "/userstar", map[string]any{"userid": 2, "type": "Dashboard", "assetid": chartID},
+			http.StatusBadRequest, "INVALID_ASSET_TYPE", []string{"type"}},
+		{"Missing asset", http.MethodPost, "/userstar", map[string]any{"userid": 2, "type": "Chart", "assetid": 999999},
+			http.StatusUnprocessableEntity, "ASSET_NOT_FOUND", nil},
+		{"Duplicate star", http.MethodPost, "/userstar", map[string]any{"userid": 1, "type": "Chart", "assetid": chartID},
+			http.StatusConflict, "DUPLICATE_STAR", nil},
+		{"Missing description", http.MethodPatch, fmt.Sprintf("/users/1/favourites/chart/%d", chartID), map[string]any{},
+			http.StatusBadRequest, "VALIDATION_FAILED", []string{"description"}},
+		{"Favourite not starred", http.MethodPatch, fmt.Sprintf("/users/2/favourites/chart/%d", chartID), map[string]any{"description": "x"},
+			http.StatusNotFound, "STAR_NOT_FOUND", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := ExecuteREST(t, tt.method, tt.path, tt.body)
+			if status != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %v", tt.wantStatus, status, resp["message"])
+			}
+			if resp["code"] != tt.wantCode {
+				t.Errorf("expected code %q, got %v", tt.wantCode, resp["code"])
+			}
+			if got := fieldNames(t, resp["errors"]); fmt.Sprint(got) != fmt.Sprint(tt.wantFields) {
+				t.Errorf("expected invalid fields %v, got %v", tt.wantFields, got)
+			}
+		})
+	}
+}
+
+// TestErrors_GraphQLCodes tests that GraphQL errors carry the same codes in extensions
+func TestErrors_GraphQLCodes(t *testing.T) {
+	CleanupTestData()
+
+	_, chartID, _ := SeedTestData(t)
+	Seed(t, &models.UserStar{UserID: 1, Type: models.AssetTypeChart, AssetID: chartID})
+
+	tests := []struct {
+		name       string
+		query      string
+		variables  map[string]any
+		wantCode   string
+		wantFields []string
+	}{
+		{"Missing chart", `query { chart(id: "999999") { id } }`, nil, "NOT_FOUND", nil},
+		{"Invalid ID", `query { chart(id: "abc") { id } }`, nil, "VALIDATION_FAILED", []string{"id"}},
+		{"Negative user", `mutation { createUserStar(input: {userid: -1, type: "Chart", assetid: 1}) { id } }`, nil,
+			"VALIDATION_FAILED", []string{"userid"}},
+		{"Invalid asset type", `mutation { star(userID: "1", type: "dashboard", assetID: "1") { starred } }`, nil,
+			"VALIDATION_FAILED", []string{"type"}},
+		{"Missing asset", `mutation { star(userID: "1", type: "chart", assetID: "999999") { starred } }`, nil,
+			"ASSET_NOT_FOUND", nil},
+		{"Duplicate star", `mutation Create($assetid: Int!) { createUserStar(input: {userid: 1, type: "Chart", assetid: $assetid}) { id } }`,
+			map[string]any{"assetid": chartID}, "DUPLICATE_STAR", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := ExecuteGraphQL(t, tt.query, tt.variables)
+			if len(resp.Errors) != 1 {
+				t.Fatalf("expected one error, got %v", resp.Errors)
+			}
+			extensions := resp.Errors[0].Extensions
+			if extensions["code"] != tt.wantCode {
+				t.Errorf("expected code %q, got %v: %s", tt.wantCode, extensions["code"], resp.Errors[0].Message)
+			}
+			if got := fieldNames(t, extensions["fields"]); fmt.Sprint(got) != fmt.Sprint(tt.wantFields) {
+				t.Errorf("expected invalid fields %v, got %v", tt.wantFields, got)
+			}
+		})
+	}
+}
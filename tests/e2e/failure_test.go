@@ -0,0 +1,279 @@
+package e2e
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"platform-go-challenge/api"
+	"platform-go-challenge/db"
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errInjected is the database failure raised by withFaultyDatabase
+var errInjected = errors.New("injected database failure")
+
+// fault is a database failure injected by withFaultyDatabase
+type fault struct {
+	operation string
+	err       error
+}
+
+// withFaultyDatabase runs fn against a router backed by its own SQLite
+// database, so that it does not depend on the suite's storage backend. fn
+// calls fail with "create", "query", "update" or "delete" to make every
+// database operation of that kind fail with err, or with "" to stop failing.
+func withFaultyDatabase(t *testing.T, fn func(fail func(operation string, err error))) {
+	database := SetupTestDB("sqlite::memory:")
+	defer func() {
+		if sqlDB, err := database.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}()
+
+	var failing atomic.Pointer[fault]
+	failing.Store(&fault{})
+	inject := func(operation string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			if current := failing.Load(); current.operation == operation {
+				tx.AddError(current.err)
+			}
+		}
+	}
+	callbacks := database.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("gorm:create").Register("test:fail_create", inject("create")),
+		callbacks.Query().Before("gorm:query").Register("test:fail_query", inject("query")),
+		callbacks.Update().Before("gorm:update").Register("test:fail_update", inject("update")),
+		callbacks.Delete().Before("gorm:delete").Register("test:fail_delete", inject("delete")),
+	} {
+		if err != nil {
+			t.Fatalf("failed to register fault injection: %v", err)
+		}
+	}
+
+	previousRepos, previousRouter := testRepos, testRouter
+	testRepos = db.NewRepositories(database, repository.AssetDeleteCascade)
+	testRouter = SetupTestRouter(testRepos)
+	defer func() { testRepos, testRouter = previousRepos, previousRouter }()
+
+	fn(func(operation string, err error) { failing.Store(&fault{operation, err}) })
+}
+
+// TestFailure_RESTReturnsServerError tests that failed database operations are reported as 500
+func TestFailure_RESTReturnsServerError(t *testing.T) {
+	withFaultyDatabase(t, func(fail func(string, error)) {
+		audienceID, chartID, insightID := SeedTestData(t)
+		star := models.UserStar{UserID: 1, Type: models.AssetTypeChart, AssetID: chartID}
+		Seed(t, &star)
+
+		tests := []struct {
+			name      string
+			operation string
+			method    string
+			path      string
+			body      any
+		}{
+			{"Create audience", "create", http.MethodPost, "/audience", map[string]any{"criteria": map[string]any{"genders": []string{"Female"}}}},
+			{"Create chart", "create", http.MethodPost, "/chart", map[string]any{"title": "Chart"}},
+			{"Create insight", "create", http.MethodPost, "/insight", map[string]any{"text": "Insight"}},
+			{"Create user star", "create", http.MethodPost, "/userstar", map[string]any{"userid": 2, "type": "Chart", "assetid": chartID}},
+			{"Star favourite", "create", http.MethodPut, fmt.Sprintf("/users/2/favourites/chart/%d", chartID), nil},
+			{"Get chart", "query", http.MethodGet, fmt.Sprintf("/chart/%d", chartID), nil},
+			{"List insights", "query", http.MethodGet, "/insights", nil},
+			{"List favourites", "query", http.MethodGet, "/users/1/favourites", nil},
+			{"Update audience", "update", http.MethodPut, fmt.Sprintf("/audience/%d", audienceID), map[string]any{"criteria": map[string]any{"genders": []string{"Female"}}}},
+			{"Update chart", "update", http.MethodPut, fmt.Sprintf("/chart/%d", chartID), map[string]any{"title": "Renamed"}},
+			{"Update insight", "update", http.MethodPut, fmt.Sprintf("/insight/%d", insightID), map[string]any{"text": "Changed"}},
+			{"Update user star", "update", http.MethodPut, fmt.Sprintf("/userstar/%d", star.ID), map[string]any{"description": "Changed"}},
+			{"Delete chart", "delete", http.MethodDelete, fmt.Sprintf("/chart/%d", chartID), nil},
+			{"Delete user star", "delete", http.MethodDelete, fmt.Sprintf("/userstar/%d", star.ID), nil},
+			{"Unstar favourite", "delete", http.MethodDelete, fmt.Sprintf("/users/1/favourites/chart/%d", chartID), nil},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				fail(tt.operation, errInjected)
+				defer fail("", nil)
+
+				status, resp := ExecuteREST(t, tt.method, tt.path, tt.body)
+				if status != http.StatusInternalServerError {
+					t.Errorf("expected status 500, got %d: %v", status, resp["message"])
+				}
+				if resp["code"] != models.CodeInternal {
+					t.Errorf("expected code %s, got %v", models.CodeInternal, resp["code"])
+				}
+				if resp["data"] != nil {
+					t.Errorf("expected no data, got %v", resp["data"])
+				}
+			})
+		}
+
+		// Nothing was changed by the failed requests
+		ctx := context.Background()
+		chart, err := testRepos.Charts.Get(ctx, chartID)
+		if err != nil {
+			t.Fatalf("expected chart to still exist: %v", err)
+		}
+		if chart.Title != "Sales Chart" {
+			t.Errorf("expected chart title to be unchanged, got %q", chart.Title)
+		}
+		if stars := AllStars(t); len(stars) != 1 {
+			t.Errorf("expected 1 user star, got %d", len(stars))
+		}
+	})
+}
+
+// TestFailure_GraphQLReturnsInternalError tests that failed database operations are reported as internal errors
+func TestFailure_GraphQLReturnsInternalError(t *testing.T) {
+	withFaultyDatabase(t, func(fail func(string, error)) {
+		_, chartID, _ := SeedTestData(t)
+
+		tests := []struct {
+			name      string
+			operation string
+			query     string
+		}{
+			{"Create chart", "create", `mutation { createChart(input: {title: "Chart", xaxistitle: "X", yaxistitle: "Y"}) { id } }`},
+			{"Get chart", "query", fmt.Sprintf(`query { chart(id: "%d") { id } }`, chartID)},
+			{"Update chart", "update", fmt.Sprintf(`mutation { updateChart(id: "%d", input: {title: "Renamed"}) { id } }`, chartID)},
+			{"Delete chart", "delete", fmt.Sprintf(`mutation { deleteChart(id: "%d") }`, chartID)},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				fail(tt.operation, errInjected)
+				defer fail("", nil)
+
+				resp := ExecuteGraphQL(t, tt.query, nil)
+				if len(resp.Errors) != 1 {
+					t.Fatalf("expected one error, got %v", resp.Errors)
+				}
+				if code := resp.Errors[0].Extensions["code"]; code != models.CodeInternal {
+					t.Errorf("expected code %s, got %v: %s", models.CodeInternal, code, resp.Errors[0].Message)
+				}
+			})
+		}
+
+		if _, err := testRepos.Charts.Get(context.Background(), chartID); err != nil {
+			t.Errorf("expected chart to still exist: %v", err)
+		}
+	})
+}
+
+// TestFailure_MissingRecords tests that updating or deleting a missing record is reported as 404
+func TestFailure_MissingRecords(t *testing.T) {
+	CleanupTestData()
+
+	const missing = 999999
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+	}{
+		{"Delete audience", http.MethodDelete, fmt.Sprintf("/audience/%d", missing), nil},
+		{"Delete chart", http.MethodDelete, fmt.Sprintf("/chart/%d", missing), nil},
+		{"Delete insight", http.MethodDelete, fmt.Sprintf("/insight/%d", missing), nil},
+		{"Delete user star", http.MethodDelete, fmt.Sprintf("/userstar/%d", missing), nil},
+		{"Update chart", http.MethodPut, fmt.Sprintf("/chart/%d", missing), map[string]any{"title": "Chart"}},
+		{"Update user star", http.MethodPut, fmt.Sprintf("/userstar/%d", missing), map[string]any{"description": "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := ExecuteREST(t, tt.method, tt.path, tt.body)
+			if status != http.StatusNotFound {
+				t.Errorf("expected status 404, got %d: %v", status, resp["message"])
+			}
+			if resp["code"] != models.CodeNotFound {
+				t.Errorf("expected code %s, got %v", models.CodeNotFound, resp["code"])
+			}
+		})
+	}
+
+	for _, mutation := range []string{"deleteAudience", "deleteChart", "deleteInsight", "deleteUserStar"} {
+		t.Run(mutation, func(t *testing.T) {
+			resp := ExecuteGraphQL(t, fmt.Sprintf(`mutation { %s(id: "%d") }`, mutation, missing), nil)
+			if len(resp.Errors) != 1 {
+				t.Fatalf("expected one error, got %v", resp.Errors)
+			}
+			if code := resp.Errors[0].Extensions["code"]; code != models.CodeNotFound {
+				t.Errorf("expected code %s, got %v", models.CodeNotFound, code)
+			}
+		})
+	}
+}
+
+// TestFailure_ConnectionLossReturnsUnavailable tests that requests failing because the database cannot be reached are reported as 503
+func TestFailure_ConnectionLossReturnsUnavailable(t *testing.T) {
+	withFaultyDatabase(t, func(fail func(string, error)) {
+		_, chartID, _ := SeedTestData(t)
+
+		fail("query", driver.ErrBadConn)
+		defer fail("", nil)
+
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/chart/%d", chartID), nil))
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected status 503, got %d", w.Code)
+		}
+		if retryAfter := w.Header().Get("Retry-After"); retryAfter == "" {
+			t.Error("expected a Retry-After header")
+		}
+
+		resp := ExecuteGraphQL(t, fmt.Sprintf(`query { chart(id: "%d") { id } }`, chartID), nil)
+		if len(resp.Errors) != 1 {
+			t.Fatalf("expected one error, got %v", resp.Errors)
+		}
+		extensions := resp.Errors[0].Extensions
+		if extensions["code"] != "UNAVAILABLE" {
+			t.Errorf("expected code UNAVAILABLE, got %v", extensions["code"])
+		}
+		if extensions["retryAfter"] == nil {
+			t.Error("expected a retryAfter extension")
+		}
+	})
+}
+
+// unavailable is an api.Availability that is never available
+type unavailable struct{}
+
+func (unavailable) Available() error {
+	return repository.ErrUnavailable.WithRetryAfter(10 * time.Second)
+}
+
+// TestFailure_UnavailableStorageIsRefused tests that requests are refused with 503 while the storage is unavailable
+func TestFailure_UnavailableStorageIsRefused(t *testing.T) {
+	router := gin.New()
+	router.Use(api.RequireAvailable(unavailable{}))
+	api.RegisterRoutes(router, testRepos)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/charts", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+	if retryAfter := w.Header().Get("Retry-After"); retryAfter != "10" {
+		t.Errorf("expected Retry-After 10, got %q", retryAfter)
+	}
+
+	var resp map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp["code"] != "UNAVAILABLE" {
+		t.Errorf("expected code UNAVAILABLE, got %v", resp["code"])
+	}
+}
@@ -0,0 +1,83 @@
+package e2e
+
+import (
+	"net/http"
+	"platform-go-challenge/models"
+	"testing"
+)
+
+// TestUserFavourites_Empty tests the REST favourites endpoint for a user without stars
+func TestUserFavourites_Empty(t *testing.T) {
+	CleanupTestData()
+
+	status, resp := ExecuteREST(t, http.MethodGet, "/users/999/favourites", nil)
+
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", status)
+	}
+
+	data := PageItems(t, resp)
+	if len(data) != 0 {
+		t.Errorf("expected 0 favourites, got %d", len(data))
+	}
+}
+
+// TestUserFavourites_HydratedInStarOrder tests that favourites embed the full asset
+// and are returned in the order they were starred
+func TestUserFavourites_HydratedInStarOrder(t *testing.T) {
+	CleanupTestData()
+
+	audienceID, chartID, insightID := SeedTestData(t)
+
+	// Star in an order that differs from the grouping by type
+	stars := []models.UserStar{
+		{UserID: 1, Type: models.AssetTypeInsight, AssetID: insightID},
+		{UserID: 1, Type: models.AssetTypeAudience, AssetID: audienceID},
+		{UserID: 1, Type: models.AssetTypeChart, AssetID: chartID},
+		{UserID: 2, Type: models.AssetTypeChart, AssetID: chartID},
+	}
+	for _, star := range stars {
+		Seed(t, &star)
+	}
+
+	status, resp := ExecuteREST(t, http.MethodGet, "/users/1/favourites", nil)
+
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", status)
+	}
+
+	data := PageItems(t, resp)
+	if len(data) != 3 {
+		t.Fatalf("expected 3 favourites, got %d", len(data))
+	}
+
+	wantTypes := []string{"Insight", "Audience", "Chart"}
+	for i, item := range data {
+		favourite := item.(map[string]any)
+		if favourite["type"] != wantTypes[i] {
+			t.Errorf("favourite %d: expected type %s, got %v", i, wantTypes[i], favourite["type"])
+		}
+		if _, ok := favourite["asset"].(map[string]any); !ok {
+			t.Errorf("favourite %d: expected embedded asset, got %v", i, favourite["asset"])
+		}
+	}
+
+	insight := data[0].(map[string]any)["asset"].(map[string]any)
+	if insight["text"] != "Revenue increased by 20% this quarter" {
+		t.Errorf("expected insight text to be embedded, got %v", insight["text"])
+	}
+
+	chart := data[2].(map[string]any)["asset"].(map[string]any)
+	if chart["id"] != float64(chartID) || chart["title"] != "Sales Chart" {
+		t.Errorf("expected chart %d 'Sales Chart', got %v", chartID, chart)
+	}
+}
+
+// TestUserFavourites_InvalidUserID tests error handling for a non-numeric user ID
+func TestUserFavourites_InvalidUserID(t *testing.T) {
+	status, _ := ExecuteREST(t, http.MethodGet, "/users/invalid/favourites", nil)
+
+	if status != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", status)
+	}
+}
@@ -0,0 +1,109 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"platform-go-challenge/models"
+	"testing"
+)
+
+// seedTemplateData stores three respondents, two of them born in GR, and an
+// audience of those born in GR
+func seedTemplateData(t *testing.T) models.Audience {
+	t.Helper()
+	respondents := []models.Respondent{
+		{Gender: "Male", BirthCountry: "GR", AgeGroup: "25-34", DailyHours: 4, NoOfPurchases: 1},
+		{Gender: "Female", BirthCountry: "GR", AgeGroup: "25-34", DailyHours: 1, NoOfPurchases: 3},
+		{Gender: "Male", BirthCountry: "US", AgeGroup: "65+", DailyHours: 5, NoOfPurchases: 0},
+	}
+	if err := testRepos.Respondents.Replace(context.Background(), respondents); err != nil {
+		t.Fatalf("failed to seed respondents: %v", err)
+	}
+	audience := models.Audience{Criteria: models.AudienceCriteria{BirthCountries: []string{"GR"}}}
+	Seed(t, &audience)
+	return audience
+}
+
+// TestInsight_RESTTemplate tests that REST renders insight templates on read
+func TestInsight_RESTTemplate(t *testing.T) {
+	CleanupTestData()
+	audience := seedTemplateData(t)
+
+	template := fmt.Sprintf("{{pct audience:%d where social_hours > 3}} of Greeks spend more than 3 hours on social media daily", audience.ID)
+	status, resp := ExecuteREST(t, http.MethodPost, "/insight", map[string]any{"text": template})
+	if status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %v", status, resp["message"])
+	}
+	insightID := uint(resp["data"].(map[string]any)["id"].(float64))
+
+	status, resp = ExecuteREST(t, http.MethodGet, fmt.Sprintf("/insight/%d", insightID), nil)
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", status)
+	}
+	insight := resp["data"].(map[string]any)
+	if insight["text"] != "50% of Greeks spend more than 3 hours on social media daily" || insight["template"] != template {
+		t.Errorf("expected the rendered text and the template, got %v", insight)
+	}
+
+	// Favourites are rendered as well, also when read from the cache
+	Seed(t, &models.UserStar{UserID: 1, Type: models.AssetTypeInsight, AssetID: insightID})
+	ExecuteREST(t, http.MethodGet, "/users/1/favourites", nil)
+	if err := testRepos.Respondents.Replace(context.Background(), []models.Respondent{
+		{Gender: "Male", BirthCountry: "GR", AgeGroup: "25-34", DailyHours: 4, NoOfPurchases: 1},
+	}); err != nil {
+		t.Fatalf("failed to replace respondents: %v", err)
+	}
+	_, resp = ExecuteREST(t, http.MethodGet, "/users/1/favourites", nil)
+	asset := PageItems(t, resp)[0].(map[string]any)["asset"].(map[string]any)
+	if asset["text"] != "100% of Greeks spend more than 3 hours on social media daily" {
+		t.Errorf("expected the favourite rendered on the new respondents, got %v", asset["text"])
+	}
+
+	status, resp = ExecuteREST(t, http.MethodPut, fmt.Sprintf("/insight/%d", insightID), map[string]any{"text": "{{pct audience:}} of Greeks"})
+	if status != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", status)
+	}
+	if fields := fieldNames(t, resp["errors"]); len(fields) != 1 || fields[0] != "text" {
+		t.Errorf("expected an invalid text, got %v", resp["errors"])
+	}
+}
+
+// TestInsight_GraphQLTemplate tests the rendered text and the template of insights in GraphQL
+func TestInsight_GraphQLTemplate(t *testing.T) {
+	CleanupTestData()
+	audience := seedTemplateData(t)
+
+	template := fmt.Sprintf("{{count audience:%d}} of {{count}} respondents were born in GR", audience.ID)
+	resp := ExecuteGraphQL(t, `
+		mutation Create($text: String!) {
+			createInsight(input: {text: $text}) { text template }
+		}
+	`, map[string]interface{}{"text": template})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+
+	var created struct {
+		CreateInsight struct {
+			Text     string `json:"text"`
+			Template string `json:"template"`
+		} `json:"createInsight"`
+	}
+	if err := json.Unmarshal(resp.Data, &created); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if created.CreateInsight.Text != "2 of 3 respondents were born in GR" || created.CreateInsight.Template != template {
+		t.Errorf("expected the rendered text and the template, got %+v", created.CreateInsight)
+	}
+
+	resp = ExecuteGraphQL(t, `
+		mutation {
+			createInsight(input: {text: "{{median}} respondents"}) { text }
+		}
+	`, nil)
+	if len(resp.Errors) == 0 || resp.Errors[0].Extensions["code"] != "VALIDATION_FAILED" {
+		t.Errorf("expected an invalid template to be rejected, got %+v", resp.Errors)
+	}
+}
@@ -0,0 +1,224 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+	"sync"
+	"testing"
+)
+
+// countStars returns the number of stars pointing at an asset
+func countStars(t *testing.T, assetType models.AssetType, assetID uint) int {
+	count := 0
+	for _, star := range AllStars(t) {
+		if star.Type == assetType && star.AssetID == assetID {
+			count++
+		}
+	}
+	return count
+}
+
+// withDeletePolicy runs fn against a router using the given asset delete policy
+// and restores the previous router
+func withDeletePolicy(policy repository.AssetDeletePolicy, fn func()) {
+	previous := testRouter
+	testRouter = SetupTestRouter(NewTestRepositories(policy))
+	defer func() { testRouter = previous }()
+	fn()
+}
+
+// TestIntegrity_RESTCreateStarRequiresAsset tests that REST stars must reference an existing asset
+func TestIntegrity_RESTCreateStarRequiresAsset(t *testing.T) {
+	CleanupTestData()
+
+	_, chartID, _ := SeedTestData(t)
+
+	tests := []struct {
+		name       string
+		body       map[string]any
+		wantStatus int
+	}{
+		{"Existing chart", map[string]any{"userid": 1, "type": "Chart", "assetid": chartID}, http.StatusCreated},
+		{"Missing chart", map[string]any{"userid": 1, "type": "Chart", "assetid": 999999}, http.StatusUnprocessableEntity},
+		{"Invalid type", map[string]any{"userid": 1, "type": "Dashboard", "assetid": chartID}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := ExecuteREST(t, http.MethodPost, "/userstar", tt.body)
+			if status != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %v", tt.wantStatus, status, resp["message"])
+			}
+		})
+	}
+}
+
+// TestIntegrity_RESTUpdateStarRequiresAsset tests that a REST star cannot be moved to a missing asset
+func TestIntegrity_RESTUpdateStarRequiresAsset(t *testing.T) {
+	CleanupTestData()
+
+	_, chartID, _ := SeedTestData(t)
+	star := models.UserStar{UserID: 1, Type: models.AssetTypeChart, AssetID: chartID}
+	Seed(t, &star)
+
+	status, _ := ExecuteREST(t, http.MethodPut, fmt.Sprintf("/userstar/%d", star.ID),
+		map[string]any{"type": "Insight", "assetid": 999999})
+	if status != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422, got %d", status)
+	}
+
+	stored, err := testRepos.Stars.Get(context.Background(), star.ID)
+	if err != nil {
+		t.Fatalf("failed to load star: %v", err)
+	}
+	if stored.Type != models.AssetTypeChart || stored.AssetID != chartID {
+		t.Errorf("expected star to be unchanged, got %+v", stored)
+	}
+}
+
+// TestIntegrity_CascadeDelete tests that deleting an asset removes its stars under the cascade policy
+func TestIntegrity_CascadeDelete(t *testing.T) {
+	withDeletePolicy(repository.AssetDeleteCascade, func() {
+		CleanupTestData()
+
+		audienceID, chartID, insightID := SeedTestData(t)
+		Seed(t, &models.UserStar{UserID: 1, Type: models.AssetTypeChart, AssetID: chartID})
+		Seed(t, &models.UserStar{UserID: 2, Type: models.AssetTypeChart, AssetID: chartID})
+		Seed(t, &models.UserStar{UserID: 1, Type: models.AssetTypeInsight, AssetID: insightID})
+		Seed(t, &models.UserStar{UserID: 1, Type: models.AssetTypeAudience, AssetID: audienceID})
+
+		// REST delete
+		status, _ := ExecuteREST(t, http.MethodDelete, fmt.Sprintf("/chart/%d", chartID), nil)
+		if status != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", status)
+		}
+		if count := countStars(t, models.AssetTypeChart, chartID); count != 0 {
+			t.Errorf("expected chart stars to be removed, got %d", count)
+		}
+
+		// GraphQL delete
+		resp := ExecuteGraphQL(t, `mutation DeleteInsight($id: ID!) { deleteInsight(id: $id) }`,
+			map[string]interface{}{"id": fmt.Sprintf("%d", insightID)})
+		if len(resp.Errors) > 0 {
+			t.Fatalf("expected no errors, got: %v", resp.Errors)
+		}
+		if count := countStars(t, models.AssetTypeInsight, insightID); count != 0 {
+			t.Errorf("expected insight stars to be removed, got %d", count)
+		}
+
+		// Stars of other assets are untouched
+		if count := countStars(t, models.AssetTypeAudience, audienceID); count != 1 {
+			t.Errorf("expected audience star to remain, got %d", count)
+		}
+	})
+}
+
+// TestIntegrity_RestrictDelete tests that starred assets cannot be deleted under the restrict policy
+func TestIntegrity_RestrictDelete(t *testing.T) {
+	withDeletePolicy(repository.AssetDeleteRestrict, func() {
+		CleanupTestData()
+
+		audienceID, chartID, _ := SeedTestData(t)
+		Seed(t, &models.UserStar{UserID: 1, Type: models.AssetTypeChart, AssetID: chartID})
+
+		// REST delete is refused
+		status, _ := ExecuteREST(t, http.MethodDelete, fmt.Sprintf("/chart/%d", chartID), nil)
+		if status != http.StatusConflict {
+			t.Fatalf("expected status 409, got %d", status)
+		}
+
+		// GraphQL delete is refused
+		resp := ExecuteGraphQL(t, `mutation DeleteChart($id: ID!) { deleteChart(id: $id) }`,
+			map[string]interface{}{"id": fmt.Sprintf("%d", chartID)})
+		if len(resp.Errors) == 0 {
+			t.Fatalf("expected error deleting starred chart, got none")
+		}
+
+		if _, err := testRepos.Charts.Get(context.Background(), chartID); err != nil {
+			t.Errorf("expected chart to still exist: %v", err)
+		}
+		if count := countStars(t, models.AssetTypeChart, chartID); count != 1 {
+			t.Errorf("expected chart star to remain, got %d", count)
+		}
+
+		// Assets nobody starred can still be deleted
+		status, _ = ExecuteREST(t, http.MethodDelete, fmt.Sprintf("/audience/%d", audienceID), nil)
+		if status != http.StatusOK {
+			t.Errorf("expected status 200 deleting unstarred audience, got %d", status)
+		}
+	})
+}
+
+// TestIntegrity_ConcurrentStarAndDelete tests that starring or linking an
+// asset while it is deleted never leaves a star or link to a missing asset
+func TestIntegrity_ConcurrentStarAndDelete(t *testing.T) {
+	ctx := context.Background()
+
+	for _, policy := range []repository.AssetDeletePolicy{repository.AssetDeleteCascade, repository.AssetDeleteRestrict} {
+		t.Run(string(policy), func(t *testing.T) {
+			CleanupTestData()
+			repos := NewTestRepositories(policy)
+			_, _, insightID := SeedTestData(t)
+
+			for round := 0; round < 200; round++ {
+				chart := models.Chart{Title: fmt.Sprintf("Chart %d", round)}
+				if err := repos.Charts.Create(ctx, &chart); err != nil {
+					t.Fatalf("failed to create chart: %v", err)
+				}
+
+				var wg sync.WaitGroup
+				var starErr, linkErr, deleteErr error
+				start := make(chan struct{})
+				wg.Add(3)
+				go func() {
+					defer wg.Done()
+					<-start
+					_, _, starErr = repos.Stars.Star(ctx, 1, models.AssetTypeChart, chart.ID)
+				}()
+				go func() {
+					defer wg.Done()
+					<-start
+					_, linkErr = repos.Links.Link(ctx, models.AssetLink{
+						SourceType: models.AssetTypeChart, SourceID: chart.ID, TargetType: models.AssetTypeInsight, TargetID: insightID,
+					})
+				}()
+				go func() {
+					defer wg.Done()
+					<-start
+					deleteErr = repos.Charts.Delete(ctx, chart.ID)
+				}()
+				close(start)
+				wg.Wait()
+
+				for name, err := range map[string]error{"star": starErr, "link": linkErr} {
+					if err != nil && !errors.Is(err, repository.ErrAssetNotFound) {
+						t.Fatalf("round %d: unexpected %s error: %v", round, name, err)
+					}
+				}
+				if deleteErr != nil && !errors.Is(deleteErr, repository.ErrAssetStarred) {
+					t.Fatalf("round %d: unexpected delete error: %v", round, deleteErr)
+				}
+				if deleteErr != nil {
+					continue
+				}
+
+				// The chart is gone, and so must be anything pointing at it
+				stars, err := repos.Stars.AllByAsset(ctx, models.AssetTypeChart, chart.ID)
+				if err != nil || len(stars) != 0 {
+					t.Errorf("round %d: expected no stars of the deleted chart, got %+v, %v", round, stars, err)
+				}
+				if policy == repository.AssetDeleteRestrict && starErr == nil {
+					t.Errorf("round %d: expected either the star or the delete to fail under restrict", round)
+				}
+				links, err := repos.Links.Targets(ctx, models.AssetTypeChart, []uint{chart.ID}, models.AssetTypeInsight)
+				if err != nil || len(links) != 0 {
+					t.Errorf("round %d: expected no links of the deleted chart, got %+v, %v", round, links, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// TestLink_REST tests linking insights and charts to their sources over REST
+// and the links embedded in the asset payloads
+func TestLink_REST(t *testing.T) {
+	CleanupTestData()
+	audienceID, chartID, insightID := SeedTestData(t)
+
+	status, resp := ExecuteREST(t, http.MethodPut, fmt.Sprintf("/chart/%d/sources/audience/%d", chartID, audienceID), nil)
+	if status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %v", status, resp["message"])
+	}
+	for _, path := range []string{
+		fmt.Sprintf("/insight/%d/sources/chart/%d", insightID, chartID),
+		fmt.Sprintf("/insight/%d/sources/Audience/%d", insightID, audienceID),
+	} {
+		if status, resp := ExecuteREST(t, http.MethodPut, path, nil); status != http.StatusCreated {
+			t.Fatalf("expected status 201 for %s, got %d: %v", path, status, resp["message"])
+		}
+	}
+
+	// Linking again is idempotent
+	status, resp = ExecuteREST(t, http.MethodPut, fmt.Sprintf("/chart/%d/sources/audience/%d", chartID, audienceID), nil)
+	if status != http.StatusOK || resp["data"].(map[string]any)["linked"] != true {
+		t.Errorf("expected status 200 and a linked status, got %d: %v", status, resp)
+	}
+
+	_, resp = ExecuteREST(t, http.MethodGet, fmt.Sprintf("/insight/%d", insightID), nil)
+	sources := resp["data"].(map[string]any)["sources"].([]any)
+	wantSources := []string{fmt.Sprintf("/chart/%d", chartID), fmt.Sprintf("/audience/%d", audienceID)}
+	if len(sources) != 2 || sources[0].(map[string]any)["href"] != wantSources[0] || sources[1].(map[string]any)["href"] != wantSources[1] {
+		t.Errorf("expected sources %v, got %v", wantSources, sources)
+	}
+
+	_, resp = ExecuteREST(t, http.MethodGet, fmt.Sprintf("/chart/%d", chartID), nil)
+	insights := resp["data"].(map[string]any)["insights"].([]any)
+	if len(insights) != 1 || insights[0].(map[string]any)["type"] != "Insight" {
+		t.Errorf("expected the insight of the chart, got %v", insights)
+	}
+
+	_, resp = ExecuteREST(t, http.MethodGet, "/audiences", nil)
+	charts := PageItems(t, resp)[0].(map[string]any)["charts"].([]any)
+	if len(charts) != 1 || charts[0].(map[string]any)["href"] != fmt.Sprintf("/chart/%d", chartID) {
+		t.Errorf("expected the chart of the audience, got %v", charts)
+	}
+
+	// Unlinking is idempotent as well
+	for range 2 {
+		status, _ = ExecuteREST(t, http.MethodDelete, fmt.Sprintf("/insight/%d/sources/chart/%d", insightID, chartID), nil)
+		if status != http.StatusOK {
+			t.Errorf("expected status 200, got %d", status)
+		}
+	}
+	_, resp = ExecuteREST(t, http.MethodGet, fmt.Sprintf("/chart/%d", chartID), nil)
+	if insights := resp["data"].(map[string]any)["insights"].([]any); len(insights) != 0 {
+		t.Errorf("expected no insights left, got %v", insights)
+	}
+
+	// Deleting a source removes its links
+	ExecuteREST(t, http.MethodDelete, fmt.Sprintf("/audience/%d", audienceID), nil)
+	_, resp = ExecuteREST(t, http.MethodGet, fmt.Sprintf("/insight/%d", insightID), nil)
+	if sources := resp["data"].(map[string]any)["sources"].([]any); len(sources) != 0 {
+		t.Errorf("expected no sources left, got %v", sources)
+	}
+}
+
+// TestLink_RESTErrors tests the links that cannot be made
+func TestLink_RESTErrors(t *testing.T) {
+	CleanupTestData()
+	audienceID, chartID, insightID := SeedTestData(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"Insight backing a chart", fmt.Sprintf("/chart/%d/sources/insight/%d", chartID, insightID), http.StatusBadRequest},
+		{"Unknown source type", fmt.Sprintf("/insight/%d/sources/report/%d", insightID, audienceID), http.StatusBadRequest},
+		{"Invalid source ID", fmt.Sprintf("/insight/%d/sources/chart/abc", insightID), http.StatusBadRequest},
+		{"Missing source", fmt.Sprintf("/insight/%d/sources/chart/%d", insightID, chartID+100), http.StatusUnprocessableEntity},
+		{"Missing target", fmt.Sprintf("/insight/%d/sources/chart/%d", insightID+100, chartID), http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := ExecuteREST(t, http.MethodPut, tt.path, nil)
+			if status != tt.status {
+				t.Errorf("expected status %d, got %d: %v", tt.status, status, resp["message"])
+			}
+		})
+	}
+}
+
+// TestLink_GraphQL tests the sources, insights and charts fields and the
+// link mutations
+func TestLink_GraphQL(t *testing.T) {
+	CleanupTestData()
+	audienceID, chartID, insightID := SeedTestData(t)
+
+	resp := ExecuteGraphQL(t, `
+		mutation Link($insight: ID!, $chart: ID!, $audience: ID!) {
+			fromChart: linkSource(type: "insight", id: $insight, sourceType: "chart", sourceID: $chart) { id }
+			fromAudience: linkSource(type: "insight", id: $insight, sourceType: "audience", sourceID: $audience) { id }
+			chart: linkSource(type: "chart", id: $chart, sourceType: "audience", sourceID: $audience) { id type }
+		}
+	`, map[string]interface{}{
+		"insight": fmt.Sprint(insightID), "chart": fmt.Sprint(chartID), "audience": fmt.Sprint(audienceID),
+	})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+
+	resp = ExecuteGraphQL(t, `
+		query Links($insight: ID!, $audience: ID!) {
+			insight(id: $insight) {
+				sources {
+					type
+					... on Chart { title insights { id } }
+					... on Audience { charts { id } }
+				}
+			}
+			audience(id: $audience) { charts { title } }
+		}
+	`, map[string]interface{}{"insight": fmt.Sprint(insightID), "audience": fmt.Sprint(audienceID)})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+
+	var result struct {
+		Insight struct {
+			Sources []struct {
+				Type     string `json:"type"`
+				Title    string `json:"title"`
+				Insights []struct {
+					ID string `json:"id"`
+				} `json:"insights"`
+				Charts []struct {
+					ID string `json:"id"`
+				} `json:"charts"`
+			} `json:"sources"`
+		} `json:"insight"`
+		Audience struct {
+			Charts []struct {
+				Title string `json:"title"`
+			} `json:"charts"`
+		} `json:"audience"`
+	}
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	sources := result.Insight.Sources
+	if len(sources) != 2 || sources[0].Type != "Chart" || sources[1].Type != "Audience" {
+		t.Fatalf("expected the chart then the audience, got %+v", sources)
+	}
+	if len(sources[0].Insights) != 1 || sources[0].Insights[0].ID != fmt.Sprint(insightID) {
+		t.Errorf("expected the chart to list the insight, got %+v", sources[0])
+	}
+	if len(sources[1].Charts) != 1 || sources[1].Charts[0].ID != fmt.Sprint(chartID) {
+		t.Errorf("expected the audience to list the chart, got %+v", sources[1])
+	}
+	if len(result.Audience.Charts) != 1 || result.Audience.Charts[0].Title != "Sales Chart" {
+		t.Errorf("expected the chart of the audience, got %+v", result.Audience.Charts)
+	}
+
+	resp = ExecuteGraphQL(t, `
+		mutation Unlink($insight: ID!, $chart: ID!) {
+			unlinkSource(type: "Insight", id: $insight, sourceType: "Chart", sourceID: $chart) {
+				... on Insight { sources { type } }
+			}
+		}
+	`, map[string]interface{}{"insight": fmt.Sprint(insightID), "chart": fmt.Sprint(chartID)})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+	var unlinked struct {
+		UnlinkSource struct {
+			Sources []struct {
+				Type string `json:"type"`
+			} `json:"sources"`
+		} `json:"unlinkSource"`
+	}
+	if err := json.Unmarshal(resp.Data, &unlinked); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(unlinked.UnlinkSource.Sources) != 1 || unlinked.UnlinkSource.Sources[0].Type != "Audience" {
+		t.Errorf("expected only the audience left, got %+v", unlinked.UnlinkSource.Sources)
+	}
+
+	// An audience cannot be derived from a chart
+	resp = ExecuteGraphQL(t, `
+		mutation Link($audience: ID!, $chart: ID!) {
+			linkSource(type: "audience", id: $audience, sourceType: "chart", sourceID: $chart) { id }
+		}
+	`, map[string]interface{}{"audience": fmt.Sprint(audienceID), "chart": fmt.Sprint(chartID)})
+	if len(resp.Errors) == 0 {
+		t.Error("expected an error linking a chart as the source of an audience")
+	}
+}
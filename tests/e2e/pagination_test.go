@@ -0,0 +1,283 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"platform-go-challenge/models"
+	"testing"
+)
+
+// seedCharts creates n charts and returns their IDs in creation order
+func seedCharts(t *testing.T, n int) []uint {
+	ids := make([]uint, 0, n)
+	for i := 0; i < n; i++ {
+		chart := models.Chart{Title: fmt.Sprintf("Chart %d", i), XAxisTitle: "X", YAxisTitle: "Y"}
+		Seed(t, &chart)
+		ids = append(ids, chart.ID)
+	}
+	return ids
+}
+
+// TestPagination_RESTCharts tests walking the chart list page by page over REST
+func TestPagination_RESTCharts(t *testing.T) {
+	CleanupTestData()
+
+	ids := seedCharts(t, 5)
+
+	var seen []uint
+	after := ""
+	for pages := 0; ; pages++ {
+		if pages > len(ids) {
+			t.Fatalf("pagination did not terminate")
+		}
+
+		path := "/charts?limit=2"
+		if after != "" {
+			path += "&after=" + url.QueryEscape(after)
+		}
+		status, resp := ExecuteREST(t, http.MethodGet, path, nil)
+		if status != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %v", status, resp["message"])
+		}
+
+		items := PageItems(t, resp)
+		if len(items) > 2 {
+			t.Fatalf("expected at most 2 items, got %d", len(items))
+		}
+		for _, item := range items {
+			seen = append(seen, uint(item.(map[string]any)["id"].(float64)))
+		}
+
+		pageInfo := resp["data"].(map[string]any)["pageinfo"].(map[string]any)
+		if !pageInfo["hasnextpage"].(bool) {
+			break
+		}
+		after = pageInfo["endcursor"].(string)
+	}
+
+	if fmt.Sprint(seen) != fmt.Sprint(ids) {
+		t.Errorf("expected charts %v, got %v", ids, seen)
+	}
+}
+
+// TestPagination_RESTInvalidParams tests that malformed paging parameters are rejected
+func TestPagination_RESTInvalidParams(t *testing.T) {
+	CleanupTestData()
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"Negative limit", "/charts?limit=-1"},
+		{"Non-numeric limit", "/insights?limit=abc"},
+		{"Invalid cursor", "/audiences?after=nope"},
+		{"Invalid favourites cursor", "/users/1/favourites?after=nope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _ := ExecuteREST(t, http.MethodGet, tt.path, nil)
+			if status != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", status)
+			}
+		})
+	}
+}
+
+// TestPagination_GraphQLCharts tests walking the chart connection page by page
+func TestPagination_GraphQLCharts(t *testing.T) {
+	CleanupTestData()
+
+	ids := seedCharts(t, 3)
+
+	query := `
+		query Charts($first: Int, $after: String) {
+			charts(first: $first, after: $after) {
+				edges { cursor node { id } }
+				pageInfo { hasNextPage endCursor }
+			}
+		}
+	`
+
+	var seen []string
+	variables := map[string]interface{}{"first": 2}
+	for pages := 0; ; pages++ {
+		if pages > len(ids) {
+			t.Fatalf("pagination did not terminate")
+		}
+
+		resp := ExecuteGraphQL(t, query, variables)
+		if len(resp.Errors) > 0 {
+			t.Fatalf("expected no errors, got: %v", resp.Errors)
+		}
+
+		var result struct {
+			Charts struct {
+				Edges []struct {
+					Cursor string `json:"cursor"`
+					Node   struct {
+						ID string `json:"id"`
+					} `json:"node"`
+				} `json:"edges"`
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+			} `json:"charts"`
+		}
+		if err := json.Unmarshal(resp.Data, &result); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+
+		for _, edge := range result.Charts.Edges {
+			if edge.Cursor == "" {
+				t.Errorf("expected edge cursor for chart %s", edge.Node.ID)
+			}
+			seen = append(seen, edge.Node.ID)
+		}
+		if !result.Charts.PageInfo.HasNextPage {
+			break
+		}
+		variables["after"] = result.Charts.PageInfo.EndCursor
+	}
+
+	var want []string
+	for _, id := range ids {
+		want = append(want, fmt.Sprintf("%d", id))
+	}
+	if fmt.Sprint(seen) != fmt.Sprint(want) {
+		t.Errorf("expected charts %v, got %v", want, seen)
+	}
+}
+
+// TestPagination_GraphQLUserStared tests paging through the stars of a user
+func TestPagination_GraphQLUserStared(t *testing.T) {
+	CleanupTestData()
+
+	ids := seedCharts(t, 3)
+	for _, id := range ids {
+		Seed(t, &models.UserStar{UserID: 4, Type: models.AssetTypeChart, AssetID: id})
+	}
+
+	query := `
+		query UserStared($userID: ID!, $first: Int, $after: String) {
+			userstared(userID: $userID, first: $first, after: $after) {
+				stars { assetid }
+				pageInfo { hasNextPage endCursor }
+			}
+		}
+	`
+
+	type page struct {
+		Userstared struct {
+			Stars []struct {
+				Assetid int `json:"assetid"`
+			} `json:"stars"`
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+		} `json:"userstared"`
+	}
+
+	resp := ExecuteGraphQL(t, query, map[string]interface{}{"userID": "4", "first": 2})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+	var first page
+	if err := json.Unmarshal(resp.Data, &first); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(first.Userstared.Stars) != 2 || !first.Userstared.PageInfo.HasNextPage {
+		t.Fatalf("expected 2 stars and a next page, got %+v", first.Userstared)
+	}
+
+	resp = ExecuteGraphQL(t, query, map[string]interface{}{
+		"userID": "4", "first": 2, "after": first.Userstared.PageInfo.EndCursor,
+	})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+	var second page
+	if err := json.Unmarshal(resp.Data, &second); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(second.Userstared.Stars) != 1 || second.Userstared.PageInfo.HasNextPage {
+		t.Fatalf("expected 1 star and no next page, got %+v", second.Userstared)
+	}
+	if second.Userstared.Stars[0].Assetid != int(ids[2]) {
+		t.Errorf("expected last star on chart %d, got %d", ids[2], second.Userstared.Stars[0].Assetid)
+	}
+
+	// Invalid cursors are rejected
+	resp = ExecuteGraphQL(t, query, map[string]interface{}{"userID": "4", "after": "nope"})
+	if len(resp.Errors) == 0 {
+		t.Errorf("expected error for invalid cursor, got none")
+	}
+}
+
+// TestPagination_GraphQLUserStaredEdges tests resuming the stars of a user
+// from the cursor of any edge and asking for an empty page with first: 0
+func TestPagination_GraphQLUserStaredEdges(t *testing.T) {
+	CleanupTestData()
+
+	ids := seedCharts(t, 3)
+	for _, id := range ids {
+		Seed(t, &models.UserStar{UserID: 4, Type: models.AssetTypeChart, AssetID: id})
+	}
+
+	query := `
+		query UserStared($userID: ID!, $first: Int, $after: String) {
+			userstared(userID: $userID, first: $first, after: $after) {
+				edges { cursor node { assetid } }
+				pageInfo { hasNextPage endCursor }
+			}
+		}
+	`
+
+	type page struct {
+		Userstared struct {
+			Edges []struct {
+				Cursor string `json:"cursor"`
+				Node   struct {
+					Assetid int `json:"assetid"`
+				} `json:"node"`
+			} `json:"edges"`
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+		} `json:"userstared"`
+	}
+	fetch := func(variables map[string]interface{}) page {
+		t.Helper()
+		resp := ExecuteGraphQL(t, query, variables)
+		if len(resp.Errors) > 0 {
+			t.Fatalf("expected no errors, got: %v", resp.Errors)
+		}
+		var result page
+		if err := json.Unmarshal(resp.Data, &result); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return result
+	}
+
+	all := fetch(map[string]interface{}{"userID": "4"})
+	if len(all.Userstared.Edges) != 3 {
+		t.Fatalf("expected 3 edges, got %+v", all.Userstared.Edges)
+	}
+
+	// Resuming from the first edge skips only that star
+	rest := fetch(map[string]interface{}{"userID": "4", "after": all.Userstared.Edges[0].Cursor})
+	if len(rest.Userstared.Edges) != 2 || rest.Userstared.Edges[0].Node.Assetid != int(ids[1]) {
+		t.Errorf("expected the stars after the first one, got %+v", rest.Userstared.Edges)
+	}
+
+	// An explicit first: 0 returns no edges but tells whether any follow
+	empty := fetch(map[string]interface{}{"userID": "4", "first": 0})
+	if len(empty.Userstared.Edges) != 0 || !empty.Userstared.PageInfo.HasNextPage {
+		t.Errorf("expected an empty page with a next page, got %+v", empty.Userstared)
+	}
+}
@@ -2,27 +2,36 @@ package e2e
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"platform-go-challenge/api"
 	"platform-go-challenge/db"
 	"platform-go-challenge/graph"
 	"platform-go-challenge/graph/resolvers"
 	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+	"platform-go-challenge/repository/cache"
+	"platform-go-challenge/repository/memory"
 	"testing"
 
-	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 var (
-	testDB     *gorm.DB
+	// testDB is the test database, nil when running in memory
+	testDB *gorm.DB
+	// testStore is the in-memory store, nil when running against a database
+	testStore *memory.Store
+	// testCache caches the favourites read through testRepos
+	testCache  *cache.LRU
+	testRepos  repository.Repositories
 	testRouter *gin.Engine
 	testServer *httptest.Server
 )
@@ -37,52 +46,84 @@ type GraphQLRequest struct {
 type GraphQLResponse struct {
 	Data   json.RawMessage `json:"data"`
 	Errors []struct {
-		Message string `json:"message"`
-		Path    []any  `json:"path,omitempty"`
+		Message    string         `json:"message"`
+		Path       []any          `json:"path,omitempty"`
+		Extensions map[string]any `json:"extensions,omitempty"`
 	} `json:"errors,omitempty"`
 }
 
-// SetupTestDB initializes a test database
-func SetupTestDB() *gorm.DB {
-	testDBURL, isSet := os.LookupEnv("TEST_DB_URL")
-	if !isSet {
-		// Load .env file from project root
-		_ = godotenv.Load("../../.env")
+// SetupTestStorage selects the storage backend of the suite from STORAGE,
+// "db" or "memory". The "db" backend connects to TEST_DB_URL, a Postgres or
+// SQLite URL. When STORAGE is not set the suite runs against the database if
+// TEST_DB_URL is configured and in memory otherwise.
+func SetupTestStorage() {
+	// Load .env file from project root
+	_ = godotenv.Load("../../.env")
+
+	storage := os.Getenv("STORAGE")
+	if storage == "" {
+		storage = "memory"
+		if os.Getenv("TEST_DB_URL") != "" {
+			storage = "db"
+		}
+	}
+
+	switch storage {
+	case "db":
+		testDB = SetupTestDB(os.Getenv("TEST_DB_URL"))
+	case "memory":
+		testStore = memory.NewStore()
+	default:
+		panic(fmt.Sprintf("unknown STORAGE %q: must be db or memory", storage))
+	}
+	testCache = cache.NewLRU(cache.DefaultSize)
+}
 
-		testDBURL = os.Getenv("TEST_DB_URL")
+// NewTestRepositories returns repositories over the test storage that handle
+// deleted assets according to policy. Favourites are read through testCache,
+// like in the application.
+func NewTestRepositories(policy repository.AssetDeletePolicy) repository.Repositories {
+	var repos repository.Repositories
+	if testDB != nil {
+		repos = db.NewRepositories(testDB, policy)
+	} else {
+		repos = testStore.Repositories(policy)
 	}
+	return cache.Wrap(repos, testCache, cache.DefaultTTL)
+}
 
-	database, err := gorm.Open(postgres.Open(testDBURL), &gorm.Config{})
+// SetupTestDB initializes a test database
+func SetupTestDB(testDBURL string) *gorm.DB {
+	database, err := db.Open(testDBURL)
 	if err != nil {
 		panic(fmt.Sprintf("failed to connect to test database: %v", err))
 	}
 
-	// Auto-migrate the schema
-	database.AutoMigrate(
-		&models.Audience{},
-		&models.Chart{},
-		&models.Insight{},
-		&models.UserStar{},
-	)
+	// Apply the migrations
+	if err := db.Migrate(database); err != nil {
+		panic(fmt.Sprintf("failed to migrate test database: %v", err))
+	}
 
 	return database
 }
 
 // SetupTestRouter creates a test Gin router with GraphQL endpoint
-func SetupTestRouter(database *gorm.DB) *gin.Engine {
+func SetupTestRouter(repos repository.Repositories) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 
 	resolver := &resolvers.Resolver{
-		DB: database,
+		Repos: repos,
 	}
 
-	h := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: resolver}))
+	h := graph.NewHandler(resolver)
 
 	router.POST("/graphql", func(c *gin.Context) {
 		h.ServeHTTP(c.Writer, c.Request)
 	})
 
+	api.RegisterRoutes(router, repos)
+
 	return router
 }
 
@@ -121,46 +162,141 @@ func ExecuteGraphQL(t *testing.T, query string, variables map[string]interface{}
 	return &resp
 }
 
-// CleanupTestData removes all data from test tables
-func CleanupTestData(database *gorm.DB) {
-	database.Exec("DELETE FROM user_favourites")
-	database.Exec("DELETE FROM insights")
-	database.Exec("DELETE FROM charts")
-	database.Exec("DELETE FROM audiences")
+// ExecuteREST executes a REST request against the test router and decodes the
+// JSON envelope into a generic map
+func ExecuteREST(t *testing.T, method, path string, body any) (int, map[string]any) {
+	var reader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewBuffer(jsonBody)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	testRouter.ServeHTTP(w, req)
+
+	var resp map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v. Body: %s", err, w.Body.String())
+	}
+
+	return w.Code, resp
+}
+
+// PageItems returns the items of a paginated REST response
+func PageItems(t *testing.T, resp map[string]any) []any {
+	data, ok := resp["data"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected data to be a page, got %T", resp["data"])
+	}
+	items, ok := data["items"].([]any)
+	if !ok {
+		t.Fatalf("expected page items to be a list, got %T", data["items"])
+	}
+	return items
+}
+
+// CleanupTestData removes all data from the test storage
+func CleanupTestData() {
+	// The data is removed behind the repositories, drop what they cached
+	testCache.Purge()
+
+	if testStore != nil {
+		testStore.Reset()
+		return
+	}
+
+	testDB.Exec("DELETE FROM user_stars")
+	testDB.Exec("DELETE FROM asset_links")
+	testDB.Exec("DELETE FROM insights")
+	testDB.Exec("DELETE FROM chart_series")
+	testDB.Exec("DELETE FROM charts")
+	testDB.Exec("DELETE FROM audiences")
+	testDB.Exec("DELETE FROM respondents")
+}
+
+// Seed stores a chart, insight, audience or user star through the test
+// repositories and sets its ID
+func Seed[T any](t *testing.T, record *T) {
+	t.Helper()
+
+	ctx := context.Background()
+	var err error
+	switch r := any(record).(type) {
+	case *models.Audience:
+		err = testRepos.Audiences.Create(ctx, r)
+	case *models.Chart:
+		err = testRepos.Charts.Create(ctx, r)
+	case *models.Insight:
+		err = testRepos.Insights.Create(ctx, r)
+	case *models.UserStar:
+		err = testRepos.Stars.Create(ctx, r)
+	default:
+		t.Fatalf("cannot seed %T", record)
+	}
+	if err != nil {
+		t.Fatalf("failed to seed %T: %v", record, err)
+	}
+}
+
+// AllStars returns every stored user star
+func AllStars(t *testing.T) []models.UserStar {
+	t.Helper()
+
+	var stars []models.UserStar
+	page := models.PageRequest{Limit: models.MaxPageSize}
+	for {
+		result, err := testRepos.Stars.List(context.Background(), page)
+		if err != nil {
+			t.Fatalf("failed to list stars: %v", err)
+		}
+		stars = append(stars, result.Items...)
+		if !result.PageInfo.HasNextPage {
+			return stars
+		}
+		page.After = result.Items[len(result.Items)-1].ID
+	}
 }
 
 // TestMain sets up and tears down the test environment
 func TestMain(m *testing.M) {
 	// Setup
-	testDB = SetupTestDB()
-	db.GormDB = testDB // Set global DB for any code that uses it
-	testRouter = SetupTestRouter(testDB)
+	SetupTestStorage()
+	testRepos = NewTestRepositories(repository.AssetDeleteCascade)
+	testRouter = SetupTestRouter(testRepos)
 
 	// Run tests
 	code := m.Run()
 
 	// Teardown
-	sqlDB, _ := testDB.DB()
-	if sqlDB != nil {
-		sqlDB.Close()
+	if testDB != nil {
+		sqlDB, _ := testDB.DB()
+		if sqlDB != nil {
+			sqlDB.Close()
+		}
 	}
 
 	os.Exit(code)
 }
 
 // SeedTestData creates sample data for testing
-func SeedTestData(t *testing.T, database *gorm.DB) (audienceID, chartID, insightID uint) {
+func SeedTestData(t *testing.T) (audienceID, chartID, insightID uint) {
 	// Create test audience
 	audience := models.Audience{
-		Gender:        "Male",
-		BirthCountry:  "USA",
-		AgeGroup:      "25-34",
-		DailyHours:    5,
-		NoOfPurchases: 10,
-	}
-	if err := database.Create(&audience).Error; err != nil {
-		t.Fatalf("failed to create test audience: %v", err)
+		Criteria: models.AudienceCriteria{
+			Genders:        []string{"Male"},
+			BirthCountries: []string{"USA"},
+			AgeGroups:      []string{"25-34"},
+			DailyHours:     &models.NumericCriterion{Operator: models.OperatorGTE, Value: 5},
+			NoOfPurchases:  &models.NumericCriterion{Operator: models.OperatorGTE, Value: 10},
+		},
 	}
+	Seed(t, &audience)
 
 	// Create test chart
 	chart := models.Chart{
@@ -168,17 +304,13 @@ func SeedTestData(t *testing.T, database *gorm.DB) (audienceID, chartID, insight
 		XAxisTitle: "Month",
 		YAxisTitle: "Revenue",
 	}
-	if err := database.Create(&chart).Error; err != nil {
-		t.Fatalf("failed to create test chart: %v", err)
-	}
+	Seed(t, &chart)
 
 	// Create test insight
 	insight := models.Insight{
 		Text: "Revenue increased by 20% this quarter",
 	}
-	if err := database.Create(&insight).Error; err != nil {
-		t.Fatalf("failed to create test insight: %v", err)
-	}
+	Seed(t, &insight)
 
 	return audience.ID, chart.ID, insight.ID
 }
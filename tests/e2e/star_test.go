@@ -0,0 +1,186 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"platform-go-challenge/models"
+	"testing"
+)
+
+type gqlStarStatus struct {
+	Userid  int          `json:"userid"`
+	Type    string       `json:"type"`
+	Assetid int          `json:"assetid"`
+	Starred bool         `json:"starred"`
+	Star    *gqlUserStar `json:"star"`
+}
+
+// TestStar_RESTIdempotent tests that starring and unstarring through REST can be repeated safely
+func TestStar_RESTIdempotent(t *testing.T) {
+	CleanupTestData()
+
+	_, chartID, _ := SeedTestData(t)
+	path := fmt.Sprintf("/users/1/favourites/chart/%d", chartID)
+
+	// First star creates the row
+	status, resp := ExecuteREST(t, http.MethodPut, path, nil)
+	if status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %v", status, resp["message"])
+	}
+	first := resp["data"].(map[string]any)
+	if first["starred"] != true {
+		t.Errorf("expected starred true, got %v", first["starred"])
+	}
+	firstStarID := first["star"].(map[string]any)["id"]
+
+	// Second star returns the existing row
+	status, resp = ExecuteREST(t, http.MethodPut, path, nil)
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %v", status, resp["message"])
+	}
+	second := resp["data"].(map[string]any)
+	if second["star"].(map[string]any)["id"] != firstStarID {
+		t.Errorf("expected the same star %v, got %v", firstStarID, second["star"])
+	}
+	if count := countStars(t, models.AssetTypeChart, chartID); count != 1 {
+		t.Errorf("expected exactly 1 star, got %d", count)
+	}
+
+	// Unstar twice
+	for i := 0; i < 2; i++ {
+		status, resp = ExecuteREST(t, http.MethodDelete, path, nil)
+		if status != http.StatusOK {
+			t.Fatalf("unstar %d: expected status 200, got %d", i, status)
+		}
+		if resp["data"].(map[string]any)["starred"] != false {
+			t.Errorf("unstar %d: expected starred false, got %v", i, resp["data"])
+		}
+	}
+	if count := countStars(t, models.AssetTypeChart, chartID); count != 0 {
+		t.Errorf("expected no stars, got %d", count)
+	}
+}
+
+// TestStar_RESTValidation tests the path parameter and asset checks of the star endpoint
+func TestStar_RESTValidation(t *testing.T) {
+	CleanupTestData()
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"Invalid user", "/users/abc/favourites/chart/1", http.StatusBadRequest},
+		{"Invalid type", "/users/1/favourites/dashboard/1", http.StatusBadRequest},
+		{"Invalid asset", "/users/1/favourites/chart/abc", http.StatusBadRequest},
+		{"Missing asset", "/users/1/favourites/chart/999999", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _ := ExecuteREST(t, http.MethodPut, tt.path, nil)
+			if status != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, status)
+			}
+		})
+	}
+}
+
+// TestStar_GraphQLIdempotent tests the star and unstar mutations return the current state on repeats
+func TestStar_GraphQLIdempotent(t *testing.T) {
+	CleanupTestData()
+
+	_, _, insightID := SeedTestData(t)
+
+	starMutation := `
+		mutation Star($userID: ID!, $type: String!, $assetID: ID!) {
+			star(userID: $userID, type: $type, assetID: $assetID) {
+				userid type assetid starred
+				star { id }
+			}
+		}
+	`
+	unstarMutation := `
+		mutation Unstar($userID: ID!, $type: String!, $assetID: ID!) {
+			unstar(userID: $userID, type: $type, assetID: $assetID) {
+				userid type assetid starred
+				star { id }
+			}
+		}
+	`
+	variables := map[string]interface{}{
+		"userID":  "5",
+		"type":    "Insight",
+		"assetID": fmt.Sprintf("%d", insightID),
+	}
+
+	var starIDs []string
+	for i := 0; i < 2; i++ {
+		resp := ExecuteGraphQL(t, starMutation, variables)
+		if len(resp.Errors) > 0 {
+			t.Fatalf("star %d: expected no errors, got: %v", i, resp.Errors)
+		}
+
+		var result struct {
+			Star gqlStarStatus `json:"star"`
+		}
+		if err := json.Unmarshal(resp.Data, &result); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if !result.Star.Starred || result.Star.Star == nil {
+			t.Fatalf("star %d: expected starred state with star, got %+v", i, result.Star)
+		}
+		if result.Star.Userid != 5 || result.Star.Type != "Insight" || result.Star.Assetid != int(insightID) {
+			t.Errorf("star %d: unexpected status %+v", i, result.Star)
+		}
+		starIDs = append(starIDs, result.Star.Star.ID)
+	}
+	if starIDs[0] != starIDs[1] {
+		t.Errorf("expected repeated star to return the same star, got %v", starIDs)
+	}
+
+	for i := 0; i < 2; i++ {
+		resp := ExecuteGraphQL(t, unstarMutation, variables)
+		if len(resp.Errors) > 0 {
+			t.Fatalf("unstar %d: expected no errors, got: %v", i, resp.Errors)
+		}
+
+		var result struct {
+			Unstar gqlStarStatus `json:"unstar"`
+		}
+		if err := json.Unmarshal(resp.Data, &result); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if result.Unstar.Starred || result.Unstar.Star != nil {
+			t.Errorf("unstar %d: expected unstarred state, got %+v", i, result.Unstar)
+		}
+	}
+}
+
+// TestStar_DuplicateCreateRejected tests that the non-idempotent create endpoints reject duplicates
+func TestStar_DuplicateCreateRejected(t *testing.T) {
+	CleanupTestData()
+
+	_, chartID, _ := SeedTestData(t)
+	body := map[string]any{"userid": 1, "type": "Chart", "assetid": chartID}
+
+	status, _ := ExecuteREST(t, http.MethodPost, "/userstar", body)
+	if status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", status)
+	}
+
+	status, _ = ExecuteREST(t, http.MethodPost, "/userstar", body)
+	if status != http.StatusConflict {
+		t.Errorf("expected status 409 for duplicate, got %d", status)
+	}
+
+	resp := ExecuteGraphQL(t, createUserStarMutation, map[string]interface{}{"input": body})
+	if len(resp.Errors) == 0 {
+		t.Errorf("expected error for duplicate createUserStar, got none")
+	}
+
+	if count := countStars(t, models.AssetTypeChart, chartID); count != 1 {
+		t.Errorf("expected exactly 1 star, got %d", count)
+	}
+}
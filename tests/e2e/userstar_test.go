@@ -0,0 +1,183 @@
+package e2e
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type gqlUserStar struct {
+	ID      string `json:"id"`
+	Userid  int    `json:"userid"`
+	Type    string `json:"type"`
+	Assetid int    `json:"assetid"`
+}
+
+const createUserStarMutation = `
+	mutation CreateUserStar($input: NewUserStar!) {
+		createUserStar(input: $input) {
+			id
+			userid
+			type
+			assetid
+		}
+	}
+`
+
+// TestUserStar_CRUD tests the full lifecycle of a user star through GraphQL
+func TestUserStar_CRUD(t *testing.T) {
+	CleanupTestData()
+
+	_, chartID, insightID := SeedTestData(t)
+
+	// Create
+	resp := ExecuteGraphQL(t, createUserStarMutation, map[string]interface{}{
+		"input": map[string]interface{}{"userid": 7, "type": "Chart", "assetid": chartID},
+	})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+
+	var created struct {
+		CreateUserStar gqlUserStar `json:"createUserStar"`
+	}
+	if err := json.Unmarshal(resp.Data, &created); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if created.CreateUserStar.Userid != 7 || created.CreateUserStar.Type != "Chart" || created.CreateUserStar.Assetid != int(chartID) {
+		t.Fatalf("unexpected created star: %+v", created.CreateUserStar)
+	}
+	starID := created.CreateUserStar.ID
+
+	// Read single and list
+	resp = ExecuteGraphQL(t, `
+		query GetUserStars($id: ID!) {
+			userstar(id: $id) { id type assetid }
+			userstars { edges { node { id } } }
+		}
+	`, map[string]interface{}{"id": starID})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+
+	var read struct {
+		Userstar  gqlUserStar `json:"userstar"`
+		Userstars struct {
+			Edges []struct {
+				Node gqlUserStar `json:"node"`
+			} `json:"edges"`
+		} `json:"userstars"`
+	}
+	if err := json.Unmarshal(resp.Data, &read); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if read.Userstar.ID != starID || read.Userstar.Type != "Chart" {
+		t.Errorf("unexpected star: %+v", read.Userstar)
+	}
+	if len(read.Userstars.Edges) != 1 {
+		t.Errorf("expected 1 star, got %d", len(read.Userstars.Edges))
+	}
+
+	// Update to point at the insight
+	resp = ExecuteGraphQL(t, `
+		mutation UpdateUserStar($id: ID!, $input: UpdateUserStar!) {
+			updateUserStar(id: $id, input: $input) { id type assetid }
+		}
+	`, map[string]interface{}{
+		"id":    starID,
+		"input": map[string]interface{}{"type": "Insight", "assetid": insightID},
+	})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+
+	var updated struct {
+		UpdateUserStar gqlUserStar `json:"updateUserStar"`
+	}
+	if err := json.Unmarshal(resp.Data, &updated); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if updated.UpdateUserStar.Type != "Insight" || updated.UpdateUserStar.Assetid != int(insightID) {
+		t.Errorf("unexpected updated star: %+v", updated.UpdateUserStar)
+	}
+
+	// Delete
+	resp = ExecuteGraphQL(t, `mutation DeleteUserStar($id: ID!) { deleteUserStar(id: $id) }`,
+		map[string]interface{}{"id": starID})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got: %v", resp.Errors)
+	}
+
+	resp = ExecuteGraphQL(t, `query GetUserStar($id: ID!) { userstar(id: $id) { id } }`,
+		map[string]interface{}{"id": starID})
+	if len(resp.Errors) == 0 {
+		t.Errorf("expected error for deleted star, got none")
+	}
+}
+
+// TestUserStar_CreateValidation tests that invalid stars are rejected with GraphQL errors
+func TestUserStar_CreateValidation(t *testing.T) {
+	CleanupTestData()
+
+	_, chartID, _ := SeedTestData(t)
+
+	tests := []struct {
+		name    string
+		input   map[string]interface{}
+		wantErr string
+	}{
+		{"Invalid type", map[string]interface{}{"userid": 1, "type": "Dashboard", "assetid": chartID}, "invalid asset type"},
+		{"Lowercase type", map[string]interface{}{"userid": 1, "type": "chart", "assetid": chartID}, "invalid asset type"},
+		{"Missing asset", map[string]interface{}{"userid": 1, "type": "Chart", "assetid": 999999}, "not found"},
+		{"Negative user", map[string]interface{}{"userid": -1, "type": "Chart", "assetid": chartID}, "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := ExecuteGraphQL(t, createUserStarMutation, map[string]interface{}{"input": tt.input})
+
+			if len(resp.Errors) == 0 {
+				t.Fatalf("expected error, got none")
+			}
+			if !strings.Contains(resp.Errors[0].Message, tt.wantErr) {
+				t.Errorf("expected error containing %q, got %q", tt.wantErr, resp.Errors[0].Message)
+			}
+		})
+	}
+
+	resp := ExecuteGraphQL(t, `query { userstars { edges { node { id } } } }`, nil)
+	var result struct {
+		Userstars struct {
+			Edges []struct {
+				Node gqlUserStar `json:"node"`
+			} `json:"edges"`
+		} `json:"userstars"`
+	}
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(result.Userstars.Edges) != 0 {
+		t.Errorf("expected no stars to be created, got %d", len(result.Userstars.Edges))
+	}
+}
+
+// TestUserStar_UpdateNotFound tests updating a star that does not exist
+func TestUserStar_UpdateNotFound(t *testing.T) {
+	CleanupTestData()
+
+	resp := ExecuteGraphQL(t, `
+		mutation UpdateUserStar($id: ID!, $input: UpdateUserStar!) {
+			updateUserStar(id: $id, input: $input) { id }
+		}
+	`, map[string]interface{}{
+		"id":    "123456",
+		"input": map[string]interface{}{"userid": 1},
+	})
+
+	if len(resp.Errors) == 0 {
+		t.Fatalf("expected error, got none")
+	}
+	if resp.Errors[0].Message != "userstar not found" {
+		t.Errorf("expected 'userstar not found', got %q", resp.Errors[0].Message)
+	}
+}
@@ -3,17 +3,14 @@ package e2e
 import (
 	"encoding/json"
 	"platform-go-challenge/models"
+	"slices"
 	"testing"
 )
 
 // GraphQL response types (IDs are strings in GraphQL)
 type gqlAudience struct {
-	ID            string `json:"id"`
-	Gender        string `json:"gender"`
-	Birthcountry  string `json:"birthcountry"`
-	Agegroup      string `json:"agegroup"`
-	Dailyhours    int    `json:"dailyhours"`
-	Noofpurchases int    `json:"noofpurchases"`
+	ID       string                  `json:"id"`
+	Criteria models.AudienceCriteria `json:"criteria"`
 }
 
 type gqlChart struct {
@@ -30,7 +27,7 @@ type gqlInsight struct {
 
 // TestUserStared_EmptyFavourites tests when user has no favourites
 func TestUserStared_EmptyFavourites(t *testing.T) {
-	CleanupTestData(testDB)
+	CleanupTestData()
 
 	query := `
 		query GetUserStared($userID: ID!) {
@@ -38,7 +35,7 @@ func TestUserStared_EmptyFavourites(t *testing.T) {
 				userid
 				audience {
 					id
-					gender
+					criteria { genders }
 				}
 				chart {
 					id
@@ -94,10 +91,10 @@ func TestUserStared_EmptyFavourites(t *testing.T) {
 
 // TestUserStared_WithFavourites tests fetching user favourites with all asset types
 func TestUserStared_WithFavourites(t *testing.T) {
-	CleanupTestData(testDB)
+	CleanupTestData()
 
 	// Seed test data
-	audienceID, chartID, insightID := SeedTestData(t, testDB)
+	audienceID, chartID, insightID := SeedTestData(t)
 
 	// Create favourites for user 1
 	favourites := []models.UserStar{
@@ -107,9 +104,7 @@ func TestUserStared_WithFavourites(t *testing.T) {
 	}
 
 	for _, fav := range favourites {
-		if err := testDB.Create(&fav).Error; err != nil {
-			t.Fatalf("failed to create favourite: %v", err)
-		}
+		Seed(t, &fav)
 	}
 
 	query := `
@@ -118,11 +113,13 @@ func TestUserStared_WithFavourites(t *testing.T) {
 				userid
 				audience {
 					id
-					gender
-					birthcountry
-					agegroup
-					dailyhours
-					noofpurchases
+					criteria {
+						genders
+						birthcountries
+						agegroups
+						dailyhours { operator value }
+						noofpurchases { operator value }
+					}
 				}
 				chart {
 					id
@@ -170,11 +167,15 @@ func TestUserStared_WithFavourites(t *testing.T) {
 	if len(result.Userstars.Audience) != 1 {
 		t.Fatalf("expected 1 audience, got %d", len(result.Userstars.Audience))
 	}
-	if result.Userstars.Audience[0].Gender != "Male" {
-		t.Errorf("expected gender 'Male', got '%s'", result.Userstars.Audience[0].Gender)
+	criteria := result.Userstars.Audience[0].Criteria
+	if !slices.Equal(criteria.Genders, []string{"Male"}) {
+		t.Errorf("expected genders [Male], got %v", criteria.Genders)
+	}
+	if !slices.Equal(criteria.BirthCountries, []string{"USA"}) {
+		t.Errorf("expected birthcountries [USA], got %v", criteria.BirthCountries)
 	}
-	if result.Userstars.Audience[0].Birthcountry != "USA" {
-		t.Errorf("expected birthcountry 'USA', got '%s'", result.Userstars.Audience[0].Birthcountry)
+	if criteria.DailyHours == nil || *criteria.DailyHours != (models.NumericCriterion{Operator: models.OperatorGTE, Value: 5}) {
+		t.Errorf("expected dailyhours GTE 5, got %+v", criteria.DailyHours)
 	}
 
 	// Verify charts
@@ -196,30 +197,34 @@ func TestUserStared_WithFavourites(t *testing.T) {
 
 // TestUserStared_MultipleFavouritesOfSameType tests multiple favourites of the same type
 func TestUserStared_MultipleFavouritesOfSameType(t *testing.T) {
-	CleanupTestData(testDB)
+	CleanupTestData()
 
 	// Create multiple audiences
 	audience1 := models.Audience{
-		Gender:        "Male",
-		BirthCountry:  "USA",
-		AgeGroup:      "25-34",
-		DailyHours:    5,
-		NoOfPurchases: 10,
+		Criteria: models.AudienceCriteria{
+			Genders:        []string{"Male"},
+			BirthCountries: []string{"USA"},
+			AgeGroups:      []string{"25-34"},
+			DailyHours:     &models.NumericCriterion{Operator: models.OperatorGTE, Value: 5},
+			NoOfPurchases:  &models.NumericCriterion{Operator: models.OperatorGTE, Value: 10},
+		},
 	}
-	testDB.Create(&audience1)
+	Seed(t, &audience1)
 
 	audience2 := models.Audience{
-		Gender:        "Female",
-		BirthCountry:  "Canada",
-		AgeGroup:      "35-44",
-		DailyHours:    3,
-		NoOfPurchases: 7,
+		Criteria: models.AudienceCriteria{
+			Genders:        []string{"Female"},
+			BirthCountries: []string{"CAN"},
+			AgeGroups:      []string{"35-44"},
+			DailyHours:     &models.NumericCriterion{Operator: models.OperatorGTE, Value: 3},
+			NoOfPurchases:  &models.NumericCriterion{Operator: models.OperatorGTE, Value: 7},
+		},
 	}
-	testDB.Create(&audience2)
+	Seed(t, &audience2)
 
 	// Create favourites
-	testDB.Create(&models.UserStar{UserID: 2, Type: models.AssetTypeAudience, AssetID: audience1.ID})
-	testDB.Create(&models.UserStar{UserID: 2, Type: models.AssetTypeAudience, AssetID: audience2.ID})
+	Seed(t, &models.UserStar{UserID: 2, Type: models.AssetTypeAudience, AssetID: audience1.ID})
+	Seed(t, &models.UserStar{UserID: 2, Type: models.AssetTypeAudience, AssetID: audience2.ID})
 
 	query := `
 		query GetUserStared($userID: ID!) {
@@ -227,8 +232,7 @@ func TestUserStared_MultipleFavouritesOfSameType(t *testing.T) {
 				userid
 				audience {
 					id
-					gender
-					birthcountry
+					criteria { genders birthcountries }
 				}
 			}
 		}
@@ -262,7 +266,9 @@ func TestUserStared_MultipleFavouritesOfSameType(t *testing.T) {
 	// Verify both audiences are returned
 	genders := make(map[string]bool)
 	for _, aud := range result.Userstars.Audience {
-		genders[aud.Gender] = true
+		for _, gender := range aud.Criteria.Genders {
+			genders[gender] = true
+		}
 	}
 
 	if !genders["Male"] || !genders["Female"] {
@@ -272,16 +278,16 @@ func TestUserStared_MultipleFavouritesOfSameType(t *testing.T) {
 
 // TestUserStared_OnlySpecificUser tests that only the requested user's favourites are returned
 func TestUserStared_OnlySpecificUser(t *testing.T) {
-	CleanupTestData(testDB)
+	CleanupTestData()
 
 	// Create test data
-	audienceID, chartID, _ := SeedTestData(t, testDB)
+	audienceID, chartID, _ := SeedTestData(t)
 
 	// Create favourites for user 1
-	testDB.Create(&models.UserStar{UserID: 1, Type: models.AssetTypeAudience, AssetID: audienceID})
+	Seed(t, &models.UserStar{UserID: 1, Type: models.AssetTypeAudience, AssetID: audienceID})
 
 	// Create favourites for user 2
-	testDB.Create(&models.UserStar{UserID: 2, Type: models.AssetTypeChart, AssetID: chartID})
+	Seed(t, &models.UserStar{UserID: 2, Type: models.AssetTypeChart, AssetID: chartID})
 
 	query := `
 		query GetUserStared($userID: ID!) {
@@ -0,0 +1,115 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// sortedFields returns the invalid field names of an error response in order
+func sortedFields(t *testing.T, value any) []string {
+	names := fieldNames(t, value)
+	sort.Strings(names)
+	return names
+}
+
+// TestValidation_SameRulesForRESTAndGraphQL tests that REST and GraphQL reject invalid assets with the same field errors
+func TestValidation_SameRulesForRESTAndGraphQL(t *testing.T) {
+	CleanupTestData()
+
+	audienceID, chartID, insightID := SeedTestData(t)
+	longText := strings.Repeat("a", 10001)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       map[string]any
+		mutation   string
+		variables  map[string]any
+		wantFields []string
+	}{
+		{
+			name:   "Audience with unknown values",
+			method: http.MethodPost, path: "/audience",
+			body: map[string]any{"criteria": map[string]any{
+				"genders": []string{"Robot"}, "birthcountries": []string{"GR", "Atlantis"}, "agegroups": []string{"20-30"},
+				"dailyhours": map[string]any{"operator": "GT", "value": 25}, "noofpurchases": map[string]any{"operator": "LT", "value": -1},
+			}},
+			mutation: `mutation { createAudience(input: {criteria: {genders: ["Robot"], birthcountries: ["GR", "Atlantis"], agegroups: ["20-30"],
+				dailyhours: {operator: GT, value: 25}, noofpurchases: {operator: LT, value: -1}}}) { id } }`,
+			wantFields: []string{"criteria.agegroups[0]", "criteria.birthcountries[1]", "criteria.dailyhours.value", "criteria.genders[0]", "criteria.noofpurchases.value"},
+		},
+		{
+			name:   "Audience update with inverted range",
+			method: http.MethodPut, path: fmt.Sprintf("/audience/%d", audienceID),
+			body:       map[string]any{"criteria": map[string]any{"genders": []string{"Male", "Male"}, "dailyhours": map[string]any{"operator": "BETWEEN", "value": 5, "upper": 2}}},
+			mutation:   `mutation Update($id: ID!) { updateAudience(id: $id, input: {criteria: {genders: ["Male", "Male"], dailyhours: {operator: BETWEEN, value: 5, upper: 2}}}) { id } }`,
+			variables:  map[string]any{"id": fmt.Sprint(audienceID)},
+			wantFields: []string{"criteria.dailyhours.upper", "criteria.genders[1]"},
+		},
+		{
+			name:   "Audience with invalid expression",
+			method: http.MethodPost, path: "/audience",
+			body:       map[string]any{"expression": "gender = Male AND"},
+			mutation:   `mutation { createAudience(input: {expression: "gender = Male AND"}) { id } }`,
+			wantFields: []string{"expression"},
+		},
+		{
+			name:   "Empty insight",
+			method: http.MethodPost, path: "/insight",
+			body:       map[string]any{"text": ""},
+			mutation:   `mutation { createInsight(input: {text: ""}) { id } }`,
+			wantFields: []string{"text"},
+		},
+		{
+			name:   "Insight too long",
+			method: http.MethodPut, path: fmt.Sprintf("/insight/%d", insightID),
+			body:       map[string]any{"text": longText},
+			mutation:   `mutation Update($id: ID!, $text: String) { updateInsight(id: $id, input: {text: $text}) { id } }`,
+			variables:  map[string]any{"id": fmt.Sprint(insightID), "text": longText},
+			wantFields: []string{"text"},
+		},
+		{
+			name:   "Chart without title",
+			method: http.MethodPost, path: "/chart",
+			body:       map[string]any{"title": "", "xaxistitle": "X", "yaxistitle": "Y"},
+			mutation:   `mutation { createChart(input: {title: "", xaxistitle: "X", yaxistitle: "Y"}) { id } }`,
+			wantFields: []string{"title"},
+		},
+		{
+			name:   "Chart with unnamed series",
+			method: http.MethodPut, path: fmt.Sprintf("/chart/%d", chartID),
+			body:       map[string]any{"labels": []string{"Q1"}, "series": []map[string]any{{"name": "", "points": []float64{1}}}},
+			mutation:   `mutation Update($id: ID!) { updateChart(id: $id, input: {labels: ["Q1"], series: [{name: "", points: [1]}]}) { id } }`,
+			variables:  map[string]any{"id": fmt.Sprint(chartID)},
+			wantFields: []string{"series[0].name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := ExecuteREST(t, tt.method, tt.path, tt.body)
+			if status != http.StatusBadRequest {
+				t.Errorf("REST: expected status 400, got %d: %v", status, resp["message"])
+			}
+			if got := sortedFields(t, resp["errors"]); fmt.Sprint(got) != fmt.Sprint(tt.wantFields) {
+				t.Errorf("REST: expected invalid fields %v, got %v", tt.wantFields, got)
+			}
+
+			gqlResp := ExecuteGraphQL(t, tt.mutation, tt.variables)
+			if len(gqlResp.Errors) != 1 {
+				t.Fatalf("GraphQL: expected one error, got %v", gqlResp.Errors)
+			}
+			extensions := gqlResp.Errors[0].Extensions
+			if extensions["code"] != "VALIDATION_FAILED" {
+				t.Errorf("GraphQL: expected code VALIDATION_FAILED, got %v", extensions["code"])
+			}
+			if got := sortedFields(t, extensions["fields"]); fmt.Sprint(got) != fmt.Sprint(tt.wantFields) {
+				t.Errorf("GraphQL: expected invalid fields %v, got %v", tt.wantFields, got)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package performance
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,17 +12,21 @@ import (
 	"platform-go-challenge/graph"
 	"platform-go-challenge/graph/resolvers"
 	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+	"platform-go-challenge/repository/memory"
 	"testing"
 
-	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 var (
-	benchDB     *gorm.DB
+	// benchDB is the benchmark database, nil when running in memory
+	benchDB *gorm.DB
+	// benchStore is the in-memory store, nil when running against a database
+	benchStore  *memory.Store
+	benchRepos  repository.Repositories
 	benchRouter *gin.Engine
 )
 
@@ -30,39 +35,55 @@ type GraphQLRequest struct {
 	Variables map[string]interface{} `json:"variables,omitempty"`
 }
 
-func setupBenchDB() *gorm.DB {
-	testDBURL, isSet := os.LookupEnv("TEST_DB_URL")
-	if !isSet {
-		// Load .env file from project root
-		_ = godotenv.Load("../../.env")
+// setupBench selects the storage backend like the e2e suite: STORAGE picks
+// "db" or "memory", and without it the database at TEST_DB_URL is used if set
+func setupBench() {
+	// Load .env file from project root
+	_ = godotenv.Load("../../.env")
+
+	storage := os.Getenv("STORAGE")
+	if storage == "" {
+		storage = "memory"
+		if os.Getenv("TEST_DB_URL") != "" {
+			storage = "db"
+		}
+	}
 
-		testDBURL = os.Getenv("TEST_DB_URL")
+	switch storage {
+	case "db":
+		benchDB = setupBenchDB(os.Getenv("TEST_DB_URL"))
+		benchRepos = db.NewRepositories(benchDB, repository.AssetDeleteCascade)
+	case "memory":
+		benchStore = memory.NewStore()
+		benchRepos = benchStore.Repositories(repository.AssetDeleteCascade)
+	default:
+		panic(fmt.Sprintf("unknown STORAGE %q: must be db or memory", storage))
 	}
+	benchRouter = setupBenchRouter(benchRepos)
+}
 
-	database, err := gorm.Open(postgres.Open(testDBURL), &gorm.Config{})
+func setupBenchDB(testDBURL string) *gorm.DB {
+	database, err := db.Open(testDBURL)
 	if err != nil {
 		panic(fmt.Sprintf("failed to connect to benchmark database: %v", err))
 	}
 
-	database.AutoMigrate(
-		&models.Audience{},
-		&models.Chart{},
-		&models.Insight{},
-		&models.UserStar{},
-	)
+	if err := db.Migrate(database); err != nil {
+		panic(fmt.Sprintf("failed to migrate benchmark database: %v", err))
+	}
 
 	return database
 }
 
-func setupBenchRouter(database *gorm.DB) *gin.Engine {
+func setupBenchRouter(repos repository.Repositories) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 
 	resolver := &resolvers.Resolver{
-		DB: database,
+		Repos: repos,
 	}
 
-	h := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: resolver}))
+	h := graph.NewHandler(resolver)
 
 	router.POST("/graphql", func(c *gin.Context) {
 		h.ServeHTTP(c.Writer, c.Request)
@@ -71,24 +92,41 @@ func setupBenchRouter(database *gorm.DB) *gin.Engine {
 	return router
 }
 
-func seedBenchmarkData(database *gorm.DB, numUsers, itemsPerUser int) {
+// cleanBenchData removes all data from the benchmark storage
+func cleanBenchData() {
+	if benchStore != nil {
+		benchStore.Reset()
+		return
+	}
+
+	benchDB.Exec("DELETE FROM user_stars")
+	benchDB.Exec("DELETE FROM insights")
+	benchDB.Exec("DELETE FROM chart_series")
+	benchDB.Exec("DELETE FROM charts")
+	benchDB.Exec("DELETE FROM audiences")
+}
+
+func seedBenchmarkData(b *testing.B, numUsers, itemsPerUser int) {
+	ctx := context.Background()
+
 	// Clean existing data
-	database.Exec("DELETE FROM user_favourites")
-	database.Exec("DELETE FROM insights")
-	database.Exec("DELETE FROM charts")
-	database.Exec("DELETE FROM audiences")
+	cleanBenchData()
 
 	// Create a pool of assets
 	audiences := make([]models.Audience, itemsPerUser)
 	for i := 0; i < itemsPerUser; i++ {
 		audiences[i] = models.Audience{
-			Gender:        "Male",
-			BirthCountry:  "USA",
-			AgeGroup:      "25-34",
-			DailyHours:    5,
-			NoOfPurchases: 10,
+			Criteria: models.AudienceCriteria{
+				Genders:        []string{"Male"},
+				BirthCountries: []string{"USA"},
+				AgeGroups:      []string{"25-34"},
+				DailyHours:     &models.NumericCriterion{Operator: models.OperatorGTE, Value: 5},
+				NoOfPurchases:  &models.NumericCriterion{Operator: models.OperatorGTE, Value: 10},
+			},
+		}
+		if err := benchRepos.Audiences.Create(ctx, &audiences[i]); err != nil {
+			b.Fatalf("failed to create audience: %v", err)
 		}
-		database.Create(&audiences[i])
 	}
 
 	charts := make([]models.Chart, itemsPerUser)
@@ -98,7 +136,9 @@ func seedBenchmarkData(database *gorm.DB, numUsers, itemsPerUser int) {
 			XAxisTitle: "X",
 			YAxisTitle: "Y",
 		}
-		database.Create(&charts[i])
+		if err := benchRepos.Charts.Create(ctx, &charts[i]); err != nil {
+			b.Fatalf("failed to create chart: %v", err)
+		}
 	}
 
 	insights := make([]models.Insight, itemsPerUser)
@@ -106,27 +146,24 @@ func seedBenchmarkData(database *gorm.DB, numUsers, itemsPerUser int) {
 		insights[i] = models.Insight{
 			Text: fmt.Sprintf("Insight %d", i),
 		}
-		database.Create(&insights[i])
+		if err := benchRepos.Insights.Create(ctx, &insights[i]); err != nil {
+			b.Fatalf("failed to create insight: %v", err)
+		}
 	}
 
 	// Create favourites for each user
 	for userID := 1; userID <= numUsers; userID++ {
 		for i := 0; i < itemsPerUser; i++ {
-			database.Create(&models.UserStar{
-				UserID:  uint(userID),
-				Type: models.AssetTypeAudience,
-				AssetID: audiences[i].ID,
-			})
-			database.Create(&models.UserStar{
-				UserID:  uint(userID),
-				Type: models.AssetTypeChart,
-				AssetID: charts[i].ID,
-			})
-			database.Create(&models.UserStar{
-				UserID:  uint(userID),
-				Type: models.AssetTypeInsight,
-				AssetID: insights[i].ID,
-			})
+			stars := []models.UserStar{
+				{UserID: uint(userID), Type: models.AssetTypeAudience, AssetID: audiences[i].ID},
+				{UserID: uint(userID), Type: models.AssetTypeChart, AssetID: charts[i].ID},
+				{UserID: uint(userID), Type: models.AssetTypeInsight, AssetID: insights[i].ID},
+			}
+			for j := range stars {
+				if err := benchRepos.Stars.Create(ctx, &stars[j]); err != nil {
+					b.Fatalf("failed to create star: %v", err)
+				}
+			}
 		}
 	}
 }
@@ -156,13 +193,11 @@ func executeGraphQLBench(b *testing.B, query string, variables map[string]interf
 
 // BenchmarkUserStared_SmallDataset benchmarks with 5 items per user
 func BenchmarkUserStared_SmallDataset(b *testing.B) {
-	if benchDB == nil {
-		benchDB = setupBenchDB()
-		db.GormDB = benchDB
-		benchRouter = setupBenchRouter(benchDB)
+	if benchRouter == nil {
+		setupBench()
 	}
 
-	seedBenchmarkData(benchDB, 10, 5)
+	seedBenchmarkData(b, 10, 5)
 
 	query := `
 		query GetUserStared($userID: ID!) {
@@ -170,11 +205,13 @@ func BenchmarkUserStared_SmallDataset(b *testing.B) {
 				userid
 				audience {
 					id
-					gender
-					birthcountry
-					agegroup
-					dailyhours
-					noofpurchases
+					criteria {
+						genders
+						birthcountries
+						agegroups
+						dailyhours { operator value }
+						noofpurchases { operator value }
+					}
 				}
 				chart {
 					id
@@ -202,19 +239,17 @@ func BenchmarkUserStared_SmallDataset(b *testing.B) {
 
 // BenchmarkUserStared_MediumDataset benchmarks with 50 items per user
 func BenchmarkUserStared_MediumDataset(b *testing.B) {
-	if benchDB == nil {
-		benchDB = setupBenchDB()
-		db.GormDB = benchDB
-		benchRouter = setupBenchRouter(benchDB)
+	if benchRouter == nil {
+		setupBench()
 	}
 
-	seedBenchmarkData(benchDB, 10, 50)
+	seedBenchmarkData(b, 10, 50)
 
 	query := `
 		query GetUserStared($userID: ID!) {
 			userstared(userID: $userID) {
 				userid
-				audience { id gender }
+				audience { id criteria { genders } }
 				chart { id title }
 				insight { id text }
 			}
@@ -233,19 +268,17 @@ func BenchmarkUserStared_MediumDataset(b *testing.B) {
 
 // BenchmarkUserStared_LargeDataset benchmarks with 200 items per user
 func BenchmarkUserStared_LargeDataset(b *testing.B) {
-	if benchDB == nil {
-		benchDB = setupBenchDB()
-		db.GormDB = benchDB
-		benchRouter = setupBenchRouter(benchDB)
+	if benchRouter == nil {
+		setupBench()
 	}
 
-	seedBenchmarkData(benchDB, 10, 200)
+	seedBenchmarkData(b, 10, 200)
 
 	query := `
 		query GetUserStared($userID: ID!) {
 			userstared(userID: $userID) {
 				userid
-				audience { id gender }
+				audience { id criteria { genders } }
 				chart { id title }
 				insight { id text }
 			}
@@ -264,13 +297,11 @@ func BenchmarkUserStared_LargeDataset(b *testing.B) {
 
 // BenchmarkUserStared_MinimalFields benchmarks with minimal field selection
 func BenchmarkUserStared_MinimalFields(b *testing.B) {
-	if benchDB == nil {
-		benchDB = setupBenchDB()
-		db.GormDB = benchDB
-		benchRouter = setupBenchRouter(benchDB)
+	if benchRouter == nil {
+		setupBench()
 	}
 
-	seedBenchmarkData(benchDB, 10, 50)
+	seedBenchmarkData(b, 10, 50)
 
 	query := `
 		query GetUserStared($userID: ID!) {
@@ -295,13 +326,11 @@ func BenchmarkUserStared_MinimalFields(b *testing.B) {
 
 // BenchmarkUserStared_AllFields benchmarks with all fields selected
 func BenchmarkUserStared_AllFields(b *testing.B) {
-	if benchDB == nil {
-		benchDB = setupBenchDB()
-		db.GormDB = benchDB
-		benchRouter = setupBenchRouter(benchDB)
+	if benchRouter == nil {
+		setupBench()
 	}
 
-	seedBenchmarkData(benchDB, 10, 50)
+	seedBenchmarkData(b, 10, 50)
 
 	query := `
 		query GetUserStared($userID: ID!) {
@@ -309,11 +338,13 @@ func BenchmarkUserStared_AllFields(b *testing.B) {
 				userid
 				audience {
 					id
-					gender
-					birthcountry
-					agegroup
-					dailyhours
-					noofpurchases
+					criteria {
+						genders
+						birthcountries
+						agegroups
+						dailyhours { operator value }
+						noofpurchases { operator value }
+					}
 				}
 				chart {
 					id
@@ -341,19 +372,17 @@ func BenchmarkUserStared_AllFields(b *testing.B) {
 
 // BenchmarkUserStared_Parallel benchmarks concurrent requests
 func BenchmarkUserStared_Parallel(b *testing.B) {
-	if benchDB == nil {
-		benchDB = setupBenchDB()
-		db.GormDB = benchDB
-		benchRouter = setupBenchRouter(benchDB)
+	if benchRouter == nil {
+		setupBench()
 	}
 
-	seedBenchmarkData(benchDB, 100, 50)
+	seedBenchmarkData(b, 100, 50)
 
 	query := `
 		query GetUserStared($userID: ID!) {
 			userstared(userID: $userID) {
 				userid
-				audience { id gender }
+				audience { id criteria { genders } }
 				chart { id title }
 				insight { id text }
 			}
@@ -0,0 +1,48 @@
+package unit
+
+import (
+	"platform-go-challenge/models"
+	"testing"
+	"time"
+)
+
+func TestAsset_Type(t *testing.T) {
+	tests := []struct {
+		name  string
+		asset models.Asset
+		want  models.AssetType
+		id    uint
+	}{
+		{"Audience", models.Audience{ID: 1}, models.AssetTypeAudience, 1},
+		{"Chart", models.Chart{ID: 2}, models.AssetTypeChart, 2},
+		{"Insight", models.Insight{ID: 3}, models.AssetTypeInsight, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.asset.AssetType(); got != tt.want {
+				t.Errorf("AssetType() = %v, want %v", got, tt.want)
+			}
+			if got := tt.asset.AssetID(); got != tt.id {
+				t.Errorf("AssetID() = %v, want %v", got, tt.id)
+			}
+		})
+	}
+}
+
+func TestStarred(t *testing.T) {
+	starredAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var unstarred models.Chart
+	if unstarred.Description() != nil || unstarred.StarredAt() != nil {
+		t.Errorf("expected nil star fields on an unstarred asset")
+	}
+
+	starred := models.Chart{Starred: models.Starred{Star: &models.UserStar{Description: "Mine", CreatedAt: starredAt}}}
+	if d := starred.Description(); d == nil || *d != "Mine" {
+		t.Errorf("Description() = %v, want %q", d, "Mine")
+	}
+	if at := starred.StarredAt(); at == nil || !at.Equal(starredAt) {
+		t.Errorf("StarredAt() = %v, want %v", at, starredAt)
+	}
+}
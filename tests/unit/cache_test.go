@@ -0,0 +1,190 @@
+package unit
+
+import (
+	"context"
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+	"platform-go-challenge/repository/cache"
+	"platform-go-challenge/repository/memory"
+	"testing"
+	"time"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	lru := cache.NewLRU(2)
+
+	lru.Set(ctx, "a", []byte("1"), 0)
+	lru.Set(ctx, "b", []byte("2"), 0)
+	lru.Get(ctx, "a")
+	lru.Set(ctx, "c", []byte("3"), 0)
+
+	if _, found, _ := lru.Get(ctx, "b"); found {
+		t.Errorf("expected least recently used key b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, found, _ := lru.Get(ctx, key); !found {
+			t.Errorf("expected key %s to be cached", key)
+		}
+	}
+	if lru.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", lru.Len())
+	}
+}
+
+func TestLRU_ExpiresAndDeletes(t *testing.T) {
+	ctx := context.Background()
+	lru := cache.NewLRU(10)
+
+	lru.Set(ctx, "short", []byte("1"), time.Millisecond)
+	lru.Set(ctx, "forever", []byte("2"), 0)
+	lru.Set(ctx, "deleted", []byte("3"), 0)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, found, _ := lru.Get(ctx, "short"); found {
+		t.Errorf("expected expired key to be missing")
+	}
+	lru.Delete(ctx, "deleted", "missing")
+	if _, found, _ := lru.Get(ctx, "deleted"); found {
+		t.Errorf("expected deleted key to be missing")
+	}
+	if value, found, _ := lru.Get(ctx, "forever"); !found || string(value) != "2" {
+		t.Errorf("expected key without ttl to be cached, got %q, %v", value, found)
+	}
+}
+
+func TestCacheFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantNil bool
+		wantTTL time.Duration
+		wantErr bool
+	}{
+		{"Defaults", map[string]string{}, false, cache.DefaultTTL, false},
+		{"LRU with TTL", map[string]string{"CACHE": "lru", "CACHE_SIZE": "10", "CACHE_TTL": "30s"}, false, 30 * time.Second, false},
+		{"Disabled", map[string]string{"CACHE": "none"}, true, 0, false},
+		{"Unknown backend", map[string]string{"CACHE": "memcached"}, true, 0, true},
+		{"Invalid size", map[string]string{"CACHE_SIZE": "0"}, true, 0, true},
+		{"Invalid TTL", map[string]string{"CACHE_TTL": "soon"}, true, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"CACHE", "CACHE_SIZE", "CACHE_TTL"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			got, ttl, err := cache.FromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got == nil) != tt.wantNil {
+				t.Errorf("FromEnv() cache = %v, wantNil %v", got, tt.wantNil)
+			}
+			if ttl != tt.wantTTL {
+				t.Errorf("FromEnv() ttl = %v, want %v", ttl, tt.wantTTL)
+			}
+		})
+	}
+}
+
+// countingFavourites counts the loads reaching the wrapped repository
+type countingFavourites struct {
+	repository.FavouriteRepository
+	loads int
+	// afterLoad runs after each load, if set
+	afterLoad func()
+}
+
+func (c *countingFavourites) AllByUser(ctx context.Context, userID uint) ([]models.Favourite, error) {
+	c.loads++
+	favourites, err := c.FavouriteRepository.AllByUser(ctx, userID)
+	if c.afterLoad != nil {
+		c.afterLoad()
+	}
+	return favourites, err
+}
+
+// newCachedRepositories returns in-memory repositories with cached favourites
+// and the counter of the loads that missed the cache
+func newCachedRepositories(t *testing.T) (repository.Repositories, *countingFavourites, models.Chart) {
+	repos := memory.NewRepositories(repository.AssetDeleteCascade)
+	counter := &countingFavourites{FavouriteRepository: repos.Favourites}
+	repos.Favourites = counter
+	repos = cache.Wrap(repos, cache.NewLRU(100), time.Minute)
+
+	chart := models.Chart{Title: "Chart"}
+	if err := repos.Charts.Create(context.Background(), &chart); err != nil {
+		t.Fatalf("failed to create chart: %v", err)
+	}
+	return repos, counter, chart
+}
+
+func TestCachedFavourites_ReadThrough(t *testing.T) {
+	ctx := context.Background()
+	repos, counter, chart := newCachedRepositories(t)
+
+	if _, _, err := repos.Stars.Star(ctx, 1, models.AssetTypeChart, chart.ID); err != nil {
+		t.Fatalf("Star() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		favourites, err := repos.Favourites.AllByUser(ctx, 1)
+		if err != nil {
+			t.Fatalf("AllByUser() error = %v", err)
+		}
+		if len(favourites) != 1 || favourites[0].Asset.AssetID() != chart.ID {
+			t.Fatalf("expected chart %d as favourite, got %+v", chart.ID, favourites)
+		}
+		if favourites[0].Asset.(models.Chart).Star == nil {
+			t.Errorf("expected cached asset to carry its star")
+		}
+	}
+	if counter.loads != 1 {
+		t.Errorf("expected 1 load, got %d", counter.loads)
+	}
+
+	// Pages are served from the cached favourites too
+	page, err := repos.Favourites.ListByUser(ctx, 1, models.PageRequest{Limit: 10})
+	if err != nil || len(page.Items) != 1 || page.PageInfo.HasNextPage {
+		t.Errorf("expected a single page with 1 favourite, got %+v, %v", page, err)
+	}
+	if counter.loads != 1 {
+		t.Errorf("expected page to be served from the cache, got %d loads", counter.loads)
+	}
+
+	// Other users are unaffected by the writes of a user
+	repos.Favourites.AllByUser(ctx, 2)
+	repos.Stars.Unstar(ctx, 1, models.AssetTypeChart, chart.ID)
+	repos.Favourites.AllByUser(ctx, 2)
+	if counter.loads != 2 {
+		t.Errorf("expected user 2 to stay cached, got %d loads", counter.loads)
+	}
+}
+
+func TestCachedFavourites_InvalidationDuringLoad(t *testing.T) {
+	ctx := context.Background()
+	repos, counter, chart := newCachedRepositories(t)
+
+	// The user stars the chart after the favourites were loaded but before
+	// they are cached
+	counter.afterLoad = func() {
+		counter.afterLoad = nil
+		repos.Stars.Star(ctx, 1, models.AssetTypeChart, chart.ID)
+	}
+	if favourites, _ := repos.Favourites.AllByUser(ctx, 1); len(favourites) != 0 {
+		t.Fatalf("expected the racing load to return no favourites, got %d", len(favourites))
+	}
+
+	favourites, err := repos.Favourites.AllByUser(ctx, 1)
+	if err != nil {
+		t.Fatalf("AllByUser() error = %v", err)
+	}
+	if len(favourites) != 1 {
+		t.Errorf("expected the stale load not to be served, got %d favourites", len(favourites))
+	}
+	if counter.loads != 2 {
+		t.Errorf("expected 2 loads, got %d", counter.loads)
+	}
+}
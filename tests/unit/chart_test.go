@@ -0,0 +1,91 @@
+package unit
+
+import (
+	"platform-go-challenge/models"
+	"slices"
+	"testing"
+)
+
+func TestChart_Validate(t *testing.T) {
+	labels := []string{"Q1", "Q2", "Q3"}
+
+	tests := []struct {
+		name    string
+		chart   models.Chart
+		wantErr bool
+	}{
+		{"No series", models.Chart{Title: "Sales", Labels: labels}, false},
+		{"No labels or series", models.Chart{Title: "Sales"}, false},
+		{"Matching series", models.Chart{Title: "Sales", Labels: labels, Series: []models.ChartSeries{
+			{Name: "2024", Points: []float64{1, 2, 3}},
+			{Name: "2025", Points: []float64{4, 5, 6}},
+		}}, false},
+		{"Too few points", models.Chart{Title: "Sales", Labels: labels, Series: []models.ChartSeries{
+			{Name: "2024", Points: []float64{1, 2}},
+		}}, true},
+		{"Too many points", models.Chart{Title: "Sales", Labels: labels, Series: []models.ChartSeries{
+			{Name: "2024", Points: []float64{1, 2, 3, 4}},
+		}}, true},
+		{"Series without labels", models.Chart{Title: "Sales", Series: []models.ChartSeries{
+			{Name: "2024", Points: []float64{1}},
+		}}, true},
+		{"Unnamed series", models.Chart{Title: "Sales", Labels: labels, Series: []models.ChartSeries{
+			{Points: []float64{1, 2, 3}},
+		}}, true},
+		{"Duplicate series", models.Chart{Title: "Sales", Labels: labels, Series: []models.ChartSeries{
+			{Name: "2024", Points: []float64{1, 2, 3}},
+			{Name: "2024", Points: []float64{4, 5, 6}},
+		}}, true},
+		{"Missing title", models.Chart{Labels: labels}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.chart.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Chart.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewAudienceChart(t *testing.T) {
+	chart := models.NewAudienceChart(3, models.DimensionAgeGroup, map[string]int64{"18-24": 1, "65+": 3})
+
+	if chart.Title != "Audience 3 by age group" || chart.XAxisTitle != "Age group" {
+		t.Errorf("unexpected titles %q and %q", chart.Title, chart.XAxisTitle)
+	}
+	wantLabels := []string{"18-24", "25-34", "35-44", "45-54", "55-64", "65+"}
+	if !slices.Equal(chart.Labels, wantLabels) {
+		t.Errorf("expected every age group in order, got %v", chart.Labels)
+	}
+	if len(chart.Series) != 2 ||
+		!slices.Equal(chart.Series[0].Points, []float64{1, 0, 0, 0, 0, 3}) ||
+		!slices.Equal(chart.Series[1].Points, []float64{25, 0, 0, 0, 0, 75}) {
+		t.Errorf("expected respondents and shares per age group, got %+v", chart.Series)
+	}
+	if err := chart.Validate(); err != nil {
+		t.Errorf("expected a valid chart, got %v", err)
+	}
+
+	// Birth countries are listed when present, the most frequent first
+	chart = models.NewAudienceChart(3, models.DimensionBirthCountry, map[string]int64{"GR": 2, "CY": 5, "US": 2})
+	if !slices.Equal(chart.Labels, []string{"CY", "GR", "US"}) {
+		t.Errorf("expected countries by frequency, got %v", chart.Labels)
+	}
+
+	// An empty audience has no share
+	chart = models.NewAudienceChart(3, models.DimensionGender, nil)
+	if !slices.Equal(chart.Series[1].Points, []float64{0, 0, 0}) {
+		t.Errorf("expected no share of an empty audience, got %v", chart.Series[1].Points)
+	}
+}
+
+func TestParseDimension(t *testing.T) {
+	if dimension, err := models.ParseDimension("birth_country"); err != nil || dimension != models.DimensionBirthCountry {
+		t.Errorf("expected BIRTH_COUNTRY, got %q, %v", dimension, err)
+	}
+	if _, err := models.ParseDimension("income"); err == nil {
+		t.Error("expected an unknown dimension to be rejected")
+	}
+}
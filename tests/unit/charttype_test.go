@@ -0,0 +1,149 @@
+package unit
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"platform-go-challenge/models"
+)
+
+func TestChartType_Value(t *testing.T) {
+	tests := []struct {
+		name      string
+		chartType models.ChartType
+		wantValue string
+		wantErr   bool
+	}{
+		{"Valid bar", models.ChartTypeBar, "BAR", false},
+		{"Valid stacked bar", models.ChartTypeStackedBar, "STACKED_BAR", false},
+		{"Unset type is a bar chart", "", "BAR", false},
+		{"Invalid type", models.ChartType("DONUT"), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.chartType.Value()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChartType.Value() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.wantValue {
+				t.Errorf("ChartType.Value() = %v, want %v", got, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestChartType_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    models.ChartType
+		wantErr bool
+	}{
+		{"Valid string", "PIE", models.ChartTypePie, false},
+		{"Valid bytes", []byte("SCATTER"), models.ChartTypeScatter, false},
+		{"Invalid string", "DONUT", "", true},
+		{"Nil value", nil, "", true},
+		{"Invalid type", 123, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ct models.ChartType
+			err := ct.Scan(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChartType.Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && ct != tt.want {
+				t.Errorf("ChartType.Scan() = %v, want %v", ct, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseChartType(t *testing.T) {
+	if ct, err := models.ParseChartType("stacked_bar"); err != nil || ct != models.ChartTypeStackedBar {
+		t.Errorf("expected STACKED_BAR ignoring case, got %q, %v", ct, err)
+	}
+	if _, err := models.ParseChartType("donut"); err == nil {
+		t.Error("expected an error for an unknown chart type")
+	}
+}
+
+func TestChart_ValidateType(t *testing.T) {
+	labels := []string{"Q1", "Q2"}
+	twoSeries := []models.ChartSeries{
+		{Name: "2024", Points: []float64{1, 2}},
+		{Name: "2025", Points: []float64{3, 4}},
+	}
+	stacks := func(stacks ...models.ChartStack) models.ChartConfig {
+		return models.ChartConfig{Stacks: stacks}
+	}
+
+	tests := []struct {
+		name       string
+		chart      models.Chart
+		wantFields []string
+	}{
+		{"Unset type", models.Chart{Title: "Sales", XAxisTitle: "Quarter", Labels: labels, Series: twoSeries}, nil},
+		{"Lowercase type", models.Chart{Title: "Sales", ChartType: "line", Labels: labels, Series: twoSeries}, nil},
+		{"Unknown type", models.Chart{Title: "Sales", ChartType: "DONUT"}, []string{"charttype"}},
+		{"Pie", models.Chart{Title: "Share", ChartType: models.ChartTypePie, Labels: labels, Series: twoSeries[:1]}, nil},
+		{"Pie with axes", models.Chart{Title: "Share", ChartType: models.ChartTypePie, XAxisTitle: "Quarter", YAxisTitle: "Sales"},
+			[]string{"xaxistitle", "yaxistitle"}},
+		{"Pie with two series", models.Chart{Title: "Share", ChartType: models.ChartTypePie, Labels: labels, Series: twoSeries},
+			[]string{"series"}},
+		{"Pie with a negative slice", models.Chart{Title: "Share", ChartType: models.ChartTypePie, Labels: labels, Series: []models.ChartSeries{
+			{Name: "2024", Points: []float64{1, -2}},
+		}}, []string{"series[0].points"}},
+		{"Stacked bar", models.Chart{Title: "Sales", ChartType: models.ChartTypeStackedBar, Labels: labels, Series: twoSeries,
+			Config: stacks(models.ChartStack{Name: "Total", Series: []string{"2024", "2025"}})}, nil},
+		{"Stacked bar without stacks", models.Chart{Title: "Sales", ChartType: models.ChartTypeStackedBar, Labels: labels, Series: twoSeries},
+			[]string{"series[0]", "series[1]"}},
+		{"Stacked bar with invalid stacks", models.Chart{Title: "Sales", ChartType: models.ChartTypeStackedBar, Labels: labels, Series: twoSeries,
+			Config: stacks(
+				models.ChartStack{Name: "A", Series: []string{"2024", "2023"}},
+				models.ChartStack{Name: "A", Series: []string{"2024", "2025"}},
+			)}, []string{"config.stacks[0].series[1]", "config.stacks[1].name", "config.stacks[1].series[0]"}},
+		{"Stacks of a bar chart", models.Chart{Title: "Sales", Labels: labels, Series: twoSeries,
+			Config: stacks(models.ChartStack{Name: "Total", Series: []string{"2024", "2025"}})}, []string{"config.stacks"}},
+		{"Scatter", models.Chart{Title: "Spend", ChartType: models.ChartTypeScatter, Labels: []string{"1", "2.5"}, Series: twoSeries}, nil},
+		{"Scatter with categories", models.Chart{Title: "Spend", ChartType: models.ChartTypeScatter, Labels: labels, Series: twoSeries},
+			[]string{"labels[0]", "labels[1]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.chart.Validate()
+			var fields []string
+			if err != nil {
+				for _, field := range models.AsError(err).Fields {
+					fields = append(fields, field.Field)
+				}
+			}
+			if !slices.Equal(fields, tt.wantFields) {
+				t.Errorf("Chart.Validate() invalid fields = %v, want %v (%v)", fields, tt.wantFields, err)
+			}
+		})
+	}
+
+	// The type is rewritten in canonical form, and defaults to a bar chart
+	chart := models.Chart{Title: "Sales", ChartType: "stacked_bar"}
+	if err := chart.Validate(); err != nil || chart.ChartType != models.ChartTypeStackedBar {
+		t.Errorf("expected STACKED_BAR, got %q, %v", chart.ChartType, err)
+	}
+	chart = models.Chart{Title: "Sales"}
+	if err := chart.Validate(); err != nil || chart.ChartType != models.ChartTypeBar {
+		t.Errorf("expected BAR, got %q, %v", chart.ChartType, err)
+	}
+}
+
+func TestChartConfig_MarshalJSON(t *testing.T) {
+	encoded, err := json.Marshal(models.ChartConfig{})
+	if err != nil || string(encoded) != `{"stacks":[]}` {
+		t.Errorf("expected missing stacks as an empty list, got %s, %v", encoded, err)
+	}
+}
@@ -0,0 +1,61 @@
+package unit
+
+import (
+	"os"
+	"platform-go-challenge/repository"
+	"testing"
+)
+
+func TestParseAssetDeletePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    repository.AssetDeletePolicy
+		wantErr bool
+	}{
+		{"Cascade", "cascade", repository.AssetDeleteCascade, false},
+		{"Restrict", "restrict", repository.AssetDeleteRestrict, false},
+		{"Empty", "", "", true},
+		{"Unknown", "ignore", "", true},
+		{"Uppercase", "CASCADE", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repository.ParseAssetDeletePolicy(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseAssetDeletePolicy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseAssetDeletePolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssetDeletePolicyFromEnv(t *testing.T) {
+	t.Run("Unset", func(t *testing.T) {
+		t.Setenv("ASSET_DELETE_POLICY", "")
+		os.Unsetenv("ASSET_DELETE_POLICY")
+		got, err := repository.AssetDeletePolicyFromEnv()
+		if err != nil || got != repository.AssetDeleteCascade {
+			t.Errorf("AssetDeletePolicyFromEnv() = %v, %v, want %v", got, err, repository.AssetDeleteCascade)
+		}
+	})
+
+	t.Run("Restrict", func(t *testing.T) {
+		t.Setenv("ASSET_DELETE_POLICY", "restrict")
+		got, err := repository.AssetDeletePolicyFromEnv()
+		if err != nil || got != repository.AssetDeleteRestrict {
+			t.Errorf("AssetDeletePolicyFromEnv() = %v, %v, want %v", got, err, repository.AssetDeleteRestrict)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Setenv("ASSET_DELETE_POLICY", "ignore")
+		if _, err := repository.AssetDeletePolicyFromEnv(); err == nil {
+			t.Errorf("AssetDeletePolicyFromEnv() expected error, got none")
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"platform-go-challenge/api/model"
+	"platform-go-challenge/graph"
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+	"testing"
+)
+
+func TestError_IsMatchesDerivedErrors(t *testing.T) {
+	derived := repository.ErrNotFound.Withf("chart not found")
+	wrapped := fmt.Errorf("failed to load chart: %w", derived)
+
+	if !errors.Is(wrapped, repository.ErrNotFound) {
+		t.Errorf("expected %v to match ErrNotFound", wrapped)
+	}
+	if errors.Is(wrapped, repository.ErrStarNotFound) {
+		t.Errorf("expected %v not to match ErrStarNotFound", wrapped)
+	}
+	if repository.ErrNotFound.Message != "record not found" {
+		t.Errorf("expected Withf to leave the sentinel unchanged, got %q", repository.ErrNotFound.Message)
+	}
+}
+
+func TestAsError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantKind    models.ErrorKind
+		wantCode    string
+		wantMessage string
+	}{
+		{"Domain error", repository.ErrDuplicateStar, models.ErrorConflict, "DUPLICATE_STAR", "user has already starred this asset"},
+		{"Wrapped domain error", fmt.Errorf("star: %w", repository.ErrAssetNotFound),
+			models.ErrorUnprocessable, "ASSET_NOT_FOUND", "star: asset not found"},
+		{"Unexpected error", errors.New("connection refused"), models.ErrorInternal, models.CodeInternal, "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := models.AsError(tt.err)
+			if got.Kind != tt.wantKind || got.Code != tt.wantCode || got.Message != tt.wantMessage {
+				t.Errorf("AsError() = %+v, want kind %s, code %s, message %q", got, tt.wantKind, tt.wantCode, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestValidationError_Fields(t *testing.T) {
+	err := models.ValidationError(
+		models.FieldError{Field: "title", Message: "is required"},
+		models.FieldError{Field: "labels", Message: "must not be empty"},
+	)
+	if err.Code != models.CodeValidationFailed || len(err.Fields) != 2 {
+		t.Fatalf("unexpected validation error %+v", err)
+	}
+	want := "invalid input: title: is required; labels: must not be empty"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestStatusFor(t *testing.T) {
+	tests := []struct {
+		kind models.ErrorKind
+		want int
+	}{
+		{models.ErrorNotFound, http.StatusNotFound},
+		{models.ErrorValidation, http.StatusBadRequest},
+		{models.ErrorUnprocessable, http.StatusUnprocessableEntity},
+		{models.ErrorConflict, http.StatusConflict},
+		{models.ErrorForbidden, http.StatusForbidden},
+		{models.ErrorInternal, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			if got := model.StatusFor(tt.kind); got != tt.want {
+				t.Errorf("StatusFor(%s) = %d, want %d", tt.kind, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorPresenter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Domain error", func(t *testing.T) {
+		presented := graph.ErrorPresenter(ctx, models.InvalidField("limit", "must not be negative"))
+		if presented.Extensions["code"] != models.CodeValidationFailed {
+			t.Errorf("expected code %s, got %v", models.CodeValidationFailed, presented.Extensions["code"])
+		}
+		fields, ok := presented.Extensions["fields"].([]models.FieldError)
+		if !ok || len(fields) != 1 || fields[0].Field != "limit" {
+			t.Errorf("expected the limit field, got %v", presented.Extensions["fields"])
+		}
+	})
+
+	t.Run("Unexpected error", func(t *testing.T) {
+		presented := graph.ErrorPresenter(ctx, errors.New("dial tcp: connection refused"))
+		if presented.Extensions["code"] != models.CodeInternal {
+			t.Errorf("expected code %s, got %v", models.CodeInternal, presented.Extensions["code"])
+		}
+		if presented.Message != "internal server error" {
+			t.Errorf("expected details to be hidden, got %q", presented.Message)
+		}
+	})
+}
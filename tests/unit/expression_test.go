@@ -0,0 +1,200 @@
+package unit
+
+import (
+	"errors"
+	"platform-go-challenge/models"
+	"testing"
+)
+
+func TestParseExpression_CanonicalForm(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			"Example",
+			"gender = Male AND (age_group IN [25-34, 35-44]) AND social_hours > 3 AND NOT birth_country = US",
+			"gender = Male AND age_group IN [25-34, 35-44] AND social_hours > 3 AND NOT birth_country = US",
+		},
+		{"Case and spacing", "gender=male  and   birth_country in [gr,cyp]", "gender = Male AND birth_country IN [GR, CY]"},
+		{"Alpha-3 duplicates", "birth_country IN [GRC, GR, UNK]", "birth_country IN [GR, XK]"},
+		{"Precedence", "gender = Male OR gender = Female AND purchases >= 1", "gender = Male OR gender = Female AND purchases >= 1"},
+		{"Needed parentheses", "(gender = Male OR gender = Female) AND NOT (purchases < 1 OR social_hours = 0)", "(gender = Male OR gender = Female) AND NOT (purchases < 1 OR social_hours = 0)"},
+		{"Nested groups", "((gender = Male AND age_group = 65+) AND purchases != 2.50)", "gender = Male AND age_group = 65+ AND purchases != 2.5"},
+		{"Aliases", "daily_hours <= 4 AND no_of_purchases = 0", "social_hours <= 4 AND purchases = 0"},
+		{"Duplicate values", "age_group IN [18-24, 18-24]", "age_group IN [18-24]"},
+		{"Keyword as value", "birth_country = in", "birth_country = IN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := models.ParseExpression(tt.source)
+			if err != nil {
+				t.Fatalf("ParseExpression() error = %v", err)
+			}
+			if got := expression.String(); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+
+			// the canonical form parses to itself
+			reparsed, err := models.ParseExpression(expression.String())
+			if err != nil || reparsed.String() != tt.want {
+				t.Errorf("expected the canonical form to be stable, got %v, %v", reparsed, err)
+			}
+		})
+	}
+}
+
+func TestParseExpression_Errors(t *testing.T) {
+	tests := []struct {
+		source       string
+		wantPosition int
+	}{
+		{"", 1},
+		{"x = 1", 1},
+		{"gender = Robot", 10},
+		{"gender > Male", 8},
+		{"purchases IN [1]", 11},
+		{"social_hours > 25", 16},
+		{"purchases >= -1", 14},
+		{"purchases = many", 13},
+		{"social_hours = NaN", 16},
+		{"purchases > Inf", 13},
+		{"purchases > +Inf", 13},
+		{"social_hours >= 1_0", 17},
+		{"purchases = 0x10", 13},
+		{"purchases = 1e2", 13},
+		{"social_hours = .5", 16},
+		{"social_hours = 5.", 16},
+		{"purchases = +1", 13},
+		{"birth_country = Greece", 17},
+		{"(gender = Male", 15},
+		{"age_group IN [25-34", 20},
+		{"gender = Male AND", 18},
+		{"gender = Male gender = Female", 15},
+		{"gender ! Male", 8},
+		{"gender = 'Male'", 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			_, err := models.ParseExpression(tt.source)
+			var syntaxErr *models.ExpressionSyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected a syntax error, got %v", err)
+			}
+			if syntaxErr.Position != tt.wantPosition {
+				t.Errorf("expected the error at position %d, got %v", tt.wantPosition, err)
+			}
+		})
+	}
+}
+
+func TestExpression_Matches(t *testing.T) {
+	expression, err := models.ParseExpression("gender = Male AND age_group IN [25-34, 35-44] AND social_hours > 3 AND NOT birth_country = US")
+	if err != nil {
+		t.Fatalf("ParseExpression() error = %v", err)
+	}
+	match := models.Profile{Gender: "Male", BirthCountry: "GR", AgeGroup: "35-44", DailyHours: 3.5}
+
+	tests := []struct {
+		name    string
+		change  func(*models.Profile)
+		matches bool
+	}{
+		{"Match", func(p *models.Profile) {}, true},
+		{"Other gender", func(p *models.Profile) { p.Gender = "Female" }, false},
+		{"Other age group", func(p *models.Profile) { p.AgeGroup = "18-24" }, false},
+		{"Boundary hours", func(p *models.Profile) { p.DailyHours = 3 }, false},
+		{"Excluded country", func(p *models.Profile) { p.BirthCountry = "us" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := match
+			tt.change(&profile)
+			if got := expression.Matches(profile); got != tt.matches {
+				t.Errorf("Matches(%+v) = %v, want %v", profile, got, tt.matches)
+			}
+		})
+	}
+}
+
+func TestDescribeExpression(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{
+			"gender = Male AND age_group IN [25-34, 35-44] AND social_hours > 3 AND NOT birth_country = US",
+			"People who are Male, are aged 25-34 or 35-44, spend more than 3 hours a day on social media and were not born in US",
+		},
+		{"purchases = 1", "People who made exactly 1 purchase last month"},
+		{"NOT social_hours > 3", "People who spend at most 3 hours a day on social media"},
+		{
+			"gender = Female OR NOT (age_group = 18-24 OR purchases >= 2)",
+			"People who are Female or (are not aged 18-24 and made less than 2 purchases last month)",
+		},
+	}
+
+	for _, tt := range tests {
+		expression, err := models.ParseExpression(tt.source)
+		if err != nil {
+			t.Fatalf("ParseExpression(%q) error = %v", tt.source, err)
+		}
+		if got := models.DescribeExpression(expression); got != tt.want {
+			t.Errorf("DescribeExpression(%q) = %q, want %q", tt.source, got, tt.want)
+		}
+	}
+
+	if got := models.DescribeExpression(nil); got != "Everyone" {
+		t.Errorf("expected nil to describe everyone, got %q", got)
+	}
+}
+
+func TestAudience_Definition(t *testing.T) {
+	upper := 5.0
+	audience := models.Audience{
+		Criteria: models.AudienceCriteria{
+			Genders:    []string{"Male", "Female"},
+			AgeGroups:  []string{"18-24"},
+			DailyHours: &models.NumericCriterion{Operator: models.OperatorBetween, Value: 1, Upper: &upper},
+		},
+		Expression: "NOT birth_country = US",
+	}
+
+	definition, err := audience.Definition()
+	if err != nil {
+		t.Fatalf("Definition() error = %v", err)
+	}
+	want := "gender IN [Male, Female] AND age_group = 18-24 AND social_hours >= 1 AND social_hours <= 5 AND NOT birth_country = US"
+	if got := definition.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	summary, err := audience.Summary()
+	if err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+	wantSummary := "People who are Male or Female, are aged 18-24, spend at least 1 hour a day on social media, " +
+		"spend at most 5 hours a day on social media and were not born in US"
+	if summary != wantSummary {
+		t.Errorf("expected summary %q, got %q", wantSummary, summary)
+	}
+}
+
+func TestAudience_ValidateExpression(t *testing.T) {
+	audience := models.Audience{Expression: " gender=female  or purchases>2 "}
+	if err := audience.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if audience.Expression != "gender = Female OR purchases > 2" {
+		t.Errorf("expected the expression in canonical form, got %q", audience.Expression)
+	}
+
+	audience = models.Audience{Expression: "gender = Robot"}
+	if got := invalidFields(t, audience.Validate()); len(got) != 1 || got[0] != "expression" {
+		t.Errorf("expected an invalid expression, got %v", got)
+	}
+}
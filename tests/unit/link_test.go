@@ -0,0 +1,138 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+)
+
+func TestAssetLink_Validate(t *testing.T) {
+	tests := []struct {
+		source, target models.AssetType
+		wantErr        bool
+	}{
+		{models.AssetTypeAudience, models.AssetTypeChart, false},
+		{models.AssetTypeAudience, models.AssetTypeInsight, false},
+		{models.AssetTypeChart, models.AssetTypeInsight, false},
+		{models.AssetTypeChart, models.AssetTypeAudience, true},
+		{models.AssetTypeInsight, models.AssetTypeChart, true},
+		{models.AssetTypeAudience, models.AssetTypeAudience, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.source)+" to "+string(tt.target), func(t *testing.T) {
+			link := models.AssetLink{SourceType: tt.source, SourceID: 1, TargetType: tt.target, TargetID: 2}
+			err := link.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AssetLink.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewAssetRef(t *testing.T) {
+	ref := models.NewAssetRef(models.AssetTypeAudience, 12)
+	if ref.Href != "/audience/12" || ref.Type != models.AssetTypeAudience || ref.ID != 12 {
+		t.Errorf("unexpected reference %+v", ref)
+	}
+}
+
+func TestLinkRepository(t *testing.T) {
+	ctx := context.Background()
+
+	for name, repos := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			audience := models.Audience{}
+			chart := models.Chart{Title: "Chart"}
+			insight := models.Insight{Text: "Insight"}
+			if err := repos.Audiences.Create(ctx, &audience); err != nil {
+				t.Fatalf("failed to create audience: %v", err)
+			}
+			if err := repos.Charts.Create(ctx, &chart); err != nil {
+				t.Fatalf("failed to create chart: %v", err)
+			}
+			if err := repos.Insights.Create(ctx, &insight); err != nil {
+				t.Fatalf("failed to create insight: %v", err)
+			}
+
+			fromChart := models.AssetLink{SourceType: models.AssetTypeChart, SourceID: chart.ID, TargetType: models.AssetTypeInsight, TargetID: insight.ID}
+			fromAudience := models.AssetLink{SourceType: models.AssetTypeAudience, SourceID: audience.ID, TargetType: models.AssetTypeInsight, TargetID: insight.ID}
+			for _, link := range []models.AssetLink{fromChart, fromAudience} {
+				if created, err := repos.Links.Link(ctx, link); err != nil || !created {
+					t.Fatalf("expected link %+v to be created, got %v, %v", link, created, err)
+				}
+			}
+			if created, err := repos.Links.Link(ctx, fromChart); err != nil || created {
+				t.Errorf("expected linking twice to be idempotent, got %v, %v", created, err)
+			}
+
+			// Sources are loaded in the order they were linked
+			sources, err := repos.LoadSources(ctx, models.AssetTypeInsight, insight.ID)
+			if err != nil {
+				t.Fatalf("failed to load sources: %v", err)
+			}
+			if len(sources) != 2 || sources[0].AssetType() != models.AssetTypeChart || sources[1].AssetType() != models.AssetTypeAudience {
+				t.Errorf("expected the chart then the audience, got %+v", sources)
+			}
+			insights, err := repository.LoadTargets(ctx, repos.Links, repos.Insights, models.AssetTypeChart, chart.ID)
+			if err != nil || len(insights) != 1 || insights[0].ID != insight.ID {
+				t.Errorf("expected the insight of the chart, got %+v, %v", insights, err)
+			}
+
+			// Both ends must exist
+			missing := fromChart
+			missing.SourceID = 42
+			if _, err := repos.Links.Link(ctx, missing); !errors.Is(err, repository.ErrAssetNotFound) {
+				t.Errorf("expected ErrAssetNotFound for a missing source, got %v", err)
+			}
+			reversed := models.AssetLink{SourceType: models.AssetTypeInsight, SourceID: insight.ID, TargetType: models.AssetTypeChart, TargetID: chart.ID}
+			if _, err := repos.Links.Link(ctx, reversed); models.AsError(err).Kind != models.ErrorValidation {
+				t.Errorf("expected a validation error for an insight backing a chart, got %v", err)
+			}
+
+			if removed, err := repos.Links.Unlink(ctx, fromAudience); err != nil || !removed {
+				t.Errorf("expected the link to be removed, got %v, %v", removed, err)
+			}
+			if removed, err := repos.Links.Unlink(ctx, fromAudience); err != nil || removed {
+				t.Errorf("expected unlinking twice to be idempotent, got %v, %v", removed, err)
+			}
+
+			// Deleting an asset removes its links
+			if err := repos.Charts.Delete(ctx, chart.ID); err != nil {
+				t.Fatalf("failed to delete chart: %v", err)
+			}
+			links, err := repos.Links.Sources(ctx, models.AssetTypeInsight, []uint{insight.ID})
+			if err != nil || len(links) != 0 {
+				t.Errorf("expected no sources left, got %+v, %v", links, err)
+			}
+		})
+	}
+}
+
+func TestChartGenerator_LinksAudience(t *testing.T) {
+	ctx := context.Background()
+
+	for name, repos := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := repos.Respondents.Replace(ctx, sampleRespondents()); err != nil {
+				t.Fatalf("failed to load respondents: %v", err)
+			}
+			audience := models.Audience{}
+			if err := repos.Audiences.Create(ctx, &audience); err != nil {
+				t.Fatalf("failed to create audience: %v", err)
+			}
+
+			chart, err := repos.ChartGenerator.Generate(ctx, audience.ID, models.DimensionGender)
+			if err != nil {
+				t.Fatalf("failed to generate chart: %v", err)
+			}
+			charts, err := repository.LoadTargets(ctx, repos.Links, repos.Charts, models.AssetTypeAudience, audience.ID)
+			if err != nil || len(charts) != 1 || charts[0].ID != chart.ID {
+				t.Errorf("expected the generated chart to be linked to its audience, got %+v, %v", charts, err)
+			}
+		})
+	}
+}
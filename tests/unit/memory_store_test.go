@@ -0,0 +1,96 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+	"platform-go-challenge/repository/memory"
+	"sync"
+	"testing"
+)
+
+func TestMemoryStore_ConcurrentStar(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories(repository.AssetDeleteCascade)
+
+	chart := models.Chart{Title: "Chart"}
+	if err := repos.Charts.Create(ctx, &chart); err != nil {
+		t.Fatalf("failed to create chart: %v", err)
+	}
+
+	const workers = 50
+	var wg sync.WaitGroup
+	created := make(chan bool, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, isNew, err := repos.Stars.Star(ctx, 1, models.AssetTypeChart, chart.ID)
+			if err != nil {
+				t.Errorf("Star() error = %v", err)
+				return
+			}
+			created <- isNew
+		}()
+	}
+	wg.Wait()
+	close(created)
+
+	newStars := 0
+	for isNew := range created {
+		if isNew {
+			newStars++
+		}
+	}
+	if newStars != 1 {
+		t.Errorf("expected exactly one new star, got %d", newStars)
+	}
+
+	stars, err := repos.Stars.AllByUser(ctx, 1)
+	if err != nil {
+		t.Fatalf("AllByUser() error = %v", err)
+	}
+	if len(stars) != 1 {
+		t.Errorf("expected 1 stored star, got %d", len(stars))
+	}
+}
+
+func TestMemoryStore_ReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories(repository.AssetDeleteCascade)
+
+	chart := models.Chart{Title: "Original", Labels: []string{"x", "y"}, Series: []models.ChartSeries{{Name: "A", Points: []float64{1, 2}}}}
+	if err := repos.Charts.Create(ctx, &chart); err != nil {
+		t.Fatalf("failed to create chart: %v", err)
+	}
+
+	// Mutating the caller's value must not change the stored chart
+	chart.Title = "Changed"
+	chart.Series[0].Points[0] = 100
+
+	stored, err := repos.Charts.Get(ctx, chart.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if stored.Title != "Original" || stored.Series[0].Points[0] != 1 {
+		t.Errorf("expected stored chart to be unchanged, got %+v", stored)
+	}
+}
+
+func TestMemoryStore_Reset(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	repos := store.Repositories(repository.AssetDeleteCascade)
+
+	insight := models.Insight{Text: "Insight"}
+	if err := repos.Insights.Create(ctx, &insight); err != nil {
+		t.Fatalf("failed to create insight: %v", err)
+	}
+
+	store.Reset()
+
+	if _, err := repos.Insights.Get(ctx, insight.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after Reset, got %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"platform-go-challenge/db"
+	"platform-go-challenge/models"
+	"slices"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// newMigrator returns a Migrator over a fresh in-memory SQLite database
+func newMigrator(t *testing.T) (*gorm.DB, *db.Migrator) {
+	database, err := db.Open("sqlite::memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	migrator, err := db.NewMigrator(database)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	return database, migrator
+}
+
+func TestMigrator_UpAndDown(t *testing.T) {
+	ctx := context.Background()
+	database, migrator := newMigrator(t)
+
+	if err := migrator.Check(ctx); !errors.Is(err, db.ErrSchemaOutdated) {
+		t.Fatalf("expected ErrSchemaOutdated before migrating, got %v", err)
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if len(applied) == 0 || applied[len(applied)-1].Version != migrator.Latest() {
+		t.Fatalf("expected migrations up to %d to be applied, got %+v", migrator.Latest(), applied)
+	}
+	if err := migrator.Check(ctx); err != nil {
+		t.Errorf("Check() after Up error = %v", err)
+	}
+	if !database.Migrator().HasTable(&models.UserStar{}) {
+		t.Errorf("expected user_stars table to exist")
+	}
+
+	// Up is a no-op once everything is applied
+	applied, err = migrator.Up(ctx)
+	if err != nil || len(applied) != 0 {
+		t.Errorf("expected second Up() to apply nothing, got %+v, %v", applied, err)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied || status.Modified || status.AppliedAt.IsZero() {
+			t.Errorf("expected migration %d to be applied unmodified, got %+v", status.Version, status)
+		}
+	}
+
+	reverted, err := migrator.Down(ctx)
+	if err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+	if len(reverted) != 1 || reverted[0].Version != migrator.Latest() {
+		t.Errorf("expected Down() to revert migration %d, got %+v", migrator.Latest(), reverted)
+	}
+	if err := migrator.Check(ctx); !errors.Is(err, db.ErrSchemaOutdated) {
+		t.Errorf("expected ErrSchemaOutdated after Down, got %v", err)
+	}
+}
+
+func TestMigrator_To(t *testing.T) {
+	ctx := context.Background()
+	database, migrator := newMigrator(t)
+
+	if _, err := migrator.To(ctx, migrator.Latest()); err != nil {
+		t.Fatalf("To(latest) error = %v", err)
+	}
+
+	if _, err := migrator.To(ctx, 0); err != nil {
+		t.Fatalf("To(0) error = %v", err)
+	}
+	if database.Migrator().HasTable(&models.Chart{}) {
+		t.Errorf("expected charts table to be dropped by To(0)")
+	}
+
+	if _, err := migrator.To(ctx, migrator.Latest()+1); err == nil {
+		t.Errorf("expected error migrating to an unknown version")
+	}
+}
+
+func TestMigrator_DetectsDrift(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  string
+		wantErr error
+	}{
+		{"Modified migration", "UPDATE schema_migrations SET checksum = 'edited'", db.ErrChecksumMismatch},
+		{"Unknown migration", "INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (9999, 'future', 'x', CURRENT_TIMESTAMP)", db.ErrUnknownMigration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			database, migrator := newMigrator(t)
+			if _, err := migrator.Up(ctx); err != nil {
+				t.Fatalf("Up() error = %v", err)
+			}
+
+			if err := database.Exec(tt.tamper).Error; err != nil {
+				t.Fatalf("failed to tamper with schema_migrations: %v", err)
+			}
+
+			if err := migrator.Check(ctx); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Check() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := migrator.Up(ctx); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Up() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// autoMigratedChart is the chart model of the former AutoMigrate-based
+// setup, before later migrations added columns
+type autoMigratedChart struct {
+	ID         uint `gorm:"primaryKey"`
+	Title      string
+	XAxisTitle string
+	YAxisTitle string
+	Labels     []string             `gorm:"serializer:json"`
+	Series     []models.ChartSeries `gorm:"foreignKey:ChartID;constraint:OnDelete:CASCADE"`
+}
+
+func (autoMigratedChart) TableName() string { return "charts" }
+
+func TestMigrator_AdoptsAutoMigratedDatabase(t *testing.T) {
+	ctx := context.Background()
+	database, migrator := newMigrator(t)
+
+	// Schema and data as created by the former AutoMigrate-based setup
+	if err := database.AutoMigrate(&autoMigratedChart{}, &models.ChartSeries{}, &models.UserStar{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	chart := autoMigratedChart{Title: "Existing"}
+	if err := database.Create(&chart).Error; err != nil {
+		t.Fatalf("failed to create chart: %v", err)
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	var stored models.Chart
+	if err := database.First(&stored, chart.ID).Error; err != nil || stored.Title != "Existing" {
+		t.Errorf("expected existing chart to be kept, got %+v, %v", stored, err)
+	}
+}
+
+func TestMigrator_ConvertsAudienceCharacteristicsToCriteria(t *testing.T) {
+	ctx := context.Background()
+	database, migrator := newMigrator(t)
+
+	if _, err := migrator.To(ctx, 1); err != nil {
+		t.Fatalf("To(1) error = %v", err)
+	}
+	err := database.Exec("INSERT INTO audiences (gender, birth_country, age_group, daily_hours, no_of_purchases) VALUES ('Male', 'GR', '18-24', 3, 0)").Error
+	if err != nil {
+		t.Fatalf("failed to insert audience: %v", err)
+	}
+
+	if _, err := migrator.To(ctx, 2); err != nil {
+		t.Fatalf("To(2) error = %v", err)
+	}
+	var audience models.Audience
+	if err := database.First(&audience).Error; err != nil {
+		t.Fatalf("failed to read audience: %v", err)
+	}
+	criteria := audience.Criteria
+	if !slices.Equal(criteria.Genders, []string{"Male"}) || !slices.Equal(criteria.BirthCountries, []string{"GR"}) || !slices.Equal(criteria.AgeGroups, []string{"18-24"}) {
+		t.Errorf("expected single-valued criteria, got %+v", criteria)
+	}
+	if criteria.DailyHours == nil || *criteria.DailyHours != (models.NumericCriterion{Operator: models.OperatorEQ, Value: 3}) {
+		t.Errorf("expected dailyhours EQ 3, got %+v", criteria.DailyHours)
+	}
+	if criteria.NoOfPurchases == nil || criteria.NoOfPurchases.Value != 0 {
+		t.Errorf("expected noofpurchases EQ 0, got %+v", criteria.NoOfPurchases)
+	}
+
+	if _, err := migrator.To(ctx, 1); err != nil {
+		t.Fatalf("To(1) error = %v", err)
+	}
+	var row struct {
+		Gender     string
+		DailyHours int
+	}
+	if err := database.Table("audiences").Select("gender, daily_hours").Scan(&row).Error; err != nil {
+		t.Fatalf("failed to read reverted audience: %v", err)
+	}
+	if row.Gender != "Male" || row.DailyHours != 3 {
+		t.Errorf("expected the characteristics to be restored, got %+v", row)
+	}
+}
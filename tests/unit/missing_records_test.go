@@ -0,0 +1,72 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"platform-go-challenge/db"
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+	"platform-go-challenge/repository/memory"
+	"testing"
+)
+
+// backends returns fresh repositories of every storage backend
+func backends(t *testing.T) map[string]repository.Repositories {
+	database, err := db.Open("sqlite::memory:")
+	if err != nil {
+		t.Fatalf("failed to open SQLite: %v", err)
+	}
+	if err := db.Migrate(database); err != nil {
+		t.Fatalf("failed to migrate SQLite: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := database.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	return map[string]repository.Repositories{
+		"memory": memory.NewRepositories(repository.AssetDeleteCascade),
+		"sqlite": db.NewRepositories(database, repository.AssetDeleteCascade),
+	}
+}
+
+func TestRepositories_MissingRecords(t *testing.T) {
+	ctx := context.Background()
+	const missing = 42
+
+	for name, repos := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			chart := models.Chart{Title: "Chart"}
+			if err := repos.Charts.Create(ctx, &chart); err != nil {
+				t.Fatalf("failed to create chart: %v", err)
+			}
+
+			checks := map[string]error{
+				"update chart":    repos.Charts.Update(ctx, &models.Chart{ID: missing, Title: "Missing"}),
+				"update insight":  repos.Insights.Update(ctx, &models.Insight{ID: missing}),
+				"update audience": repos.Audiences.Update(ctx, &models.Audience{}),
+				"update star": repos.Stars.Update(ctx, &models.UserStar{
+					ID: missing, UserID: 1, Type: models.AssetTypeChart, AssetID: chart.ID,
+				}),
+				"delete chart":    repos.Charts.Delete(ctx, missing),
+				"delete insight":  repos.Insights.Delete(ctx, missing),
+				"delete audience": repos.Audiences.Delete(ctx, missing),
+				"delete star":     repos.Stars.Delete(ctx, missing),
+			}
+			for check, err := range checks {
+				if !errors.Is(err, repository.ErrNotFound) {
+					t.Errorf("%s: expected ErrNotFound, got %v", check, err)
+				}
+			}
+
+			// Updating a missing record does not create it
+			if _, err := repos.Charts.Get(ctx, missing); !errors.Is(err, repository.ErrNotFound) {
+				t.Errorf("expected missing chart to stay missing, got %v", err)
+			}
+			if stars, err := repos.Stars.AllByUser(ctx, 1); err != nil || len(stars) != 0 {
+				t.Errorf("expected no stars, got %v (%v)", stars, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package unit
+
+import (
+	"platform-go-challenge/models"
+	"reflect"
+	"testing"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	for _, id := range []uint{0, 1, 42, 1 << 40} {
+		cursor := models.EncodeCursor(id)
+		got, err := models.DecodeCursor(cursor)
+		if err != nil {
+			t.Errorf("DecodeCursor(%q) error = %v", cursor, err)
+			continue
+		}
+		if got != id {
+			t.Errorf("DecodeCursor(EncodeCursor(%d)) = %d", id, got)
+		}
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"Not base64", "!!!"},
+		{"Plain number", "42"},
+		{"Missing prefix", "NDI"},   // base64 of "42"
+		{"Non-numeric", "aWQ6YWJj"}, // base64 of "id:abc"
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := models.DecodeCursor(tt.cursor); err == nil {
+				t.Errorf("DecodeCursor(%q) expected error, got none", tt.cursor)
+			}
+		})
+	}
+}
+
+func TestNewPageRequest(t *testing.T) {
+	limit := func(n int) *int { return &n }
+
+	tests := []struct {
+		name    string
+		limit   *int
+		after   string
+		want    models.PageRequest
+		wantErr bool
+	}{
+		{"Defaults", nil, "", models.PageRequest{Limit: models.DefaultPageSize}, false},
+		{"Explicit limit", limit(5), "", models.PageRequest{Limit: 5}, false},
+		{"Zero limit", limit(0), "", models.PageRequest{Limit: 0}, false},
+		{"Capped limit", limit(1000), "", models.PageRequest{Limit: models.MaxPageSize}, false},
+		{"With cursor", limit(5), models.EncodeCursor(9), models.PageRequest{Limit: 5, After: 9}, false},
+		{"Negative limit", limit(-1), "", models.PageRequest{}, true},
+		{"Invalid cursor", limit(5), "nope", models.PageRequest{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := models.NewPageRequest(tt.limit, tt.after)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewPageRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NewPageRequest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	id := func(v uint) uint { return v }
+
+	tests := []struct {
+		name     string
+		rows     []uint
+		limit    int
+		after    uint
+		want     []uint
+		wantInfo models.PageInfo
+	}{
+		{"Empty", nil, 2, 0, []uint{}, models.PageInfo{}},
+		{"Partial page", []uint{3}, 2, 0, []uint{3}, models.PageInfo{EndCursor: models.EncodeCursor(3)}},
+		{"Exact page", []uint{3, 5}, 2, 0, []uint{3, 5}, models.PageInfo{EndCursor: models.EncodeCursor(5)}},
+		{"Extra row", []uint{3, 5, 8}, 2, 0, []uint{3, 5}, models.PageInfo{EndCursor: models.EncodeCursor(5), HasNextPage: true}},
+		{"Zero limit", []uint{3}, 0, 0, []uint{}, models.PageInfo{HasNextPage: true}},
+		{"Zero limit after a cursor", []uint{3}, 0, 2, []uint{}, models.PageInfo{EndCursor: models.EncodeCursor(2), HasNextPage: true}},
+		{"Past the end", nil, 2, 9, []uint{}, models.PageInfo{EndCursor: models.EncodeCursor(9)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := models.NewPage(tt.rows, models.PageRequest{Limit: tt.limit, After: tt.after}, id)
+			if !reflect.DeepEqual(page.Items, tt.want) {
+				t.Errorf("NewPage() items = %v, want %v", page.Items, tt.want)
+			}
+			if page.PageInfo != tt.wantInfo {
+				t.Errorf("NewPage() page info = %+v, want %+v", page.PageInfo, tt.wantInfo)
+			}
+		})
+	}
+}
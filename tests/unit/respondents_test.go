@@ -0,0 +1,569 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"platform-go-challenge/dataset"
+	"platform-go-challenge/models"
+	"platform-go-challenge/repository"
+	"platform-go-challenge/repository/cache"
+	"platform-go-challenge/repository/memory"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sampleRespondents are four respondents, two of which are males from GR
+func sampleRespondents() []models.Respondent {
+	return []models.Respondent{
+		{Gender: "Male", BirthCountry: "GR", AgeGroup: "18-24", DailyHours: 4, NoOfPurchases: 1},
+		{Gender: "Male", BirthCountry: "GR", AgeGroup: "25-34", DailyHours: 1.5, NoOfPurchases: 0},
+		{Gender: "Female", BirthCountry: "CY", AgeGroup: "25-34", DailyHours: 5, NoOfPurchases: 3},
+		{Gender: "Other", BirthCountry: "US", AgeGroup: "65+", DailyHours: 0, NoOfPurchases: 12},
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	input := "id,Gender,birth_country,age_group,social_hours,purchases\n" +
+		"7,Male,GR,18-24,4,1\n" +
+		"8, Female , CY, 25-34, 2.5, 0\n"
+
+	respondents, err := dataset.ReadCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadCSV() error = %v", err)
+	}
+	want := []models.Respondent{
+		{Gender: "Male", BirthCountry: "GR", AgeGroup: "18-24", DailyHours: 4, NoOfPurchases: 1},
+		{Gender: "Female", BirthCountry: "CY", AgeGroup: "25-34", DailyHours: 2.5, NoOfPurchases: 0},
+	}
+	if len(respondents) != len(want) {
+		t.Fatalf("expected %d respondents, got %+v", len(want), respondents)
+	}
+	for i := range want {
+		if respondents[i] != want[i] {
+			t.Errorf("respondent %d: expected %+v, got %+v", i, want[i], respondents[i])
+		}
+	}
+}
+
+func TestReadCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"Empty", "", "missing header row"},
+		{"Missing column", "gender,birthcountry,agegroup,dailyhours\n", "missing column noofpurchases"},
+		{"Invalid number", "gender,birthcountry,agegroup,dailyhours,noofpurchases\nMale,GR,18-24,four,1\n", "line 2: invalid dailyhours"},
+		{"Invalid value", "gender,birthcountry,agegroup,dailyhours,noofpurchases\nMale,GR,18-24,4,1\nMale,Greece,18-24,4,1\n", "line 3: invalid input: birthcountry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dataset.ReadCSV(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "respondents.jsonl")
+	content := `{"gender":"Male","birthcountry":"GR","agegroup":"18-24","dailyhours":4,"noofpurchases":1}
+
+{"gender":"Female","birthcountry":"CYP","agegroup":"65+","dailyhours":0.5,"noofpurchases":0}
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	respondents, err := dataset.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(respondents) != 2 || respondents[1].BirthCountry != "CY" {
+		t.Errorf("expected 2 respondents born in GR and CY, got %+v", respondents)
+	}
+
+	if _, err := dataset.Load(filepath.Join(dir, "respondents.parquet")); err == nil || !strings.Contains(err.Error(), "unsupported") {
+		t.Errorf("expected an unsupported format error, got %v", err)
+	}
+}
+
+func TestLoad_AlphaThreeCountries(t *testing.T) {
+	ctx := context.Background()
+	input := "gender,birthcountry,agegroup,dailyhours,noofpurchases\n" +
+		"Male,GRC,18-24,4,1\n" +
+		"Female,GR,25-34,2,0\n" +
+		"Other,CYP,65+,1,3\n"
+
+	respondents, err := dataset.ReadCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadCSV() error = %v", err)
+	}
+
+	for name, repos := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := repos.Respondents.Replace(ctx, slices.Clone(respondents)); err != nil {
+				t.Fatalf("Replace() error = %v", err)
+			}
+
+			// Respondents loaded with either form of a country match both
+			for _, country := range []string{"GR", "GRC"} {
+				audience := models.Audience{Criteria: models.AudienceCriteria{BirthCountries: []string{country}}}
+				if err := audience.Validate(); err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				size, err := repos.AudienceSizes.Size(ctx, audience)
+				if err != nil {
+					t.Fatalf("Size() error = %v", err)
+				}
+				if size.Count != 2 || size.Base != 3 {
+					t.Errorf("expected 2 of 3 respondents born in %s, got %+v", country, size)
+				}
+			}
+		})
+	}
+}
+
+func TestRespondentRepository(t *testing.T) {
+	ctx := context.Background()
+	malesFromGR, err := models.ParseExpression("gender = Male AND birth_country = GR")
+	if err != nil {
+		t.Fatalf("ParseExpression() error = %v", err)
+	}
+
+	for name, repos := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			empty, err := repos.Respondents.Version(ctx)
+			if err != nil {
+				t.Fatalf("Version() error = %v", err)
+			}
+
+			respondents := sampleRespondents()
+			if err := repos.Respondents.Replace(ctx, respondents); err != nil {
+				t.Fatalf("Replace() error = %v", err)
+			}
+			if respondents[0].ID == 0 {
+				t.Errorf("expected Replace() to set the IDs")
+			}
+			first, _ := repos.Respondents.Version(ctx)
+
+			matched, total, err := repos.Respondents.Count(ctx, malesFromGR)
+			if err != nil || matched != 2 || total != 4 {
+				t.Errorf("expected 2 of 4 respondents, got %d of %d, %v", matched, total, err)
+			}
+			if matched, total, _ := repos.Respondents.Count(ctx, nil); matched != 4 || total != 4 {
+				t.Errorf("expected nil to match all 4 respondents, got %d of %d", matched, total)
+			}
+
+			// Replacing with as many respondents still changes the version
+			if err := repos.Respondents.Replace(ctx, sampleRespondents()); err != nil {
+				t.Fatalf("Replace() error = %v", err)
+			}
+			second, _ := repos.Respondents.Version(ctx)
+			if empty == first || first == second {
+				t.Errorf("expected a new version per dataset, got %q, %q and %q", empty, first, second)
+			}
+			if _, total, _ := repos.Respondents.Count(ctx, nil); total != 4 {
+				t.Errorf("expected the dataset to be replaced, got %d respondents", total)
+			}
+		})
+	}
+}
+
+// countingSizes counts the sizes and comparisons computed by the wrapped
+// repository
+type countingSizes struct {
+	repository.AudienceSizeRepository
+	computed int
+	compared int
+}
+
+func (c *countingSizes) Size(ctx context.Context, audience models.Audience) (models.AudienceSize, error) {
+	c.computed++
+	return c.AudienceSizeRepository.Size(ctx, audience)
+}
+
+func (c *countingSizes) Compare(ctx context.Context, audiences []models.Audience) (models.AudienceComparison, error) {
+	c.compared++
+	return c.AudienceSizeRepository.Compare(ctx, audiences)
+}
+
+func TestCachedAudienceSizes(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories(repository.AssetDeleteCascade)
+	counter := &countingSizes{AudienceSizeRepository: repos.AudienceSizes}
+	repos.AudienceSizes = counter
+	repos = cache.Wrap(repos, cache.NewLRU(100), time.Minute)
+
+	if err := repos.Respondents.Replace(ctx, sampleRespondents()); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	audience := models.Audience{Criteria: models.AudienceCriteria{Genders: []string{"Male"}}}
+	if err := repos.Audiences.Create(ctx, &audience); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	size := func() models.AudienceSize {
+		t.Helper()
+		size, err := repos.AudienceSizes.Size(ctx, audience)
+		if err != nil {
+			t.Fatalf("Size() error = %v", err)
+		}
+		return size
+	}
+
+	if got := size(); got.Count != 2 || got.Base != 4 || got.Percentage != 50 {
+		t.Errorf("expected 2 of 4 respondents (50%%), got %+v", got)
+	}
+	size()
+	if counter.computed != 1 {
+		t.Errorf("expected the second size to be cached, got %d computations", counter.computed)
+	}
+
+	// A change of the expression that selects the same people keeps the version
+	audience.Expression = "gender = male"
+	if err := audience.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	audience.Criteria = models.AudienceCriteria{}
+	size()
+	if counter.computed != 1 {
+		t.Errorf("expected an equivalent definition to stay cached, got %d computations", counter.computed)
+	}
+
+	audience.Expression = "gender = Male AND social_hours > 2"
+	if got := size(); got.Count != 1 || counter.computed != 2 {
+		t.Errorf("expected a new definition to be sized again, got %+v after %d computations", got, counter.computed)
+	}
+
+	if err := repos.Respondents.Replace(ctx, sampleRespondents()[:2]); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if got := size(); got.Count != 1 || got.Base != 2 || counter.computed != 3 {
+		t.Errorf("expected a new dataset to be sized again, got %+v after %d computations", got, counter.computed)
+	}
+}
+
+// corruptCache returns an undecodable value for every key that was stored
+type corruptCache struct {
+	*cache.LRU
+}
+
+func (c corruptCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if _, found, err := c.LRU.Get(ctx, key); !found || err != nil {
+		return nil, found, err
+	}
+	return []byte("{not json"), true, nil
+}
+
+func TestCachedAudienceSizes_CorruptEntry(t *testing.T) {
+	ctx := context.Background()
+	var logs strings.Builder
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	repos := cache.Wrap(memory.NewRepositories(repository.AssetDeleteCascade), corruptCache{cache.NewLRU(100)}, time.Minute)
+	if err := repos.Respondents.Replace(ctx, sampleRespondents()); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	audience := models.Audience{ID: 1, Criteria: models.AudienceCriteria{Genders: []string{"Male"}}}
+
+	for range 2 {
+		size, err := repos.AudienceSizes.Size(ctx, audience)
+		if err != nil || size.Count != 2 {
+			t.Fatalf("expected the size to be computed again, got %+v, %v", size, err)
+		}
+	}
+	if !strings.Contains(logs.String(), "failed to decode size of audience 1: invalid character") {
+		t.Errorf("expected the decode error to be logged, got %q", logs.String())
+	}
+}
+
+func TestAudienceComparison(t *testing.T) {
+	// audience 1 has 6 respondents, audience 2 has 4, 3 of them in both, and
+	// audience 3 matches none
+	counts := [][]int64{
+		{6, 3, 0},
+		{3, 4, 0},
+		{0, 0, 0},
+	}
+	comparison := models.NewAudienceComparison([]uint{1, 2, 3}, counts, 10)
+
+	if got := comparison.Audiences[1]; got.AudienceID != 2 || got.Count != 4 || got.Percentage != 40 {
+		t.Errorf("expected audience 2 to have 4 respondents (40%%), got %+v", got)
+	}
+	want := models.AudienceOverlap{Intersection: 3, Union: 7, Jaccard: 3.0 / 7}
+	if comparison.Matrix[0][1] != want || comparison.Matrix[1][0] != want {
+		t.Errorf("expected overlap %+v, got %+v and %+v", want, comparison.Matrix[0][1], comparison.Matrix[1][0])
+	}
+	if got := comparison.Matrix[0][0]; got.Jaccard != 1 || got.Union != 6 {
+		t.Errorf("expected an audience to fully overlap itself, got %+v", got)
+	}
+	if got := comparison.Matrix[2][2]; got != (models.AudienceOverlap{}) {
+		t.Errorf("expected empty audiences not to overlap, got %+v", got)
+	}
+}
+
+func TestValidateComparedAudiences(t *testing.T) {
+	tests := []struct {
+		name       string
+		ids        []uint
+		wantFields []string
+	}{
+		{"Pair", []uint{1, 2}, nil},
+		{"Single", []uint{1}, []string{"ids"}},
+		{"Too many", []uint{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, []string{"ids"}},
+		{"Duplicates", []uint{1, 2, 1, 2}, []string{"ids[2]", "ids[3]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := models.ValidateComparedAudiences(tt.ids)
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			var fields []string
+			if modelErr := models.AsError(err); modelErr != nil {
+				for _, field := range modelErr.Fields {
+					fields = append(fields, field.Field)
+				}
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("expected invalid fields %v, got %v", tt.wantFields, err)
+			}
+		})
+	}
+}
+
+func TestRespondentRepository_CountOverlaps(t *testing.T) {
+	ctx := context.Background()
+	males, _ := models.ParseExpression("gender = Male")
+	greeks, _ := models.ParseExpression("birth_country = GR")
+	heavyUsers, _ := models.ParseExpression("social_hours >= 4")
+
+	for name, repos := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := repos.Respondents.Replace(ctx, sampleRespondents()); err != nil {
+				t.Fatalf("Replace() error = %v", err)
+			}
+
+			counts, total, err := repos.Respondents.CountOverlaps(ctx, []models.Expression{males, greeks, heavyUsers, nil})
+			if err != nil {
+				t.Fatalf("CountOverlaps() error = %v", err)
+			}
+			want := [][]int64{
+				{2, 2, 1, 2},
+				{2, 2, 1, 2},
+				{1, 1, 2, 2},
+				{2, 2, 2, 4},
+			}
+			if total != 4 || fmt.Sprint(counts) != fmt.Sprint(want) {
+				t.Errorf("expected %v of 4 respondents, got %v of %d", want, counts, total)
+			}
+		})
+	}
+}
+
+func TestCachedAudienceComparisons(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories(repository.AssetDeleteCascade)
+	counter := &countingSizes{AudienceSizeRepository: repos.AudienceSizes}
+	repos.AudienceSizes = counter
+	repos = cache.Wrap(repos, cache.NewLRU(100), time.Minute)
+
+	if err := repos.Respondents.Replace(ctx, sampleRespondents()); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	audiences := []models.Audience{
+		{Criteria: models.AudienceCriteria{Genders: []string{"Male"}}},
+		{Criteria: models.AudienceCriteria{BirthCountries: []string{"GR", "CY"}}},
+	}
+	for i := range audiences {
+		if err := repos.Audiences.Create(ctx, &audiences[i]); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	compare := func() models.AudienceComparison {
+		t.Helper()
+		comparison, err := repos.AudienceSizes.Compare(ctx, audiences)
+		if err != nil {
+			t.Fatalf("Compare() error = %v", err)
+		}
+		return comparison
+	}
+
+	got := compare()
+	if overlap := got.Matrix[0][1]; overlap.Intersection != 2 || overlap.Union != 3 {
+		t.Errorf("expected 2 of 3 respondents in both audiences, got %+v", overlap)
+	}
+	if got.Audiences[0].AudienceVersion == "" || got.Audiences[1].DatasetVersion != got.DatasetVersion {
+		t.Errorf("expected the versions of the audiences and the dataset, got %+v", got.Audiences)
+	}
+	compare()
+	if counter.compared != 1 {
+		t.Errorf("expected the second comparison to be cached, got %d computations", counter.compared)
+	}
+
+	// The order of the audiences is part of the comparison
+	audiences[0], audiences[1] = audiences[1], audiences[0]
+	if got := compare(); got.Audiences[0].Count != 3 || counter.compared != 2 {
+		t.Errorf("expected the reordered audiences to be compared again, got %+v after %d computations", got.Audiences, counter.compared)
+	}
+
+	audiences[0].Criteria.BirthCountries = []string{"GR"}
+	if got := compare(); got.Matrix[0][1].Intersection != 2 || got.Audiences[0].Count != 2 || counter.compared != 3 {
+		t.Errorf("expected an updated audience to be compared again, got %+v after %d computations", got, counter.compared)
+	}
+}
+
+func TestChartGenerator(t *testing.T) {
+	ctx := context.Background()
+
+	for name, repos := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := repos.Respondents.Replace(ctx, sampleRespondents()); err != nil {
+				t.Fatalf("Replace() error = %v", err)
+			}
+			audience := models.Audience{Criteria: models.AudienceCriteria{AgeGroups: []string{"18-24", "25-34"}}}
+			if err := repos.Audiences.Create(ctx, &audience); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			chart, err := repos.ChartGenerator.Generate(ctx, audience.ID, models.DimensionBirthCountry)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			stored, err := repos.Charts.Get(ctx, chart.ID)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if !slices.Equal(stored.Labels, []string{"GR", "CY"}) || !slices.Equal(stored.Series[0].Points, []float64{2, 1}) {
+				t.Errorf("expected 2 respondents from GR and 1 from CY, got %v and %+v", stored.Labels, stored.Series)
+			}
+			provenance := stored.Provenance
+			if provenance == nil || provenance.AudienceID != audience.ID || provenance.Dimension != models.DimensionBirthCountry || provenance.GeneratedAt.IsZero() {
+				t.Fatalf("expected the provenance of the chart, got %+v", provenance)
+			}
+
+			// Refreshing follows the audience and the dataset but keeps the titles
+			stored.Title = "Young people by country"
+			if err := repos.Charts.Update(ctx, &stored); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+			audience.Criteria.AgeGroups = []string{"18-24", "25-34", "65+"}
+			if err := repos.Audiences.Update(ctx, &audience); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+			if err := repos.Respondents.Replace(ctx, sampleRespondents()[1:]); err != nil {
+				t.Fatalf("Replace() error = %v", err)
+			}
+
+			refreshed, err := repos.ChartGenerator.Refresh(ctx, chart.ID)
+			if err != nil {
+				t.Fatalf("Refresh() error = %v", err)
+			}
+			if refreshed.Title != "Young people by country" || !slices.Equal(refreshed.Labels, []string{"CY", "GR", "US"}) {
+				t.Errorf("expected the titles kept and new labels, got %q and %v", refreshed.Title, refreshed.Labels)
+			}
+			if p := refreshed.Provenance; p.AudienceVersion == provenance.AudienceVersion || p.DatasetVersion == provenance.DatasetVersion {
+				t.Errorf("expected new versions, got %+v after %+v", p, provenance)
+			}
+			if stored, _ := repos.Charts.Get(ctx, chart.ID); !slices.Equal(stored.Series[0].Points, []float64{1, 1, 1}) {
+				t.Errorf("expected the refreshed series to be stored, got %+v", stored.Series)
+			}
+		})
+	}
+}
+
+// deletingSourceLinks deletes the source audience of every link before
+// linking, as if it was deleted while the chart was generated
+type deletingSourceLinks struct {
+	repository.LinkRepository
+	audiences repository.AudienceRepository
+}
+
+func (l deletingSourceLinks) Link(ctx context.Context, link models.AssetLink) (bool, error) {
+	if err := l.audiences.Delete(ctx, link.SourceID); err != nil {
+		return false, err
+	}
+	return l.LinkRepository.Link(ctx, link)
+}
+
+func TestChartGenerator_DeletedAudience(t *testing.T) {
+	ctx := context.Background()
+
+	for name, repos := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := repos.Respondents.Replace(ctx, sampleRespondents()); err != nil {
+				t.Fatalf("Replace() error = %v", err)
+			}
+			audience := models.Audience{}
+			if err := repos.Audiences.Create(ctx, &audience); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			repos.Links = deletingSourceLinks{LinkRepository: repos.Links, audiences: repos.Audiences}
+			generator := repository.NewChartGenerator(repos)
+			if _, err := generator.Generate(ctx, audience.ID, models.DimensionGender); !errors.Is(err, repository.ErrAssetNotFound) {
+				t.Fatalf("expected the link to a deleted audience to fail, got %v", err)
+			}
+
+			// The chart is not kept without its link
+			charts, err := repos.Charts.List(ctx, models.PageRequest{Limit: models.MaxPageSize})
+			if err != nil || len(charts.Items) != 0 {
+				t.Errorf("expected no charts, got %+v, %v", charts.Items, err)
+			}
+		})
+	}
+}
+
+func TestChartGenerator_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	for name, repos := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if _, err := repos.ChartGenerator.Generate(ctx, 42, models.DimensionGender); !errors.Is(err, repository.ErrNotFound) {
+				t.Errorf("expected a missing audience to be not found, got %v", err)
+			}
+			if _, err := repos.ChartGenerator.Refresh(ctx, 42); !errors.Is(err, repository.ErrNotFound) {
+				t.Errorf("expected a missing chart to be not found, got %v", err)
+			}
+
+			manual := models.Chart{Title: "Sales"}
+			if err := repos.Charts.Create(ctx, &manual); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if _, err := repos.ChartGenerator.Refresh(ctx, manual.ID); models.AsError(err) == nil || models.AsError(err).Kind != models.ErrorConflict {
+				t.Errorf("expected a chart that was not generated to conflict, got %v", err)
+			}
+
+			audience := models.Audience{}
+			if err := repos.Audiences.Create(ctx, &audience); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			chart, err := repos.ChartGenerator.Generate(ctx, audience.ID, models.DimensionGender)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if err := repos.Audiences.Delete(ctx, audience.ID); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if _, err := repos.ChartGenerator.Refresh(ctx, chart.ID); models.AsError(err) == nil || models.AsError(err).Kind != models.ErrorConflict {
+				t.Errorf("expected a chart of a deleted audience to conflict, got %v", err)
+			}
+		})
+	}
+}
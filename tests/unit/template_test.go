@@ -0,0 +1,137 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"platform-go-challenge/models"
+	"strings"
+	"testing"
+)
+
+func TestParseInsightTemplate(t *testing.T) {
+	template, err := models.ParseInsightTemplate("{{PCT audience:12 where social_hours > 3}} of millennials, {{count}} in total")
+	if err != nil {
+		t.Fatalf("ParseInsightTemplate() error = %v", err)
+	}
+
+	placeholders := template.Placeholders()
+	if len(placeholders) != 2 {
+		t.Fatalf("expected 2 placeholders, got %+v", placeholders)
+	}
+	if p := placeholders[0]; p.Metric != models.MetricPct || p.AudienceID != 12 || p.Where == nil || p.Where.String() != "social_hours > 3" {
+		t.Errorf("unexpected first placeholder %+v", p)
+	}
+	if p := placeholders[1]; p.Metric != models.MetricCount || p.AudienceID != 0 || p.Where != nil {
+		t.Errorf("unexpected second placeholder %+v", p)
+	}
+
+	text, err := template.Render(func(p models.Placeholder) (string, error) { return string(p.Metric), nil })
+	if err != nil || text != "pct of millennials, count in total" {
+		t.Errorf("Render() = %q, %v", text, err)
+	}
+}
+
+func TestParseInsightTemplate_Errors(t *testing.T) {
+	tests := []struct {
+		template string
+		wantErr  string
+	}{
+		{"Only {{count", `at position 6: placeholder is not closed with "}}"`},
+		{"{{}}", "at position 3: expected a metric"},
+		{"{{ total }}", `at position 4: unknown metric "total"`},
+		{"{{pct audience:x}}", `at position 7: invalid audience "audience:x"`},
+		{"{{pct audience:0}}", `at position 7: invalid audience "audience:0"`},
+		{"{{pct audience:1 with}}", `at position 18: unexpected "with"`},
+		{"{{pct where}}", "at position 12: expected a condition after where"},
+		{"é {{pct where social_hours >}}", "at position 29: expected a value"},
+		{"{{pct where social_hours = NaN}}", "at position 28: social_hours must be a number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			_, err := models.ParseInsightTemplate(tt.template)
+			var syntaxErr *models.TemplateSyntaxError
+			if !errors.As(err, &syntaxErr) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPlaceholder_Format(t *testing.T) {
+	count := models.Placeholder{Metric: models.MetricCount}
+	pct := models.Placeholder{Metric: models.MetricPct}
+
+	if got := count.Format(1250, 5000); got != "1250" {
+		t.Errorf("expected 1250, got %q", got)
+	}
+	if got := pct.Format(2, 3); got != "67%" {
+		t.Errorf("expected 67%%, got %q", got)
+	}
+	if got := pct.Format(0, 0); got != models.PlaceholderUnavailable {
+		t.Errorf("expected a percentage of nobody to be unavailable, got %q", got)
+	}
+}
+
+func TestInsight_ValidateTemplate(t *testing.T) {
+	insight := models.Insight{Text: "{{pct audience:1 where gender = Robot}} of people"}
+	err := insight.Validate()
+	if modelErr := models.AsError(err); modelErr == nil || len(modelErr.Fields) != 1 || modelErr.Fields[0].Field != "text" {
+		t.Errorf("expected an invalid text, got %v", err)
+	}
+}
+
+func TestInsightRenderer(t *testing.T) {
+	ctx := context.Background()
+
+	for name, repos := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := repos.Respondents.Replace(ctx, sampleRespondents()); err != nil {
+				t.Fatalf("Replace() error = %v", err)
+			}
+			greeks := models.Audience{Criteria: models.AudienceCriteria{BirthCountries: []string{"GR"}}}
+			if err := repos.Audiences.Create(ctx, &greeks); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			render := func(text string) string {
+				t.Helper()
+				rendered, err := repos.InsightRenderer.Render(ctx, models.Insight{Text: text})
+				if err != nil {
+					t.Fatalf("Render() error = %v", err)
+				}
+				return rendered
+			}
+
+			tests := []struct {
+				template string
+				want     string
+			}{
+				{"{{count}} respondents", "4 respondents"},
+				{"{{count audience:%d}} Greeks", "2 Greeks"},
+				{"{{pct audience:%d}} are Greek", "50% are Greek"},
+				{"{{pct audience:%d where social_hours > 3}} of Greeks", "50% of Greeks"},
+				{"{{count audience:%d where purchases > 0}} bought", "1 bought"},
+				{"{{pct where purchases >= 1}} bought", "75% bought"},
+				{"No placeholders", "No placeholders"},
+				{"{{pct audience:999}} of a deleted audience", "n/a of a deleted audience"},
+				{"{{not a template", "{{not a template"},
+			}
+			for _, tt := range tests {
+				template := strings.ReplaceAll(tt.template, "%d", fmt.Sprint(greeks.ID))
+				if got := render(template); got != tt.want {
+					t.Errorf("%s: expected %q, got %q", tt.template, tt.want, got)
+				}
+			}
+
+			// Numbers follow the respondents
+			if err := repos.Respondents.Replace(ctx, sampleRespondents()[:2]); err != nil {
+				t.Fatalf("Replace() error = %v", err)
+			}
+			if got := render(fmt.Sprintf("{{pct audience:%d}}", greeks.ID)); got != "100%" {
+				t.Errorf("expected the new dataset to be used, got %q", got)
+			}
+		})
+	}
+}
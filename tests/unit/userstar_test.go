@@ -105,3 +105,32 @@ func TestAssetType_Scan(t *testing.T) {
 		})
 	}
 }
+
+func TestParseAssetType(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    models.AssetType
+		wantErr bool
+	}{
+		{"Exact Chart", "Chart", models.AssetTypeChart, false},
+		{"Lowercase chart", "chart", models.AssetTypeChart, false},
+		{"Uppercase INSIGHT", "INSIGHT", models.AssetTypeInsight, false},
+		{"Mixed audience", "aUdIeNcE", models.AssetTypeAudience, false},
+		{"Empty", "", "", true},
+		{"Plural", "charts", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := models.ParseAssetType(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseAssetType() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseAssetType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package unit
+
+import (
+	"errors"
+	"platform-go-challenge/models"
+	"strings"
+	"testing"
+)
+
+// invalidFields returns the invalid fields of a validation error, or nil
+func invalidFields(t *testing.T, err error) []string {
+	if err == nil {
+		return nil
+	}
+	var domainErr *models.Error
+	if !errors.As(err, &domainErr) || domainErr.Kind != models.ErrorValidation {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	names := make([]string, len(domainErr.Fields))
+	for i, field := range domainErr.Fields {
+		names[i] = field.Field
+	}
+	return names
+}
+
+func TestAudience_Validate(t *testing.T) {
+	valid := models.Audience{Criteria: models.AudienceCriteria{
+		Genders:        []string{"Male", "Female"},
+		BirthCountries: []string{"GR", "CY"},
+		AgeGroups:      []string{"18-24", "25-34"},
+		DailyHours:     &models.NumericCriterion{Operator: models.OperatorGT, Value: 3},
+		NoOfPurchases:  &models.NumericCriterion{Operator: models.OperatorGTE, Value: 2},
+	}}
+	upper := func(value float64) *float64 { return &value }
+
+	tests := []struct {
+		name       string
+		change     func(*models.AudienceCriteria)
+		wantFields []string
+	}{
+		{"Valid", func(c *models.AudienceCriteria) {}, nil},
+		{"No criteria", func(c *models.AudienceCriteria) { *c = models.AudienceCriteria{} }, nil},
+		{"Alpha-3 country", func(c *models.AudienceCriteria) { c.BirthCountries = []string{"USA"} }, nil},
+		{"Oldest bucket", func(c *models.AudienceCriteria) { c.AgeGroups = []string{"65+"} }, nil},
+		{"Range", func(c *models.AudienceCriteria) {
+			c.DailyHours = &models.NumericCriterion{Operator: models.OperatorBetween, Value: 1, Upper: upper(24)}
+		}, nil},
+		{"Unknown gender", func(c *models.AudienceCriteria) { c.Genders = []string{"Male", "male"} }, []string{"criteria.genders[1]"}},
+		{"Duplicate gender", func(c *models.AudienceCriteria) { c.Genders = []string{"Male", "Male"} }, []string{"criteria.genders[1]"}},
+		{"Same country in both forms", func(c *models.AudienceCriteria) { c.BirthCountries = []string{"GR", "GRC"} }, []string{"criteria.birthcountries[1]"}},
+		{"Country name", func(c *models.AudienceCriteria) { c.BirthCountries = []string{"Greece"} }, []string{"criteria.birthcountries[0]"}},
+		{"Unknown age group", func(c *models.AudienceCriteria) { c.AgeGroups = []string{"20-30"} }, []string{"criteria.agegroups[0]"}},
+		{"Unknown operator", func(c *models.AudienceCriteria) { c.DailyHours.Operator = "NE" }, []string{"criteria.dailyhours.operator"}},
+		{"Too many hours", func(c *models.AudienceCriteria) { c.DailyHours.Value = 25 }, []string{"criteria.dailyhours.value"}},
+		{"Negative purchases", func(c *models.AudienceCriteria) { c.NoOfPurchases.Value = -1 }, []string{"criteria.noofpurchases.value"}},
+		{"Range without upper", func(c *models.AudienceCriteria) { c.DailyHours.Operator = models.OperatorBetween }, []string{"criteria.dailyhours.upper"}},
+		{"Inverted range", func(c *models.AudienceCriteria) {
+			c.DailyHours = &models.NumericCriterion{Operator: models.OperatorBetween, Value: 5, Upper: upper(2)}
+		}, []string{"criteria.dailyhours.upper"}},
+		{"Upper without range", func(c *models.AudienceCriteria) { c.NoOfPurchases.Upper = upper(5) }, []string{"criteria.noofpurchases.upper"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audience := models.Audience{Criteria: valid.Criteria.Clone()}
+			tt.change(&audience.Criteria)
+			got := invalidFields(t, audience.Validate())
+			if strings.Join(got, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("expected invalid fields %v, got %v", tt.wantFields, got)
+			}
+		})
+	}
+}
+
+func TestAudience_ValidateCountryCodes(t *testing.T) {
+	audience := models.Audience{Criteria: models.AudienceCriteria{BirthCountries: []string{"GRC", "CY", "UNK"}}}
+	if err := audience.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if got := strings.Join(audience.Criteria.BirthCountries, ","); got != "GR,CY,XK" {
+		t.Errorf("expected alpha-2 codes, got %s", got)
+	}
+
+	// Both forms of a country define the same audience
+	alpha2 := models.Audience{Criteria: models.AudienceCriteria{BirthCountries: []string{"GR", "CY", "XK"}}}
+	if err := alpha2.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	version, _ := audience.Version()
+	if alpha2Version, _ := alpha2.Version(); version != alpha2Version {
+		t.Errorf("expected equal versions, got %s and %s", version, alpha2Version)
+	}
+}
+
+func TestCountryCode(t *testing.T) {
+	tests := []struct {
+		code   string
+		want   string
+		wantOK bool
+	}{
+		{"GR", "GR", true},
+		{"GRC", "GR", true},
+		{"USA", "US", true},
+		{"UNK", "XK", true},
+		{"gr", "", false},
+		{"Greece", "", false},
+		{"ZZZ", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, ok := models.CountryCode(tt.code)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("CountryCode(%q) = %q, %v, want %q, %v", tt.code, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNumericCriterion_Matches(t *testing.T) {
+	upper := 5.0
+	tests := []struct {
+		criterion models.NumericCriterion
+		value     float64
+		want      bool
+	}{
+		{models.NumericCriterion{Operator: models.OperatorEQ, Value: 3}, 3, true},
+		{models.NumericCriterion{Operator: models.OperatorGT, Value: 3}, 3, false},
+		{models.NumericCriterion{Operator: models.OperatorGTE, Value: 3}, 3, true},
+		{models.NumericCriterion{Operator: models.OperatorLT, Value: 3}, 2.5, true},
+		{models.NumericCriterion{Operator: models.OperatorLTE, Value: 3}, 4, false},
+		{models.NumericCriterion{Operator: models.OperatorBetween, Value: 1, Upper: &upper}, 5, true},
+		{models.NumericCriterion{Operator: models.OperatorBetween, Value: 1, Upper: &upper}, 6, false},
+		{models.NumericCriterion{Operator: models.OperatorBetween, Value: 1}, 2, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.criterion.Matches(tt.value); got != tt.want {
+			t.Errorf("%+v.Matches(%g) = %v, want %v", tt.criterion, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestInsight_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{"Valid", "Revenue grew", false},
+		{"Empty", "", true},
+		{"At the limit", strings.Repeat("é", 10000), false},
+		{"Too long", strings.Repeat("a", 10001), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			insight := models.Insight{Text: tt.text}
+			if err := insight.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Insight.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_Messages(t *testing.T) {
+	chart := models.Chart{Title: strings.Repeat("t", 201), Labels: make([]string, 1001)}
+	err := chart.Validate()
+
+	var domainErr *models.Error
+	if !errors.As(err, &domainErr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	want := map[string]string{
+		"title":  "must be at most 200 characters long",
+		"labels": "must have at most 1000 items",
+	}
+	for _, field := range domainErr.Fields {
+		if message, ok := want[field.Field]; ok && message != field.Message {
+			t.Errorf("%s: expected message %q, got %q", field.Field, message, field.Message)
+		}
+		delete(want, field.Field)
+	}
+	if len(want) > 0 {
+		t.Errorf("expected errors for %v, got %v", want, domainErr.Fields)
+	}
+}